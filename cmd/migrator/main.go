@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/uptrace/bun/migrate"
-	migrations2 "stocks-api/migrations"
+	"stocks-api/support/config"
 	"stocks-api/support/db"
 )
 
@@ -34,6 +34,8 @@ func init() {
 func main() {
 	var rootCmd = &cobra.Command{}
 
+	config.RegisterFlags(rootCmd.PersistentFlags())
+
 	// Flags registration.
 	for _, fn := range functionsRegistrar() {
 		if fn.flag != "" {
@@ -43,7 +45,9 @@ func main() {
 		rootCmd.AddCommand(fn.fn)
 	}
 
-	rootCmd.Execute()
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
 
 func functionsRegistrar() [4]fnFlags {
@@ -53,7 +57,7 @@ func functionsRegistrar() [4]fnFlags {
 		Use:   "init",
 		Short: "Initialises the bun_migration tables",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			migrator, err := spinUpDb()
+			migrator, err := spinUpDb(cmd)
 			if err != nil {
 				return errors.WithStack(err)
 			}
@@ -66,7 +70,7 @@ func functionsRegistrar() [4]fnFlags {
 		Use:   "migrate",
 		Short: "Migrate applies migrations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			migrator, err := spinUpDb()
+			migrator, err := spinUpDb(cmd)
 			if err != nil {
 				return errors.WithStack(err)
 			}
@@ -79,7 +83,7 @@ func functionsRegistrar() [4]fnFlags {
 		Use:   "rollback",
 		Short: "Rollback handles rollbacks",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			migrator, err := spinUpDb()
+			migrator, err := spinUpDb(cmd)
 			if err != nil {
 				return errors.WithStack(err)
 			}
@@ -98,7 +102,7 @@ func functionsRegistrar() [4]fnFlags {
 				return errors.WithStack(err)
 			}
 
-			migrator, err := spinUpDb()
+			migrator, err := spinUpDb(cmd)
 			if err != nil {
 				return errors.WithStack(err)
 			}
@@ -115,21 +119,24 @@ func functionsRegistrar() [4]fnFlags {
 	}
 }
 
-func spinUpDb() (*db.Migrator, error) {
+// spinUpDb connects to the database of the config.
+func spinUpDb(cmd *cobra.Command) (*db.Migrator, error) {
 	logger := logrus.New()
 
-	conn, err := db.NewConnection()
+	cfg, err := config.Load(cmd.Flags())
 	if err != nil {
 		return nil, err
 	}
 
-	instance := db.NewInstance(conn, logger)
-	logger.Log(logrus.InfoLevel, "DB conn: ", instance.Health())
-
-	migrations := migrate.NewMigrations(migrate.WithMigrationsDirectory("migrations"))
-	migrations.Discover(migrations2.MigrationFilesScan())
+	conn, err := db.NewConnection(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
 
-	migrator := migrate.NewMigrator(instance.Base, migrations)
+	instance := db.NewInstance(conn, logger)
+	if err := instance.Health(context.Background()); err != nil {
+		return nil, err
+	}
 
-	return db.NewMigrator(logger, instance, migrator), nil
+	return db.FromInstance(logger, instance)
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"stocks-api/module/services"
+)
+
+const (
+	_roleFlag = "role"
+	_ttlFlag  = "ttl"
+	_idFlag   = "id"
+)
+
+// apiKeyCmd groups the commands managing the API keys of the service clients.
+func apiKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Mints, lists and revokes API keys",
+	}
+
+	cmd.AddCommand(mintApiKeyCmd(), listApiKeysCmd(), revokeApiKeyCmd())
+
+	return cmd
+}
+
+// mintApiKeyCmd adds a new API key, printing it once.
+func mintApiKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "mint",
+		Example: fmt.Sprintf("apikey mint --%s billing --%s stock:read --%s 2160h", _nameFlag, _roleFlag, _ttlFlag),
+		Short:   "Mints a new API key, only ever printed here",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := tenantContext(cmd)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			logger := logrus.New()
+
+			name, _ := cmd.Flags().GetString(_nameFlag)
+			roles, _ := cmd.Flags().GetStringSlice(_roleFlag)
+			ttl, _ := cmd.Flags().GetDuration(_ttlFlag)
+
+			instance, err := spinUpDb(cmd, logger)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			key, plaintext, err := services.NewApiKeyService(logger, instance).Mint(ctx, name, roles, ttl)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Minted API key %s (%s)\n", key.ID, key.Name)
+			fmt.Fprintf(out, "%s\n", plaintext)
+			fmt.Fprintln(out, "Store it now, it can't be shown again.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(_nameFlag, "", "name of the client the key is minted for")
+	cmd.Flags().StringSlice(_roleFlag, nil, "role granted to the key, repeatable")
+	cmd.Flags().Duration(_ttlFlag, 0, "validity of the key, it never expires if omitted")
+	cmd.MarkFlagRequired(_nameFlag)
+
+	return cmd
+}
+
+// listApiKeysCmd lists the API keys, without their secrets.
+func listApiKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Lists the API keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			logger := logrus.New()
+
+			instance, err := spinUpDb(cmd, logger)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			keys, err := services.NewApiKeyService(logger, instance).GetAll(ctx)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tTENANT\tPREFIX\tROLES\tEXPIRES\tSTATUS")
+
+			now := time.Now()
+			for _, key := range keys {
+				expires, state := "never", "active"
+				if key.ExpiresAt != nil {
+					expires = key.ExpiresAt.Format(time.RFC3339)
+				}
+
+				if !key.Usable(now) {
+					state = "inactive"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					key.ID, key.Name, key.TenantID, key.Prefix, strings.Join(key.Roles, ","), expires, state)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+// revokeApiKeyCmd revokes an API key, it can't be used from then on.
+func revokeApiKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "revoke",
+		Example: fmt.Sprintf("apikey revoke --%s 0b6b9c44-3b0e-4b8e-9a47-0e1f0a3c5d11", _idFlag),
+		Short:   "Revokes an API key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			logger := logrus.New()
+
+			id, _ := cmd.Flags().GetString(_idFlag)
+
+			instance, err := spinUpDb(cmd, logger)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if err := services.NewApiKeyService(logger, instance).Revoke(ctx, id); err != nil {
+				return errors.WithStack(err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Revoked API key %s\n", id)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(_idFlag, "", "id of the key to revoke")
+	cmd.MarkFlagRequired(_idFlag)
+
+	return cmd
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"stocks-api/support/config"
+)
+
+// configCmd groups the commands inspecting the configuration of the servers.
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspects the configuration",
+	}
+
+	cmd.AddCommand(printConfigCmd())
+
+	return cmd
+}
+
+// printConfigCmd prints the config loaded from the file, the env params and the flags, the secrets redacted.
+func printConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "print",
+		Example: fmt.Sprintf("config print --%s config.yaml --server.port 8080", config.FileFlag),
+		Short:   "Prints the validated config the servers run with, the secrets redacted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(cmd.Flags())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			return config.Print(cmd.OutOrStdout(), cfg)
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"stocks-api/module/entities"
+	"stocks-api/module/entities/filters"
+	"stocks-api/module/exporters"
+	"stocks-api/module/services"
+)
+
+const (
+	_formatFlag      = "format"
+	_outFlag         = "out"
+	_nameFlag        = "name"
+	_minQuantityFlag = "min-quantity"
+	_maxQuantityFlag = "max-quantity"
+)
+
+// exportCmd streams the stock items matching the filters to a local file.
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Example: fmt.Sprintf("export --%s parquet --%s stocks.parquet --%s 1", _formatFlag, _outFlag, _minQuantityFlag),
+		Short:   "Exports stock items as CSV, NDJSON or Parquet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := tenantContext(cmd)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			logger := logrus.New()
+
+			rawFormat, _ := cmd.Flags().GetString(_formatFlag)
+			path, _ := cmd.Flags().GetString(_outFlag)
+
+			format, err := exporters.ParseFormat(rawFormat)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			filter := &filters.StockFilter{}
+			filter.Name, _ = cmd.Flags().GetString(_nameFlag)
+
+			if cmd.Flags().Changed(_minQuantityFlag) {
+				min, _ := cmd.Flags().GetInt64(_minQuantityFlag)
+				filter.MinQuantity = &min
+			}
+
+			if cmd.Flags().Changed(_maxQuantityFlag) {
+				max, _ := cmd.Flags().GetInt64(_maxQuantityFlag)
+				filter.MaxQuantity = &max
+			}
+
+			instance, err := spinUpDb(cmd, logger)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			f, err := os.Create(path)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer f.Close()
+
+			encoder, err := exporters.NewEncoder(format, f)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			count := 0
+			service := services.NewStockService(logger, instance, ctx)
+
+			err = service.Export(ctx, filter, func(stock *entities.Stock) error {
+				count++
+				return encoder.Encode(stock)
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if err := encoder.Close(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d stock items to %s\n", count, path)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(_formatFlag, string(exporters.CSV), "export format: csv, ndjson or parquet")
+	cmd.Flags().String(_outFlag, "", "path of the file to write")
+	cmd.Flags().String(_nameFlag, "", "only export items whose name contains the value")
+	cmd.Flags().Int64(_minQuantityFlag, 0, "only export items with at least this quantity")
+	cmd.Flags().Int64(_maxQuantityFlag, 0, "only export items with at most this quantity")
+	cmd.MarkFlagRequired(_outFlag)
+
+	return cmd
+}
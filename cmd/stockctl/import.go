@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"stocks-api/module/importers"
+)
+
+const (
+	_fileFlag    = "file"
+	_sheetFlag   = "sheet"
+	_mapFlag     = "map"
+	_dryRunFlag  = "dry-run"
+	_rejectsFlag = "rejects"
+)
+
+// importCmd upserts stock items, matched by id when the file has one and by name otherwise, from a CSV or XLSX file.
+func importCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import",
+		Example: fmt.Sprintf("import --%s stocks.xlsx --%s name=Product,quantity=Qty --%s", _fileFlag, _mapFlag, _dryRunFlag),
+		Short:   "Imports stock items from a CSV or XLSX file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := tenantContext(cmd)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			logger := logrus.New()
+
+			path, _ := cmd.Flags().GetString(_fileFlag)
+			sheetName, _ := cmd.Flags().GetString(_sheetFlag)
+			rawMapping, _ := cmd.Flags().GetString(_mapFlag)
+			dryRun, _ := cmd.Flags().GetBool(_dryRunFlag)
+			rejectsPath, _ := cmd.Flags().GetString(_rejectsFlag)
+
+			mapping, err := importers.ParseMapping(rawMapping)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			sheet, err := importers.ReadFile(path, sheetName)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			instance, err := spinUpDb(cmd, logger)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			importer := importers.NewStockImporter(logger, instance, ctx)
+
+			report, err := importer.Plan(ctx, sheet, mapping)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if !dryRun {
+				if err := importer.Apply(ctx, report); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+
+			printReport(cmd, report, dryRun)
+
+			if rejectsPath != "" && report.Count(importers.Reject) > 0 {
+				return writeRejects(rejectsPath, report)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(_fileFlag, "", "path to the .csv or .xlsx file")
+	cmd.Flags().String(_sheetFlag, "", "the XLSX sheet to read, defaults to the first one")
+	cmd.Flags().String(_mapFlag, "", "column mapping as field=column pairs, fields: id, name, quantity")
+	cmd.Flags().Bool(_dryRunFlag, false, "only report what would change")
+	cmd.Flags().String(_rejectsFlag, "", "path of the CSV file the rejected rows are written to")
+	cmd.MarkFlagRequired(_fileFlag)
+
+	return cmd
+}
+
+func printReport(cmd *cobra.Command, report *importers.Report, dryRun bool) {
+	out := cmd.OutOrStdout()
+
+	for _, row := range report.Rows {
+		switch row.Action {
+		case importers.Create:
+			fmt.Fprintf(out, "line %d: %s '%s' quantity %d\n", row.Line, row.Action, row.Stock.Name, row.Stock.Quantity)
+		case importers.Update:
+			fmt.Fprintf(out, "line %d: %s '%s' quantity %d -> %d\n",
+				row.Line, row.Action, row.Stock.Name, row.Existing.Quantity, row.Stock.Quantity)
+		case importers.Reject:
+			fmt.Fprintf(out, "line %d: %s %s\n", row.Line, row.Action, row.Err)
+		}
+	}
+
+	prefix := "Imported"
+	if dryRun {
+		prefix = "Dry-run, would import"
+	}
+
+	fmt.Fprintf(out, "%s: %d created, %d updated, %d unchanged, %d rejected\n",
+		prefix,
+		report.Count(importers.Create),
+		report.Count(importers.Update),
+		report.Count(importers.Unchanged),
+		report.Count(importers.Reject),
+	)
+}
+
+func writeRejects(path string, report *importers.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	return report.WriteRejects(f)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"stocks-api/module/entities"
+	"stocks-api/support/auth"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+const _tenantFlag = "tenant"
+
+func init() {
+	if err := godotenv.Load(".env"); err != nil {
+		log.Print("No .env file found")
+	}
+}
+
+func main() {
+	var rootCmd = &cobra.Command{
+		Use:   "stockctl",
+		Short: "Stock management operations tooling",
+	}
+
+	rootCmd.PersistentFlags().String(_tenantFlag, entities.DefaultTenant, "tenant the stock items and the minted API keys belong to")
+	config.RegisterFlags(rootCmd.PersistentFlags())
+
+	rootCmd.AddCommand(
+		importCmd(),
+		exportCmd(),
+		apiKeyCmd(),
+		configCmd(),
+	)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// tenantContext a background context, scoped to the tenant of the --tenant flag.
+func tenantContext(cmd *cobra.Command) (context.Context, error) {
+	requested, _ := cmd.Flags().GetString(_tenantFlag)
+
+	tenant, err := auth.Tenant(nil, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.WithTenant(context.Background(), tenant), nil
+}
+
+// spinUpDb connects to the database of the config.
+func spinUpDb(cmd *cobra.Command, logger *logrus.Logger) (*db.Instance, error) {
+	cfg, err := config.Load(cmd.Flags())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.NewConnection(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := db.NewInstance(conn, logger)
+	if err := instance.Health(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
@@ -0,0 +1,10 @@
+package stocks
+
+import (
+	_ "embed"
+)
+
+// OpenAPI the OpenAPI (v2) document of the gateway routes, generated from stocks.proto by protoc-gen-openapiv2.
+//
+//go:embed stocks.swagger.json
+var OpenAPI []byte
@@ -7,9 +7,12 @@
 package stocks
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,6 +24,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// StockEventType is the kind of change.
+type StockEventType int32
+
+const (
+	StockEventType_STOCK_EVENT_TYPE_UNSPECIFIED StockEventType = 0
+	StockEventType_STOCK_EVENT_TYPE_CREATED     StockEventType = 1
+	StockEventType_STOCK_EVENT_TYPE_UPDATED     StockEventType = 2
+	StockEventType_STOCK_EVENT_TYPE_DELETED     StockEventType = 3
+)
+
+// Enum value maps for StockEventType.
+var (
+	StockEventType_name = map[int32]string{
+		0: "STOCK_EVENT_TYPE_UNSPECIFIED",
+		1: "STOCK_EVENT_TYPE_CREATED",
+		2: "STOCK_EVENT_TYPE_UPDATED",
+		3: "STOCK_EVENT_TYPE_DELETED",
+	}
+	StockEventType_value = map[string]int32{
+		"STOCK_EVENT_TYPE_UNSPECIFIED": 0,
+		"STOCK_EVENT_TYPE_CREATED":     1,
+		"STOCK_EVENT_TYPE_UPDATED":     2,
+		"STOCK_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x StockEventType) Enum() *StockEventType {
+	p := new(StockEventType)
+	*p = x
+	return p
+}
+
+func (x StockEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StockEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_stocks_proto_enumTypes[0].Descriptor()
+}
+
+func (StockEventType) Type() protoreflect.EnumType {
+	return &file_stocks_proto_enumTypes[0]
+}
+
+func (x StockEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StockEventType.Descriptor instead.
+func (StockEventType) EnumDescriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{0}
+}
+
 // GetStockRequest is the request definition.
 type GetStockRequest struct {
 	state         protoimpl.MessageState
@@ -274,6 +330,8 @@ type CreateStockResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Stock *SingleStock `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"` // the created item, as stored
 }
 
 func (x *CreateStockResponse) Reset() {
@@ -308,6 +366,13 @@ func (*CreateStockResponse) Descriptor() ([]byte, []int) {
 	return file_stocks_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *CreateStockResponse) GetStock() *SingleStock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
 // EditStockRequest is the request definition.
 type EditStockRequest struct {
 	state         protoimpl.MessageState
@@ -315,6 +380,8 @@ type EditStockRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	Stock *EditableStock `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"`
+	// update_mask lists the fields to update ("name", "quantity"), all are updated if empty.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 }
 
 func (x *EditStockRequest) Reset() {
@@ -356,11 +423,20 @@ func (x *EditStockRequest) GetStock() *EditableStock {
 	return nil
 }
 
+func (x *EditStockRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 // EditStockResponse is the response definition.
 type EditStockResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Stock *SingleStock `protobuf:"bytes,1,opt,name=stock,proto3" json:"stock,omitempty"` // the edited item, as stored
 }
 
 func (x *EditStockResponse) Reset() {
@@ -395,6 +471,13 @@ func (*EditStockResponse) Descriptor() ([]byte, []int) {
 	return file_stocks_proto_rawDescGZIP(), []int{7}
 }
 
+func (x *EditStockResponse) GetStock() *SingleStock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
 // DeleteStockRequest is the request definition.
 type DeleteStockRequest struct {
 	state         protoimpl.MessageState
@@ -482,23 +565,18 @@ func (*DeleteStockResponse) Descriptor() ([]byte, []int) {
 	return file_stocks_proto_rawDescGZIP(), []int{9}
 }
 
-// SingleStock represents a single stock item.
-type SingleStock struct {
+// BatchCreateStocksRequest is the request definition.
+type BatchCreateStocksRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity   int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	HCreatedAt string                 `protobuf:"bytes,6,opt,name=h_created_at,json=hCreatedAt,proto3" json:"h_created_at,omitempty"`
-	HUpdatedAt string                 `protobuf:"bytes,7,opt,name=h_updated_at,json=hUpdatedAt,proto3" json:"h_updated_at,omitempty"`
+	Stocks     []*NewStock `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	BestEffort bool        `protobuf:"varint,2,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"` // process items independently instead of in a single transaction
 }
 
-func (x *SingleStock) Reset() {
-	*x = SingleStock{}
+func (x *BatchCreateStocksRequest) Reset() {
+	*x = BatchCreateStocksRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_stocks_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -506,13 +584,13 @@ func (x *SingleStock) Reset() {
 	}
 }
 
-func (x *SingleStock) String() string {
+func (x *BatchCreateStocksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SingleStock) ProtoMessage() {}
+func (*BatchCreateStocksRequest) ProtoMessage() {}
 
-func (x *SingleStock) ProtoReflect() protoreflect.Message {
+func (x *BatchCreateStocksRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_stocks_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -524,88 +602,155 @@ func (x *SingleStock) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SingleStock.ProtoReflect.Descriptor instead.
-func (*SingleStock) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchCreateStocksRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateStocksRequest) Descriptor() ([]byte, []int) {
 	return file_stocks_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *SingleStock) GetId() string {
+func (x *BatchCreateStocksRequest) GetStocks() []*NewStock {
 	if x != nil {
-		return x.Id
+		return x.Stocks
 	}
-	return ""
+	return nil
 }
 
-func (x *SingleStock) GetName() string {
+func (x *BatchCreateStocksRequest) GetBestEffort() bool {
 	if x != nil {
-		return x.Name
+		return x.BestEffort
 	}
-	return ""
+	return false
 }
 
-func (x *SingleStock) GetQuantity() int64 {
-	if x != nil {
-		return x.Quantity
+// BatchCreateStocksResponse is the response definition.
+type BatchCreateStocksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchCreateStocksResponse) Reset() {
+	*x = BatchCreateStocksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *SingleStock) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
+func (x *BatchCreateStocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateStocksResponse) ProtoMessage() {}
+
+func (x *BatchCreateStocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *SingleStock) GetUpdatedAt() *timestamppb.Timestamp {
+// Deprecated: Use BatchCreateStocksResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateStocksResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchCreateStocksResponse) GetResults() []*BatchItemResult {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Results
 	}
 	return nil
 }
 
-func (x *SingleStock) GetHCreatedAt() string {
+// BatchEditStocksRequest is the request definition.
+type BatchEditStocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stocks     []*EditableStock `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	BestEffort bool             `protobuf:"varint,2,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"` // process items independently instead of in a single transaction
+}
+
+func (x *BatchEditStocksRequest) Reset() {
+	*x = BatchEditStocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchEditStocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEditStocksRequest) ProtoMessage() {}
+
+func (x *BatchEditStocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEditStocksRequest.ProtoReflect.Descriptor instead.
+func (*BatchEditStocksRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchEditStocksRequest) GetStocks() []*EditableStock {
 	if x != nil {
-		return x.HCreatedAt
+		return x.Stocks
 	}
-	return ""
+	return nil
 }
 
-func (x *SingleStock) GetHUpdatedAt() string {
+func (x *BatchEditStocksRequest) GetBestEffort() bool {
 	if x != nil {
-		return x.HUpdatedAt
+		return x.BestEffort
 	}
-	return ""
+	return false
 }
 
-// EditableStock represents an editable/creatable stock item.
-type EditableStock struct {
+// BatchEditStocksResponse is the response definition.
+type BatchEditStocksResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity int64  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Results []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *EditableStock) Reset() {
-	*x = EditableStock{}
+func (x *BatchEditStocksResponse) Reset() {
+	*x = BatchEditStocksResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_stocks_proto_msgTypes[11]
+		mi := &file_stocks_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *EditableStock) String() string {
+func (x *BatchEditStocksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditableStock) ProtoMessage() {}
+func (*BatchEditStocksResponse) ProtoMessage() {}
 
-func (x *EditableStock) ProtoReflect() protoreflect.Message {
-	mi := &file_stocks_proto_msgTypes[11]
+func (x *BatchEditStocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -616,59 +761,100 @@ func (x *EditableStock) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditableStock.ProtoReflect.Descriptor instead.
-func (*EditableStock) Descriptor() ([]byte, []int) {
-	return file_stocks_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use BatchEditStocksResponse.ProtoReflect.Descriptor instead.
+func (*BatchEditStocksResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *EditableStock) GetId() string {
+func (x *BatchEditStocksResponse) GetResults() []*BatchItemResult {
 	if x != nil {
-		return x.Id
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-func (x *EditableStock) GetName() string {
+// BatchDeleteStocksRequest is the request definition.
+type BatchDeleteStocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids        []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	BestEffort bool     `protobuf:"varint,2,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"` // process items independently instead of in a single transaction
+}
+
+func (x *BatchDeleteStocksRequest) Reset() {
+	*x = BatchDeleteStocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDeleteStocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteStocksRequest) ProtoMessage() {}
+
+func (x *BatchDeleteStocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteStocksRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteStocksRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchDeleteStocksRequest) GetIds() []string {
 	if x != nil {
-		return x.Name
+		return x.Ids
 	}
-	return ""
+	return nil
 }
 
-func (x *EditableStock) GetQuantity() int64 {
+func (x *BatchDeleteStocksRequest) GetBestEffort() bool {
 	if x != nil {
-		return x.Quantity
+		return x.BestEffort
 	}
-	return 0
+	return false
 }
 
-// NewStock represents an creatable stock item.
-type NewStock struct {
+// BatchDeleteStocksResponse is the response definition.
+type BatchDeleteStocksResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity int64  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Results []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *NewStock) Reset() {
-	*x = NewStock{}
+func (x *BatchDeleteStocksResponse) Reset() {
+	*x = BatchDeleteStocksResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_stocks_proto_msgTypes[12]
+		mi := &file_stocks_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NewStock) String() string {
+func (x *BatchDeleteStocksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewStock) ProtoMessage() {}
+func (*BatchDeleteStocksResponse) ProtoMessage() {}
 
-func (x *NewStock) ProtoReflect() protoreflect.Message {
-	mi := &file_stocks_proto_msgTypes[12]
+func (x *BatchDeleteStocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -679,52 +865,46 @@ func (x *NewStock) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewStock.ProtoReflect.Descriptor instead.
-func (*NewStock) Descriptor() ([]byte, []int) {
-	return file_stocks_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *NewStock) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// Deprecated: Use BatchDeleteStocksResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteStocksResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *NewStock) GetQuantity() int64 {
+func (x *BatchDeleteStocksResponse) GetResults() []*BatchItemResult {
 	if x != nil {
-		return x.Quantity
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-// Pagination is used within listing operation.
-type Pagination struct {
+// BatchItemResult is the outcome of a single item within a batch operation.
+type BatchItemResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Page         int64 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	ItemsPerPage int64 `protobuf:"varint,2,opt,name=items_per_page,json=itemsPerPage,proto3" json:"items_per_page,omitempty"`
+	Index int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // position of the item in the request
+	Id    string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // empty on success
 }
 
-func (x *Pagination) Reset() {
-	*x = Pagination{}
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_stocks_proto_msgTypes[13]
+		mi := &file_stocks_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Pagination) String() string {
+func (x *BatchItemResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Pagination) ProtoMessage() {}
+func (*BatchItemResult) ProtoMessage() {}
 
-func (x *Pagination) ProtoReflect() protoreflect.Message {
-	mi := &file_stocks_proto_msgTypes[13]
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -735,39 +915,620 @@ func (x *Pagination) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
-func (*Pagination) Descriptor() ([]byte, []int) {
-	return file_stocks_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Pagination) GetPage() int64 {
+func (x *BatchItemResult) GetIndex() int64 {
 	if x != nil {
-		return x.Page
+		return x.Index
 	}
 	return 0
 }
 
-func (x *Pagination) GetItemsPerPage() int64 {
+func (x *BatchItemResult) GetId() string {
 	if x != nil {
-		return x.ItemsPerPage
+		return x.Id
 	}
-	return 0
+	return ""
 }
 
-var File_stocks_proto protoreflect.FileDescriptor
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
 
-var file_stocks_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74,
-	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x10, 0x47, 0x65,
-	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29,
-	0x0a, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f,
-	0x63, 0x6b, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x22, 0x47, 0x0a, 0x11, 0x4c, 0x69, 0x73,
-	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32,
+// ExportStocksRequest is the request definition.
+type ExportStocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *StockFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *ExportStocksRequest) Reset() {
+	*x = ExportStocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportStocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStocksRequest) ProtoMessage() {}
+
+func (x *ExportStocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStocksRequest.ProtoReflect.Descriptor instead.
+func (*ExportStocksRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportStocksRequest) GetFilter() *StockFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// ExportStocksResponse is a single chunk of the exported stock items.
+type ExportStocksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stocks []*SingleStock `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+}
+
+func (x *ExportStocksResponse) Reset() {
+	*x = ExportStocksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportStocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportStocksResponse) ProtoMessage() {}
+
+func (x *ExportStocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportStocksResponse.ProtoReflect.Descriptor instead.
+func (*ExportStocksResponse) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExportStocksResponse) GetStocks() []*SingleStock {
+	if x != nil {
+		return x.Stocks
+	}
+	return nil
+}
+
+// WatchStocksRequest is the request definition.
+type WatchStocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromSequence int64    `protobuf:"varint,1,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"` // replays the events after this sequence first, 0 streams only new events
+	StockIds     []string `protobuf:"bytes,2,rep,name=stock_ids,json=stockIds,proto3" json:"stock_ids,omitempty"`              // only stream the events of these items, all if empty
+}
+
+func (x *WatchStocksRequest) Reset() {
+	*x = WatchStocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchStocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStocksRequest) ProtoMessage() {}
+
+func (x *WatchStocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStocksRequest.ProtoReflect.Descriptor instead.
+func (*WatchStocksRequest) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchStocksRequest) GetFromSequence() int64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
+func (x *WatchStocksRequest) GetStockIds() []string {
+	if x != nil {
+		return x.StockIds
+	}
+	return nil
+}
+
+// StockEvent represents a single change of a stock item.
+type StockEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sequence   int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // monotonically increasing, used to resume watching
+	Type       StockEventType         `protobuf:"varint,2,opt,name=type,proto3,enum=stocks.StockEventType" json:"type,omitempty"`
+	Stock      *SingleStock           `protobuf:"bytes,3,opt,name=stock,proto3" json:"stock,omitempty"` // the item after the change, or before it for deletions
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+}
+
+func (x *StockEvent) Reset() {
+	*x = StockEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StockEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockEvent) ProtoMessage() {}
+
+func (x *StockEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockEvent.ProtoReflect.Descriptor instead.
+func (*StockEvent) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StockEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StockEvent) GetType() StockEventType {
+	if x != nil {
+		return x.Type
+	}
+	return StockEventType_STOCK_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *StockEvent) GetStock() *SingleStock {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+func (x *StockEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+// SingleStock represents a single stock item.
+type SingleStock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity   int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	HCreatedAt string                 `protobuf:"bytes,6,opt,name=h_created_at,json=hCreatedAt,proto3" json:"h_created_at,omitempty"` // human readable timestamp
+	HUpdatedAt string                 `protobuf:"bytes,7,opt,name=h_updated_at,json=hUpdatedAt,proto3" json:"h_updated_at,omitempty"` // human readable timestamp
+}
+
+func (x *SingleStock) Reset() {
+	*x = SingleStock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SingleStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SingleStock) ProtoMessage() {}
+
+func (x *SingleStock) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SingleStock.ProtoReflect.Descriptor instead.
+func (*SingleStock) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SingleStock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SingleStock) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SingleStock) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *SingleStock) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SingleStock) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *SingleStock) GetHCreatedAt() string {
+	if x != nil {
+		return x.HCreatedAt
+	}
+	return ""
+}
+
+func (x *SingleStock) GetHUpdatedAt() string {
+	if x != nil {
+		return x.HUpdatedAt
+	}
+	return ""
+}
+
+// EditableStock represents an editable/creatable stock item.
+type EditableStock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity int64  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+}
+
+func (x *EditableStock) Reset() {
+	*x = EditableStock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EditableStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditableStock) ProtoMessage() {}
+
+func (x *EditableStock) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditableStock.ProtoReflect.Descriptor instead.
+func (*EditableStock) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *EditableStock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EditableStock) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EditableStock) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// NewStock represents an creatable stock item.
+type NewStock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity int64  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Id       string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"` // optional, generated if empty
+}
+
+func (x *NewStock) Reset() {
+	*x = NewStock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewStock) ProtoMessage() {}
+
+func (x *NewStock) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewStock.ProtoReflect.Descriptor instead.
+func (*NewStock) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *NewStock) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NewStock) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *NewStock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Pagination is used within listing operation.
+type Pagination struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Page         int64 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	ItemsPerPage int64 `protobuf:"varint,2,opt,name=items_per_page,json=itemsPerPage,proto3" json:"items_per_page,omitempty"`
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Pagination) GetPage() int64 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetItemsPerPage() int64 {
+	if x != nil {
+		return x.ItemsPerPage
+	}
+	return 0
+}
+
+// StockFilter narrows down the stock items of listing operations, unset fields are ignored.
+type StockFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // case-insensitive partial match
+	MinQuantity *wrapperspb.Int64Value `protobuf:"bytes,2,opt,name=min_quantity,json=minQuantity,proto3" json:"min_quantity,omitempty"`
+	MaxQuantity *wrapperspb.Int64Value `protobuf:"bytes,3,opt,name=max_quantity,json=maxQuantity,proto3" json:"max_quantity,omitempty"`
+}
+
+func (x *StockFilter) Reset() {
+	*x = StockFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_stocks_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StockFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockFilter) ProtoMessage() {}
+
+func (x *StockFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_stocks_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockFilter.ProtoReflect.Descriptor instead.
+func (*StockFilter) Descriptor() ([]byte, []int) {
+	return file_stocks_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StockFilter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StockFilter) GetMinQuantity() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.MinQuantity
+	}
+	return nil
+}
+
+func (x *StockFilter) GetMaxQuantity() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.MaxQuantity
+	}
+	return nil
+}
+
+var File_stocks_proto protoreflect.FileDescriptor
+
+var file_stocks_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73,
+	0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x10, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29,
+	0x0a, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x22, 0x47, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32,
 	0x0a, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x50, 0x61, 0x67, 0x69,
 	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x69,
@@ -781,17 +1542,90 @@ var file_stocks_proto_rawDesc = []byte{
 	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05,
 	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73, 0x74,
 	0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x73,
-	0x74, 0x6f, 0x63, 0x6b, 0x22, 0x15, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74,
-	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3f, 0x0a, 0x10, 0x45,
-	0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x2b, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
-	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x22, 0x13, 0x0a, 0x11,
-	0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x87,
+	0x74, 0x6f, 0x63, 0x6b, 0x22, 0x40, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52,
+	0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x22, 0x7c, 0x0a, 0x10, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b,
+	0x52, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x4d, 0x61, 0x73, 0x6b, 0x22, 0x3e, 0x0a, 0x11, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x22, 0x24, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x15, 0x0a, 0x13, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x65, 0x0a, 0x18, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a,
+	0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52,
+	0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x65, 0x73, 0x74, 0x5f,
+	0x65, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x65,
+	0x73, 0x74, 0x45, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x22, 0x4e, 0x0a, 0x19, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x68, 0x0a, 0x16, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x65, 0x66, 0x66, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x45, 0x66, 0x66, 0x6f,
+	0x72, 0x74, 0x22, 0x4c, 0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x64, 0x69, 0x74, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x22, 0x4d, 0x0a, 0x18, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x65, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x45, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x22,
+	0x4e, 0x0a, 0x19, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22,
+	0x4d, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x42,
+	0x0a, 0x13, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x22, 0x43, 0x0a, 0x14, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x2e, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52,
+	0x06, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x56, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a,
+	0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x73, 0x22,
+	0xbc, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x53,
+	0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x12, 0x3b, 0x0a, 0x0b, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0a, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0x87,
 	0x02, 0x0a, 0x0b, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x0e,
 	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
 	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
@@ -813,42 +1647,105 @@ var file_stocks_proto_rawDesc = []byte{
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a,
 	0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x3a, 0x0a, 0x08, 0x4e, 0x65, 0x77,
+	0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x4a, 0x0a, 0x08, 0x4e, 0x65, 0x77,
 	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61,
 	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x46, 0x0a, 0x0a, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x46, 0x0a, 0x0a, 0x50, 0x61, 0x67, 0x69, 0x6e, 0x61, 0x74,
 	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x03, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x69, 0x74, 0x65, 0x6d, 0x73,
 	0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0c, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x65, 0x72, 0x50, 0x61, 0x67, 0x65, 0x32, 0xe4, 0x02,
-	0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d,
-	0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x17, 0x2e, 0x73, 0x74, 0x6f,
-	0x63, 0x6b, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x47, 0x65, 0x74,
-	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a,
-	0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x19, 0x2e, 0x73, 0x74,
-	0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63,
+	0x0c, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x50, 0x65, 0x72, 0x50, 0x61, 0x67, 0x65, 0x22, 0xa1, 0x01,
+	0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x12, 0x3e, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x2a, 0x8c, 0x01, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x54, 0x4f, 0x43, 0x4b, 0x5f, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x54, 0x4f, 0x43, 0x4b, 0x5f,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x54, 0x4f, 0x43, 0x4b, 0x5f, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44,
+	0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x54, 0x4f, 0x43, 0x4b, 0x5f, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03,
+	0x32, 0x9d, 0x08, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x56, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x17, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x57, 0x0a, 0x0a, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x19, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x12,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x61, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63,
 	0x6b, 0x12, 0x1a, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
 	0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
 	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f,
-	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x45, 0x64,
-	0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73,
-	0x2e, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x19, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x53,
-	0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x1a, 0x2e, 0x73, 0x74,
-	0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x4d, 0x53, 0x61, 0x72, 0x61, 0x6e, 0x64, 0x65, 0x76, 0x2f, 0x73, 0x74, 0x6f,
-	0x63, 0x6b, 0x2d, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x13, 0x22, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x3a, 0x05,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x66, 0x0a, 0x09, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x12, 0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74,
+	0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x32,
+	0x15, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2f, 0x7b, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x2e, 0x69, 0x64, 0x7d, 0x3a, 0x05, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x5f, 0x0a,
+	0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x1a, 0x2e, 0x73,
+	0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x2a, 0x0f, 0x2f,
+	0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x7b,
+	0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x12, 0x20, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b,
+	0x22, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x3a, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x73, 0x0a, 0x0f, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x45, 0x64, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1e,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x64, 0x69,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x64, 0x69,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x3a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x45, 0x64, 0x69, 0x74, 0x3a, 0x01, 0x2a,
+	0x12, 0x7b, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x20, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x1b, 0x22, 0x16, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x3a, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x66, 0x0a,
+	0x0c, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1b, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13,
+	0x12, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x3a, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x30, 0x01, 0x12, 0x59, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1a, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x12, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x3a, 0x77, 0x61, 0x74, 0x63, 0x68, 0x30, 0x01,
+	0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4d,
+	0x53, 0x61, 0x72, 0x61, 0x6e, 0x64, 0x65, 0x76, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x2d, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -863,47 +1760,88 @@ func file_stocks_proto_rawDescGZIP() []byte {
 	return file_stocks_proto_rawDescData
 }
 
-var file_stocks_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_stocks_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_stocks_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_stocks_proto_goTypes = []interface{}{
-	(*GetStockRequest)(nil),       // 0: stocks.GetStockRequest
-	(*GetStockResponse)(nil),      // 1: stocks.GetStockResponse
-	(*ListStocksRequest)(nil),     // 2: stocks.ListStocksRequest
-	(*ListStocksResponse)(nil),    // 3: stocks.ListStocksResponse
-	(*CreateStockRequest)(nil),    // 4: stocks.CreateStockRequest
-	(*CreateStockResponse)(nil),   // 5: stocks.CreateStockResponse
-	(*EditStockRequest)(nil),      // 6: stocks.EditStockRequest
-	(*EditStockResponse)(nil),     // 7: stocks.EditStockResponse
-	(*DeleteStockRequest)(nil),    // 8: stocks.DeleteStockRequest
-	(*DeleteStockResponse)(nil),   // 9: stocks.DeleteStockResponse
-	(*SingleStock)(nil),           // 10: stocks.SingleStock
-	(*EditableStock)(nil),         // 11: stocks.EditableStock
-	(*NewStock)(nil),              // 12: stocks.NewStock
-	(*Pagination)(nil),            // 13: stocks.Pagination
-	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+	(StockEventType)(0),               // 0: stocks.StockEventType
+	(*GetStockRequest)(nil),           // 1: stocks.GetStockRequest
+	(*GetStockResponse)(nil),          // 2: stocks.GetStockResponse
+	(*ListStocksRequest)(nil),         // 3: stocks.ListStocksRequest
+	(*ListStocksResponse)(nil),        // 4: stocks.ListStocksResponse
+	(*CreateStockRequest)(nil),        // 5: stocks.CreateStockRequest
+	(*CreateStockResponse)(nil),       // 6: stocks.CreateStockResponse
+	(*EditStockRequest)(nil),          // 7: stocks.EditStockRequest
+	(*EditStockResponse)(nil),         // 8: stocks.EditStockResponse
+	(*DeleteStockRequest)(nil),        // 9: stocks.DeleteStockRequest
+	(*DeleteStockResponse)(nil),       // 10: stocks.DeleteStockResponse
+	(*BatchCreateStocksRequest)(nil),  // 11: stocks.BatchCreateStocksRequest
+	(*BatchCreateStocksResponse)(nil), // 12: stocks.BatchCreateStocksResponse
+	(*BatchEditStocksRequest)(nil),    // 13: stocks.BatchEditStocksRequest
+	(*BatchEditStocksResponse)(nil),   // 14: stocks.BatchEditStocksResponse
+	(*BatchDeleteStocksRequest)(nil),  // 15: stocks.BatchDeleteStocksRequest
+	(*BatchDeleteStocksResponse)(nil), // 16: stocks.BatchDeleteStocksResponse
+	(*BatchItemResult)(nil),           // 17: stocks.BatchItemResult
+	(*ExportStocksRequest)(nil),       // 18: stocks.ExportStocksRequest
+	(*ExportStocksResponse)(nil),      // 19: stocks.ExportStocksResponse
+	(*WatchStocksRequest)(nil),        // 20: stocks.WatchStocksRequest
+	(*StockEvent)(nil),                // 21: stocks.StockEvent
+	(*SingleStock)(nil),               // 22: stocks.SingleStock
+	(*EditableStock)(nil),             // 23: stocks.EditableStock
+	(*NewStock)(nil),                  // 24: stocks.NewStock
+	(*Pagination)(nil),                // 25: stocks.Pagination
+	(*StockFilter)(nil),               // 26: stocks.StockFilter
+	(*fieldmaskpb.FieldMask)(nil),     // 27: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),     // 28: google.protobuf.Timestamp
+	(*wrapperspb.Int64Value)(nil),     // 29: google.protobuf.Int64Value
 }
 var file_stocks_proto_depIdxs = []int32{
-	10, // 0: stocks.GetStockResponse.stock:type_name -> stocks.SingleStock
-	13, // 1: stocks.ListStocksRequest.pagination:type_name -> stocks.Pagination
-	10, // 2: stocks.ListStocksResponse.stocks:type_name -> stocks.SingleStock
-	12, // 3: stocks.CreateStockRequest.stock:type_name -> stocks.NewStock
-	11, // 4: stocks.EditStockRequest.stock:type_name -> stocks.EditableStock
-	14, // 5: stocks.SingleStock.created_at:type_name -> google.protobuf.Timestamp
-	14, // 6: stocks.SingleStock.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 7: stocks.StockService.GetStock:input_type -> stocks.GetStockRequest
-	2,  // 8: stocks.StockService.ListStocks:input_type -> stocks.ListStocksRequest
-	4,  // 9: stocks.StockService.CreateStock:input_type -> stocks.CreateStockRequest
-	6,  // 10: stocks.StockService.EditStock:input_type -> stocks.EditStockRequest
-	8,  // 11: stocks.StockService.DeleteStock:input_type -> stocks.DeleteStockRequest
-	1,  // 12: stocks.StockService.GetStock:output_type -> stocks.GetStockResponse
-	3,  // 13: stocks.StockService.ListStocks:output_type -> stocks.ListStocksResponse
-	5,  // 14: stocks.StockService.CreateStock:output_type -> stocks.CreateStockResponse
-	7,  // 15: stocks.StockService.EditStock:output_type -> stocks.EditStockResponse
-	9,  // 16: stocks.StockService.DeleteStock:output_type -> stocks.DeleteStockResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	22, // 0: stocks.GetStockResponse.stock:type_name -> stocks.SingleStock
+	25, // 1: stocks.ListStocksRequest.pagination:type_name -> stocks.Pagination
+	22, // 2: stocks.ListStocksResponse.stocks:type_name -> stocks.SingleStock
+	24, // 3: stocks.CreateStockRequest.stock:type_name -> stocks.NewStock
+	22, // 4: stocks.CreateStockResponse.stock:type_name -> stocks.SingleStock
+	23, // 5: stocks.EditStockRequest.stock:type_name -> stocks.EditableStock
+	27, // 6: stocks.EditStockRequest.update_mask:type_name -> google.protobuf.FieldMask
+	22, // 7: stocks.EditStockResponse.stock:type_name -> stocks.SingleStock
+	24, // 8: stocks.BatchCreateStocksRequest.stocks:type_name -> stocks.NewStock
+	17, // 9: stocks.BatchCreateStocksResponse.results:type_name -> stocks.BatchItemResult
+	23, // 10: stocks.BatchEditStocksRequest.stocks:type_name -> stocks.EditableStock
+	17, // 11: stocks.BatchEditStocksResponse.results:type_name -> stocks.BatchItemResult
+	17, // 12: stocks.BatchDeleteStocksResponse.results:type_name -> stocks.BatchItemResult
+	26, // 13: stocks.ExportStocksRequest.filter:type_name -> stocks.StockFilter
+	22, // 14: stocks.ExportStocksResponse.stocks:type_name -> stocks.SingleStock
+	0,  // 15: stocks.StockEvent.type:type_name -> stocks.StockEventType
+	22, // 16: stocks.StockEvent.stock:type_name -> stocks.SingleStock
+	28, // 17: stocks.StockEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	28, // 18: stocks.SingleStock.created_at:type_name -> google.protobuf.Timestamp
+	28, // 19: stocks.SingleStock.updated_at:type_name -> google.protobuf.Timestamp
+	29, // 20: stocks.StockFilter.min_quantity:type_name -> google.protobuf.Int64Value
+	29, // 21: stocks.StockFilter.max_quantity:type_name -> google.protobuf.Int64Value
+	1,  // 22: stocks.StockService.GetStock:input_type -> stocks.GetStockRequest
+	3,  // 23: stocks.StockService.ListStocks:input_type -> stocks.ListStocksRequest
+	5,  // 24: stocks.StockService.CreateStock:input_type -> stocks.CreateStockRequest
+	7,  // 25: stocks.StockService.EditStock:input_type -> stocks.EditStockRequest
+	9,  // 26: stocks.StockService.DeleteStock:input_type -> stocks.DeleteStockRequest
+	11, // 27: stocks.StockService.BatchCreateStocks:input_type -> stocks.BatchCreateStocksRequest
+	13, // 28: stocks.StockService.BatchEditStocks:input_type -> stocks.BatchEditStocksRequest
+	15, // 29: stocks.StockService.BatchDeleteStocks:input_type -> stocks.BatchDeleteStocksRequest
+	18, // 30: stocks.StockService.ExportStocks:input_type -> stocks.ExportStocksRequest
+	20, // 31: stocks.StockService.WatchStocks:input_type -> stocks.WatchStocksRequest
+	2,  // 32: stocks.StockService.GetStock:output_type -> stocks.GetStockResponse
+	4,  // 33: stocks.StockService.ListStocks:output_type -> stocks.ListStocksResponse
+	6,  // 34: stocks.StockService.CreateStock:output_type -> stocks.CreateStockResponse
+	8,  // 35: stocks.StockService.EditStock:output_type -> stocks.EditStockResponse
+	10, // 36: stocks.StockService.DeleteStock:output_type -> stocks.DeleteStockResponse
+	12, // 37: stocks.StockService.BatchCreateStocks:output_type -> stocks.BatchCreateStocksResponse
+	14, // 38: stocks.StockService.BatchEditStocks:output_type -> stocks.BatchEditStocksResponse
+	16, // 39: stocks.StockService.BatchDeleteStocks:output_type -> stocks.BatchDeleteStocksResponse
+	19, // 40: stocks.StockService.ExportStocks:output_type -> stocks.ExportStocksResponse
+	21, // 41: stocks.StockService.WatchStocks:output_type -> stocks.StockEvent
+	32, // [32:42] is the sub-list for method output_type
+	22, // [22:32] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_stocks_proto_init() }
@@ -1033,7 +1971,7 @@ func file_stocks_proto_init() {
 			}
 		}
 		file_stocks_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SingleStock); i {
+			switch v := v.(*BatchCreateStocksRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1045,7 +1983,7 @@ func file_stocks_proto_init() {
 			}
 		}
 		file_stocks_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EditableStock); i {
+			switch v := v.(*BatchCreateStocksResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1057,7 +1995,7 @@ func file_stocks_proto_init() {
 			}
 		}
 		file_stocks_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewStock); i {
+			switch v := v.(*BatchEditStocksRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1069,6 +2007,138 @@ func file_stocks_proto_init() {
 			}
 		}
 		file_stocks_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchEditStocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteStocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteStocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchItemResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportStocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportStocksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchStocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StockEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SingleStock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EditableStock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewStock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_stocks_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Pagination); i {
 			case 0:
 				return &v.state
@@ -1080,19 +2150,32 @@ func file_stocks_proto_init() {
 				return nil
 			}
 		}
+		file_stocks_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StockFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_stocks_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   14,
+			NumEnums:      1,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_stocks_proto_goTypes,
 		DependencyIndexes: file_stocks_proto_depIdxs,
+		EnumInfos:         file_stocks_proto_enumTypes,
 		MessageInfos:      file_stocks_proto_msgTypes,
 	}.Build()
 	File_stocks_proto = out.File
@@ -32,6 +32,16 @@ type StockServiceClient interface {
 	EditStock(ctx context.Context, in *EditStockRequest, opts ...grpc.CallOption) (*EditStockResponse, error)
 	// DeleteStock removes a single stock item by id.
 	DeleteStock(ctx context.Context, in *DeleteStockRequest, opts ...grpc.CallOption) (*DeleteStockResponse, error)
+	// BatchCreateStocks creates multiple stock items at once.
+	BatchCreateStocks(ctx context.Context, in *BatchCreateStocksRequest, opts ...grpc.CallOption) (*BatchCreateStocksResponse, error)
+	// BatchEditStocks edits multiple stock items at once.
+	BatchEditStocks(ctx context.Context, in *BatchEditStocksRequest, opts ...grpc.CallOption) (*BatchEditStocksResponse, error)
+	// BatchDeleteStocks removes multiple stock items by id at once.
+	BatchDeleteStocks(ctx context.Context, in *BatchDeleteStocksRequest, opts ...grpc.CallOption) (*BatchDeleteStocksResponse, error)
+	// ExportStocks streams every stock item matching the filter, in chunks.
+	ExportStocks(ctx context.Context, in *ExportStocksRequest, opts ...grpc.CallOption) (StockService_ExportStocksClient, error)
+	// WatchStocks streams the stock changes as they happen.
+	WatchStocks(ctx context.Context, in *WatchStocksRequest, opts ...grpc.CallOption) (StockService_WatchStocksClient, error)
 }
 
 type stockServiceClient struct {
@@ -87,6 +97,97 @@ func (c *stockServiceClient) DeleteStock(ctx context.Context, in *DeleteStockReq
 	return out, nil
 }
 
+func (c *stockServiceClient) BatchCreateStocks(ctx context.Context, in *BatchCreateStocksRequest, opts ...grpc.CallOption) (*BatchCreateStocksResponse, error) {
+	out := new(BatchCreateStocksResponse)
+	err := c.cc.Invoke(ctx, "/stocks.StockService/BatchCreateStocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockServiceClient) BatchEditStocks(ctx context.Context, in *BatchEditStocksRequest, opts ...grpc.CallOption) (*BatchEditStocksResponse, error) {
+	out := new(BatchEditStocksResponse)
+	err := c.cc.Invoke(ctx, "/stocks.StockService/BatchEditStocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockServiceClient) BatchDeleteStocks(ctx context.Context, in *BatchDeleteStocksRequest, opts ...grpc.CallOption) (*BatchDeleteStocksResponse, error) {
+	out := new(BatchDeleteStocksResponse)
+	err := c.cc.Invoke(ctx, "/stocks.StockService/BatchDeleteStocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockServiceClient) ExportStocks(ctx context.Context, in *ExportStocksRequest, opts ...grpc.CallOption) (StockService_ExportStocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StockService_ServiceDesc.Streams[0], "/stocks.StockService/ExportStocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stockServiceExportStocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StockService_ExportStocksClient interface {
+	Recv() (*ExportStocksResponse, error)
+	grpc.ClientStream
+}
+
+type stockServiceExportStocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *stockServiceExportStocksClient) Recv() (*ExportStocksResponse, error) {
+	m := new(ExportStocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *stockServiceClient) WatchStocks(ctx context.Context, in *WatchStocksRequest, opts ...grpc.CallOption) (StockService_WatchStocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StockService_ServiceDesc.Streams[1], "/stocks.StockService/WatchStocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &stockServiceWatchStocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StockService_WatchStocksClient interface {
+	Recv() (*StockEvent, error)
+	grpc.ClientStream
+}
+
+type stockServiceWatchStocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *stockServiceWatchStocksClient) Recv() (*StockEvent, error) {
+	m := new(StockEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StockServiceServer is the server API for StockService service.
 // All implementations must embed UnimplementedStockServiceServer
 // for forward compatibility
@@ -101,6 +202,16 @@ type StockServiceServer interface {
 	EditStock(context.Context, *EditStockRequest) (*EditStockResponse, error)
 	// DeleteStock removes a single stock item by id.
 	DeleteStock(context.Context, *DeleteStockRequest) (*DeleteStockResponse, error)
+	// BatchCreateStocks creates multiple stock items at once.
+	BatchCreateStocks(context.Context, *BatchCreateStocksRequest) (*BatchCreateStocksResponse, error)
+	// BatchEditStocks edits multiple stock items at once.
+	BatchEditStocks(context.Context, *BatchEditStocksRequest) (*BatchEditStocksResponse, error)
+	// BatchDeleteStocks removes multiple stock items by id at once.
+	BatchDeleteStocks(context.Context, *BatchDeleteStocksRequest) (*BatchDeleteStocksResponse, error)
+	// ExportStocks streams every stock item matching the filter, in chunks.
+	ExportStocks(*ExportStocksRequest, StockService_ExportStocksServer) error
+	// WatchStocks streams the stock changes as they happen.
+	WatchStocks(*WatchStocksRequest, StockService_WatchStocksServer) error
 	mustEmbedUnimplementedStockServiceServer()
 }
 
@@ -123,6 +234,21 @@ func (UnimplementedStockServiceServer) EditStock(context.Context, *EditStockRequ
 func (UnimplementedStockServiceServer) DeleteStock(context.Context, *DeleteStockRequest) (*DeleteStockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteStock not implemented")
 }
+func (UnimplementedStockServiceServer) BatchCreateStocks(context.Context, *BatchCreateStocksRequest) (*BatchCreateStocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateStocks not implemented")
+}
+func (UnimplementedStockServiceServer) BatchEditStocks(context.Context, *BatchEditStocksRequest) (*BatchEditStocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchEditStocks not implemented")
+}
+func (UnimplementedStockServiceServer) BatchDeleteStocks(context.Context, *BatchDeleteStocksRequest) (*BatchDeleteStocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteStocks not implemented")
+}
+func (UnimplementedStockServiceServer) ExportStocks(*ExportStocksRequest, StockService_ExportStocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportStocks not implemented")
+}
+func (UnimplementedStockServiceServer) WatchStocks(*WatchStocksRequest, StockService_WatchStocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStocks not implemented")
+}
 func (UnimplementedStockServiceServer) mustEmbedUnimplementedStockServiceServer() {}
 
 // UnsafeStockServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -226,6 +352,102 @@ func _StockService_DeleteStock_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StockService_BatchCreateStocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateStocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockServiceServer).BatchCreateStocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stocks.StockService/BatchCreateStocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockServiceServer).BatchCreateStocks(ctx, req.(*BatchCreateStocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockService_BatchEditStocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchEditStocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockServiceServer).BatchEditStocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stocks.StockService/BatchEditStocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockServiceServer).BatchEditStocks(ctx, req.(*BatchEditStocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockService_BatchDeleteStocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteStocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockServiceServer).BatchDeleteStocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/stocks.StockService/BatchDeleteStocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockServiceServer).BatchDeleteStocks(ctx, req.(*BatchDeleteStocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StockService_ExportStocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StockServiceServer).ExportStocks(m, &stockServiceExportStocksServer{stream})
+}
+
+type StockService_ExportStocksServer interface {
+	Send(*ExportStocksResponse) error
+	grpc.ServerStream
+}
+
+type stockServiceExportStocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *stockServiceExportStocksServer) Send(m *ExportStocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StockService_WatchStocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StockServiceServer).WatchStocks(m, &stockServiceWatchStocksServer{stream})
+}
+
+type StockService_WatchStocksServer interface {
+	Send(*StockEvent) error
+	grpc.ServerStream
+}
+
+type stockServiceWatchStocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *stockServiceWatchStocksServer) Send(m *StockEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // StockService_ServiceDesc is the grpc.ServiceDesc for StockService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -253,7 +475,30 @@ var StockService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteStock",
 			Handler:    _StockService_DeleteStock_Handler,
 		},
+		{
+			MethodName: "BatchCreateStocks",
+			Handler:    _StockService_BatchCreateStocks_Handler,
+		},
+		{
+			MethodName: "BatchEditStocks",
+			Handler:    _StockService_BatchEditStocks_Handler,
+		},
+		{
+			MethodName: "BatchDeleteStocks",
+			Handler:    _StockService_BatchDeleteStocks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportStocks",
+			Handler:       _StockService_ExportStocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchStocks",
+			Handler:       _StockService_WatchStocks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "stocks.proto",
 }
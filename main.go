@@ -2,21 +2,35 @@ package main
 
 import (
 	"context"
-	"errors"
+	"crypto/tls"
 	"log"
+	stdhttp "net/http"
 	"os"
-	"strconv"
-	"sync"
 
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	rpc "google.golang.org/grpc"
 	"stocks-api/module/controllers"
 	"stocks-api/module/handlers"
+	"stocks-api/module/policy"
+	"stocks-api/support/auth"
+	"stocks-api/support/certs"
+	"stocks-api/support/config"
 	"stocks-api/support/db"
+	"stocks-api/support/events"
+	"stocks-api/support/gateway"
 	"stocks-api/support/grpc"
+	"stocks-api/support/health"
 	"stocks-api/support/http"
+	"stocks-api/support/idempotency"
+	"stocks-api/support/lifecycle"
+	"stocks-api/support/metrics"
+	"stocks-api/support/publishers"
+	"stocks-api/support/ratelimit"
+	"stocks-api/support/tracing"
+	"stocks-api/support/webhooks"
 )
 
 func init() {
@@ -27,69 +41,302 @@ func init() {
 
 func main() {
 	logger := logrus.New()
-
 	ctx := context.Background()
 
-	wg := &sync.WaitGroup{}
+	cfg := prepConfig(logger)
+	lc := lifecycle.FromConfig(logger, cfg.Shutdown)
+
+	db := prepDB(logger, ctx, cfg, lc)
+	prepTracing(logger, db, ctx, cfg, lc)
+	m := prepMetrics(logger, db, cfg, lc)
+	checker := prepHealth(logger, db, lc)
+	authenticator := prepAuth(logger, db, cfg)
+	engine := prepPolicy(logger, cfg)
+	guard := prepIdempotency(logger, db, cfg, lc)
+	limiter := prepRateLimit(logger, db, cfg, lc)
+	broker := prepBroker(logger, db, lc)
+	relayed := prepRelay(logger, db, cfg, lc)
+	prepDispatcher(logger, db, relayed, lc)
+	reloader := prepCerts(logger, cfg, lc)
+
+	lc.Serve("HTTP server", prepServer(logger, db, ctx, cfg, broker, authenticator, engine, guard, limiter, checker, m, reloader))
+	lc.Serve("gRPC server", prepGrpc(logger, db, ctx, cfg, broker, authenticator, engine, guard, limiter, checker, m, reloader))
+
+	if err := lc.Run(ctx); err != nil {
+		logger.Fatal(err)
+	}
+}
 
-	db := prepDB(logger)
-	s := prepServer(logger, db, ctx, wg)
+// prepConfig load the config of the --config file, the env params and the flags, then configure the logger with it.
+func prepConfig(l *logrus.Logger) *config.Config {
+	flags := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	config.RegisterFlags(flags)
+	flags.Parse(os.Args[1:])
 
-	g, err := prepGrpc(logger, db, ctx, wg)
+	cfg, err := config.Load(flags)
 	if err != nil {
-		logger.Warningf("gRPC server failed to start: %s", err)
+		l.Fatal(err)
 	}
 
-	wg.Add(2)
+	level, _ := logrus.ParseLevel(cfg.Log.Level)
+	l.SetLevel(level)
 
-	go s.Serve()
-	go g.Serve()
+	if cfg.Log.Format == "json" {
+		l.SetFormatter(&logrus.JSONFormatter{})
+	}
 
-	wg.Wait()
+	return cfg
 }
 
-// prepDB prepare the database.
-func prepDB(l *logrus.Logger) *db.Instance {
-	conn, err := db.NewConnection()
+// prepDB prepare the database, an unreachable one only fails the readiness checks. It's closed last.
+func prepDB(l *logrus.Logger, ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) *db.Instance {
+	conn, err := db.NewConnection(cfg.DB)
 	if err != nil {
 		l.Fatal(err)
 	}
 
 	instance := db.NewInstance(conn, l)
-	l.Log(logrus.InfoLevel, "DB conn: ", instance.Health())
+	if err := instance.Health(ctx); err != nil {
+		l.Warning(err)
+	}
+
+	lc.Close("db", instance.Close)
 
 	return instance
 }
 
-// prepServer prepare the HTTP server.
-func prepServer(l *logrus.Logger, db *db.Instance, ctx context.Context, wg *sync.WaitGroup) *http.Serve {
-	controller := controllers.NewStockController(l, db, ctx)
+// prepTracing prepare the tracing of the servers and the database, flushing the spans left on shutdown.
+// The trace ids are added to the log entries of the traced calls.
+func prepTracing(l *logrus.Logger, db *db.Instance, ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) {
+	provider, err := tracing.FromConfig(ctx, l, cfg.Tracing)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	l.AddHook(tracing.LogHook{})
 
-	return http.NewServe(controller, l, wg)
+	if provider == nil {
+		l.Warning("tracing is disabled")
+		return
+	}
+
+	db.Base.AddQueryHook(tracing.QueryHook())
+	lc.Close("tracing", provider.Shutdown)
 }
 
-// prepGrpc prepare the gRPC server.
-func prepGrpc(l *logrus.Logger, db *db.Instance, ctx context.Context, wg *sync.WaitGroup) (*grpc.Serve, error) {
-	sPort, ok := os.LookupEnv("GRPC_PORT")
-	if !ok {
-		return nil, errors.New("failed to start gRPC server, missing port")
+// prepMetrics prepare the metrics of both servers and the database, nil if the metrics are disabled.
+// It must run before the first queries, as it hooks into them.
+func prepMetrics(l *logrus.Logger, db *db.Instance, cfg *config.Config, lc *lifecycle.Manager) *metrics.Metrics {
+	m := metrics.FromConfig(l, db, cfg.Metrics)
+	if m == nil {
+		l.Warning("metrics are disabled")
+		return nil
+	}
+
+	lc.Go("inventory metrics", m.Run)
+
+	return m
+}
+
+// prepHealth prepare the health checks of both servers.
+func prepHealth(l *logrus.Logger, db *db.Instance, lc *lifecycle.Manager) *health.Checker {
+	checker, err := health.FromDB(l, db)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	lc.Go("health checks", checker.Run)
+
+	// Ready once both servers listen, not ready as soon as they start draining.
+	lc.OnReady(func() { checker.SetReady(true) })
+	lc.OnShutdown(checker.Shutdown)
+
+	return checker
+}
+
+// prepAuth prepare the authenticator of both servers, nil if the authentication is disabled.
+func prepAuth(l *logrus.Logger, db *db.Instance, cfg *config.Config) *auth.Authenticator {
+	authenticator, err := auth.FromConfig(l, db, cfg.Auth)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	if authenticator == nil {
+		l.Warning("authentication is disabled, every call is served anonymously")
+	}
+
+	return authenticator
+}
+
+// prepPolicy prepare the access policy of both servers.
+func prepPolicy(l *logrus.Logger, cfg *config.Config) *policy.Engine {
+	p, err := policy.FromConfig(cfg.Policy)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	return policy.NewEngine(p)
+}
+
+// prepIdempotency prepare the idempotency guard of both servers, purging the expired records.
+func prepIdempotency(l *logrus.Logger, db *db.Instance, cfg *config.Config, lc *lifecycle.Manager) *idempotency.Guard {
+	guard := idempotency.FromConfig(l, db, cfg.Idempotency)
+
+	lc.Go("idempotency record purge", guard.Run)
+
+	return guard
+}
+
+// prepRateLimit prepare the rate limiter of both servers, nil if the rate limiting is disabled.
+func prepRateLimit(l *logrus.Logger, db *db.Instance, cfg *config.Config, lc *lifecycle.Manager) *ratelimit.Limiter {
+	limiter, err := ratelimit.FromConfig(l, db, cfg.RateLimit)
+	if err != nil {
+		l.Fatal(err)
 	}
 
-	port := 0
-	var err error
+	if limiter == nil {
+		l.Warning("rate limiting is disabled")
+		return nil
+	}
+
+	lc.Go("rate limit bucket purge", limiter.Run)
+
+	return limiter
+}
+
+// prepBroker prepare the stock event broker, shared by both servers.
+func prepBroker(l *logrus.Logger, db *db.Instance, lc *lifecycle.Manager) *events.Broker {
+	broker := events.FromDB(l, db)
+
+	lc.Go("stock event broker", broker.Run)
 
-	if port, err = strconv.Atoi(sPort); err != nil {
-		return nil, errors.New("failed to start gRPC server, faulty port")
+	return broker
+}
+
+// prepDispatcher prepare the webhook dispatcher, relaying the outbox events. If the broker relay runs too, the events
+// are only purged once published.
+func prepDispatcher(l *logrus.Logger, db *db.Instance, relayed bool, lc *lifecycle.Manager) {
+	dispatcher := webhooks.FromDB(l, db, relayed)
+
+	lc.Go("webhook dispatcher", dispatcher.Run)
+}
+
+// prepRelay prepare the outbox relay to the message broker, if one is configured, reporting whether it runs.
+func prepRelay(l *logrus.Logger, db *db.Instance, cfg *config.Config, lc *lifecycle.Manager) bool {
+	publisher, err := publishers.FromConfig(cfg.Publisher)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	if publisher == nil {
+		return false
 	}
 
+	relay := publishers.NewRelay(l, db, publisher)
+
+	lc.Go("outbox relay", relay.Run)
+
+	return true
+}
+
+// prepCerts prepare the TLS certificate of both servers, reloaded as it's renewed on disk. Nil if TLS is disabled.
+func prepCerts(l *logrus.Logger, cfg *config.Config, lc *lifecycle.Manager) *certs.Reloader {
+	reloader, err := certs.FromConfig(l, cfg.TLS)
+	if err != nil {
+		l.Fatal(err)
+	}
+
+	if reloader == nil {
+		l.Warning("TLS is disabled, both servers are served in plaintext")
+		return nil
+	}
+
+	lc.Go("TLS certificate reload", reloader.Run)
+
+	return reloader
+}
+
+// prepServer prepare the HTTP server.
+func prepServer(
+	l *logrus.Logger,
+	db *db.Instance,
+	ctx context.Context,
+	cfg *config.Config,
+	broker *events.Broker,
+	authenticator *auth.Authenticator,
+	engine *policy.Engine,
+	guard *idempotency.Guard,
+	limiter *ratelimit.Limiter,
+	checker *health.Checker,
+	m *metrics.Metrics,
+	reloader *certs.Reloader,
+) *http.Serve {
+	gw, err := prepGateway(l, ctx, cfg, reloader)
+	if err != nil {
+		l.Warningf("REST gateway failed to start: %s", err)
+	}
+
+	return http.NewServe(cfg, l, http.Options{
+		Stocks:        controllers.NewStockController(l, db, ctx, engine),
+		Feed:          controllers.NewFeedController(l, broker, engine),
+		Webhooks:      controllers.NewWebhookController(l, db, engine),
+		GraphQL:       controllers.NewGraphQLController(l, db, ctx, broker, engine),
+		Gateway:       gw,
+		Authenticator: authenticator,
+		Idempotency:   guard,
+		Limiter:       limiter,
+		Checker:       checker,
+		Metrics:       m,
+		Certs:         reloader,
+	})
+}
+
+// prepGateway prepare the REST gateway, dialing the gRPC server over TLS if it's served over it.
+func prepGateway(l *logrus.Logger, ctx context.Context, cfg *config.Config, reloader *certs.Reloader) (stdhttp.Handler, error) {
+	var tlsConfig *tls.Config
+	if reloader != nil {
+		tlsConfig = reloader.Gateway()
+	}
+
+	return gateway.NewGateway(ctx, l, cfg.GRPC.Target(), tlsConfig)
+}
+
+// prepGrpc prepare the gRPC server.
+func prepGrpc(
+	l *logrus.Logger,
+	db *db.Instance,
+	ctx context.Context,
+	cfg *config.Config,
+	broker *events.Broker,
+	authenticator *auth.Authenticator,
+	engine *policy.Engine,
+	guard *idempotency.Guard,
+	limiter *ratelimit.Limiter,
+	checker *health.Checker,
+	m *metrics.Metrics,
+	reloader *certs.Reloader,
+) *grpc.Serve {
 	logEntry := logrus.NewEntry(l)
 
 	opts := []rpc.ServerOption{
-		rpc.StreamInterceptor(grpc_logrus.StreamServerInterceptor(logEntry)),
-		rpc.UnaryInterceptor(grpc_logrus.UnaryServerInterceptor(logEntry)),
+		rpc.ChainStreamInterceptor(
+			grpc_logrus.StreamServerInterceptor(logEntry),
+			grpc.StreamTracingInterceptor(),
+			grpc.StreamMetricsInterceptor(m),
+			grpc.StreamAuthInterceptor(l, authenticator, reloader),
+			grpc.StreamRateLimitInterceptor(limiter),
+		),
+		rpc.ChainUnaryInterceptor(
+			grpc_logrus.UnaryServerInterceptor(logEntry),
+			grpc.UnaryTracingInterceptor(),
+			grpc.UnaryMetricsInterceptor(m),
+			grpc.UnaryAuthInterceptor(l, authenticator, reloader),
+			grpc.UnaryRateLimitInterceptor(limiter),
+			grpc.UnaryIdempotencyInterceptor(l, guard),
+		),
 	}
 
-	handler := handlers.NewStockHandler(l, db, ctx)
+	handler := handlers.NewStockHandler(l, db, ctx, broker, engine)
 
-	return grpc.NewServe(int64(port), l, &opts, handler, wg), nil
+	return grpc.NewServe(cfg, l, &opts, handler, checker, reloader)
 }
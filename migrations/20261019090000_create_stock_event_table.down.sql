@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS stock_event_trigger ON stock;
+
+--bun:split
+
+DROP FUNCTION IF EXISTS record_stock_event();
+
+--bun:split
+
+DROP TABLE IF EXISTS stock_event;
@@ -0,0 +1,52 @@
+CREATE TABLE stock_event
+(
+    seq        bigserial   NOT NULL PRIMARY KEY,
+    type       varchar     NOT NULL,
+    stock_id   uuid        NOT NULL,
+    stock      jsonb       NOT NULL,
+    created_at timestamp   NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX stock_event_stock_id_idx ON stock_event (stock_id);
+
+--bun:split
+
+CREATE FUNCTION record_stock_event() RETURNS trigger AS
+$$
+DECLARE
+    r         stock;
+    event_seq bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+
+    INSERT INTO stock_event (type, stock_id, stock)
+    VALUES (CASE TG_OP WHEN 'INSERT' THEN 'CREATED' WHEN 'UPDATE' THEN 'UPDATED' ELSE 'DELETED' END,
+            r.id,
+            jsonb_build_object(
+                    'id', r.id,
+                    'name', r.name,
+                    'quantity', r.quantity,
+                    'created_at', r.created_at AT TIME ZONE 'UTC',
+                    'updated_at', r.updated_at AT TIME ZONE 'UTC'
+                ))
+    RETURNING stock_event.seq INTO event_seq;
+
+    PERFORM pg_notify('stock_events', event_seq::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--bun:split
+
+CREATE TRIGGER stock_event_trigger
+    AFTER INSERT OR UPDATE OR DELETE
+    ON stock
+    FOR EACH ROW
+EXECUTE FUNCTION record_stock_event();
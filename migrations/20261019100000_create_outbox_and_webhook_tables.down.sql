@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS webhook_dead_letter;
+
+--bun:split
+
+DROP TABLE IF EXISTS webhook_delivery;
+
+--bun:split
+
+DROP TABLE IF EXISTS webhook_subscription;
+
+--bun:split
+
+DROP TABLE IF EXISTS outbox;
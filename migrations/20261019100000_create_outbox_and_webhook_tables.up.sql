@@ -0,0 +1,57 @@
+CREATE TABLE outbox
+(
+    id            uuid      NOT NULL PRIMARY KEY,
+    stock_id      uuid      NOT NULL,
+    type          varchar   NOT NULL,
+    stock         jsonb     NOT NULL,
+    created_at    timestamp NOT NULL DEFAULT current_timestamp,
+    dispatched_at timestamp
+);
+
+--bun:split
+
+CREATE INDEX outbox_pending_dispatch_idx ON outbox (created_at) WHERE dispatched_at IS NULL;
+
+--bun:split
+
+CREATE TABLE webhook_subscription
+(
+    id          uuid      NOT NULL PRIMARY KEY,
+    url         varchar   NOT NULL,
+    secret      varchar   NOT NULL,
+    event_types varchar[] NOT NULL DEFAULT '{}',
+    active      boolean   NOT NULL DEFAULT true,
+    created_at  timestamp NOT NULL DEFAULT current_timestamp,
+    updated_at  timestamp NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE TABLE webhook_delivery
+(
+    id              uuid      NOT NULL PRIMARY KEY,
+    subscription_id uuid      NOT NULL REFERENCES webhook_subscription (id) ON DELETE CASCADE,
+    event_id        uuid      NOT NULL REFERENCES outbox (id) ON DELETE CASCADE,
+    attempts        int       NOT NULL DEFAULT 0,
+    next_attempt_at timestamp NOT NULL DEFAULT current_timestamp,
+    last_error      varchar,
+    created_at      timestamp NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX webhook_delivery_due_idx ON webhook_delivery (next_attempt_at);
+
+--bun:split
+
+CREATE TABLE webhook_dead_letter
+(
+    id              uuid      NOT NULL PRIMARY KEY,
+    subscription_id uuid      NOT NULL,
+    event_id        uuid      NOT NULL,
+    url             varchar   NOT NULL,
+    payload         jsonb     NOT NULL,
+    attempts        int       NOT NULL,
+    last_error      varchar,
+    created_at      timestamp NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_pending_publish_idx;
+
+--bun:split
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS published_at;
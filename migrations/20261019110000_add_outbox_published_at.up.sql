@@ -0,0 +1,5 @@
+ALTER TABLE outbox ADD COLUMN published_at timestamp;
+
+--bun:split
+
+CREATE INDEX outbox_pending_publish_idx ON outbox (created_at) WHERE published_at IS NULL;
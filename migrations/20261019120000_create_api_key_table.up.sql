@@ -0,0 +1,11 @@
+CREATE TABLE api_key
+(
+    id         uuid      NOT NULL PRIMARY KEY,
+    name       varchar   NOT NULL,
+    prefix     varchar   NOT NULL UNIQUE,
+    hash       varchar   NOT NULL,
+    roles      varchar[] NOT NULL DEFAULT '{}',
+    expires_at timestamp,
+    revoked_at timestamp,
+    created_at timestamp NOT NULL DEFAULT current_timestamp
+);
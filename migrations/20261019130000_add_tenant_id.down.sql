@@ -0,0 +1,69 @@
+DROP POLICY IF EXISTS stock_tenant_isolation ON stock;
+
+--bun:split
+
+ALTER TABLE stock DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE OR REPLACE FUNCTION record_stock_event() RETURNS trigger AS
+$$
+DECLARE
+    r         stock;
+    event_seq bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+
+    INSERT INTO stock_event (type, stock_id, stock)
+    VALUES (CASE TG_OP WHEN 'INSERT' THEN 'CREATED' WHEN 'UPDATE' THEN 'UPDATED' ELSE 'DELETED' END,
+            r.id,
+            jsonb_build_object(
+                    'id', r.id,
+                    'name', r.name,
+                    'quantity', r.quantity,
+                    'created_at', r.created_at AT TIME ZONE 'UTC',
+                    'updated_at', r.updated_at AT TIME ZONE 'UTC'
+                ))
+    RETURNING stock_event.seq INTO event_seq;
+
+    PERFORM pg_notify('stock_events', event_seq::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--bun:split
+
+ALTER TABLE api_key DROP COLUMN IF EXISTS tenant_id;
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter DROP COLUMN IF EXISTS tenant_id;
+
+--bun:split
+
+DROP INDEX IF EXISTS webhook_subscription_tenant_id_idx;
+
+--bun:split
+
+ALTER TABLE webhook_subscription DROP COLUMN IF EXISTS tenant_id;
+
+--bun:split
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS tenant_id;
+
+--bun:split
+
+ALTER TABLE stock_event DROP COLUMN IF EXISTS tenant_id;
+
+--bun:split
+
+DROP INDEX IF EXISTS stock_tenant_id_idx;
+
+--bun:split
+
+ALTER TABLE stock DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,72 @@
+ALTER TABLE stock ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+CREATE INDEX stock_tenant_id_idx ON stock (tenant_id);
+
+--bun:split
+
+ALTER TABLE stock_event ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+ALTER TABLE outbox ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+ALTER TABLE webhook_subscription ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+CREATE INDEX webhook_subscription_tenant_id_idx ON webhook_subscription (tenant_id);
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+ALTER TABLE api_key ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+--bun:split
+
+CREATE OR REPLACE FUNCTION record_stock_event() RETURNS trigger AS
+$$
+DECLARE
+    r         stock;
+    event_seq bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        r := OLD;
+    ELSE
+        r := NEW;
+    END IF;
+
+    INSERT INTO stock_event (type, stock_id, tenant_id, stock)
+    VALUES (CASE TG_OP WHEN 'INSERT' THEN 'CREATED' WHEN 'UPDATE' THEN 'UPDATED' ELSE 'DELETED' END,
+            r.id,
+            r.tenant_id,
+            jsonb_build_object(
+                    'id', r.id,
+                    'name', r.name,
+                    'quantity', r.quantity,
+                    'created_at', r.created_at AT TIME ZONE 'UTC',
+                    'updated_at', r.updated_at AT TIME ZONE 'UTC'
+                ))
+    RETURNING stock_event.seq INTO event_seq;
+
+    PERFORM pg_notify('stock_events', event_seq::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--bun:split
+
+ALTER TABLE stock ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY stock_tenant_isolation ON stock
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
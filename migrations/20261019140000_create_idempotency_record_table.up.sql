@@ -0,0 +1,17 @@
+CREATE TABLE idempotency_record
+(
+    tenant_id    varchar   NOT NULL,
+    key          varchar   NOT NULL,
+    fingerprint  varchar   NOT NULL,
+    status       int       NOT NULL DEFAULT 0,
+    headers      jsonb,
+    response     bytea,
+    created_at   timestamp NOT NULL DEFAULT current_timestamp,
+    completed_at timestamp,
+    expires_at   timestamp NOT NULL,
+    PRIMARY KEY (tenant_id, key)
+);
+
+--bun:split
+
+CREATE INDEX idempotency_record_expires_at_idx ON idempotency_record (expires_at);
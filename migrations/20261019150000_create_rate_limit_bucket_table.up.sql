@@ -0,0 +1,10 @@
+CREATE TABLE rate_limit_bucket
+(
+    key        varchar          NOT NULL PRIMARY KEY,
+    tokens     double precision NOT NULL,
+    updated_at timestamp        NOT NULL DEFAULT current_timestamp
+);
+
+--bun:split
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON rate_limit_bucket (updated_at);
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS stock_inventory(bigint);
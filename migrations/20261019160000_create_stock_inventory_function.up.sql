@@ -0,0 +1,20 @@
+-- stock_inventory sums up the stock of every tenant for the metrics. It runs with the rights of its owner,
+-- so that the row-level security policies don't hide the other tenants.
+CREATE OR REPLACE FUNCTION stock_inventory(low_stock_threshold bigint)
+    RETURNS TABLE
+            (
+                tenant_id varchar,
+                skus      bigint,
+                units     bigint,
+                low_stock bigint
+            )
+AS
+$$
+SELECT stock.tenant_id,
+       count(*),
+       coalesce(sum(stock.quantity), 0)::bigint,
+       count(*) FILTER (WHERE stock.quantity <= low_stock_threshold)
+FROM stock
+GROUP BY stock.tenant_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER
+                    SET search_path = public;
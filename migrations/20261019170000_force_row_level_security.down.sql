@@ -0,0 +1,71 @@
+DROP POLICY IF EXISTS idempotency_record_tenant_isolation ON idempotency_record;
+
+--bun:split
+
+ALTER TABLE idempotency_record NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE idempotency_record DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+DROP POLICY IF EXISTS webhook_dead_letter_tenant_isolation ON webhook_dead_letter;
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+DROP POLICY IF EXISTS webhook_subscription_tenant_isolation ON webhook_subscription;
+
+--bun:split
+
+ALTER TABLE webhook_subscription NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE webhook_subscription DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+DROP POLICY IF EXISTS outbox_tenant_isolation ON outbox;
+
+--bun:split
+
+ALTER TABLE outbox NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE outbox DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+DROP POLICY IF EXISTS stock_event_tenant_isolation ON stock_event;
+
+--bun:split
+
+ALTER TABLE stock_event NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE stock_event DISABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE stock NO FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+DROP POLICY IF EXISTS stock_tenant_isolation ON stock;
+
+--bun:split
+
+CREATE POLICY stock_tenant_isolation ON stock
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,83 @@
+-- The tables are owned by the app's role, which bypasses the policies unless they're forced. The background jobs
+-- working across the tenants set app.all_tenants instead of app.tenant_id.
+DROP POLICY IF EXISTS stock_tenant_isolation ON stock;
+
+--bun:split
+
+CREATE POLICY stock_tenant_isolation ON stock
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE stock FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE stock_event ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY stock_event_tenant_isolation ON stock_event
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE stock_event FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY outbox_tenant_isolation ON outbox
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE webhook_subscription ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY webhook_subscription_tenant_isolation ON webhook_subscription
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE webhook_subscription FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY webhook_dead_letter_tenant_isolation ON webhook_dead_letter
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE webhook_dead_letter FORCE ROW LEVEL SECURITY;
+
+--bun:split
+
+ALTER TABLE idempotency_record ENABLE ROW LEVEL SECURITY;
+
+--bun:split
+
+CREATE POLICY idempotency_record_tenant_isolation ON idempotency_record
+    USING (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true) OR current_setting('app.all_tenants', true) = 'on');
+
+--bun:split
+
+ALTER TABLE idempotency_record FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,21 @@
+-- stock_inventory sums up the stock of every tenant for the metrics. Row-level security is forced on its owner too,
+-- so it works across the tenants the way the background jobs do.
+CREATE OR REPLACE FUNCTION stock_inventory(low_stock_threshold bigint)
+    RETURNS TABLE
+            (
+                tenant_id varchar,
+                skus      bigint,
+                units     bigint,
+                low_stock bigint
+            )
+AS
+$$
+SELECT stock.tenant_id,
+       count(*),
+       coalesce(sum(stock.quantity), 0)::bigint,
+       count(*) FILTER (WHERE stock.quantity <= low_stock_threshold)
+FROM stock
+GROUP BY stock.tenant_id;
+$$ LANGUAGE sql STABLE SECURITY DEFINER
+                    SET search_path = public
+                    SET app.all_tenants = 'on';
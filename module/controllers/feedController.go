@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+	"stocks-api/module/validators"
+)
+
+const (
+	// heartbeatInterval how often idle connections are pinged, keeping proxies from closing them.
+	heartbeatInterval = 15 * time.Second
+	// wsWriteTimeout bounds a single WebSocket write.
+	wsWriteTimeout = 10 * time.Second
+)
+
+// EventWatcher a contract to the stock event broker.
+type EventWatcher interface {
+	Watch(ctx context.Context, fromSeq int64, stockIDs []uuid.UUID, fn func(event *entities.StockEvent) error) error
+}
+
+// FeedController streams the stock changes to browser clients, over SSE or WebSocket.
+type FeedController struct {
+	logger   *logrus.Logger
+	watcher  EventWatcher
+	policy   *policy.Engine
+	upgrader websocket.Upgrader
+}
+
+// NewFeedController a constructor for the FeedController.
+func NewFeedController(l *logrus.Logger, watcher EventWatcher, engine *policy.Engine) *FeedController {
+	return &FeedController{
+		logger:  l,
+		watcher: watcher,
+		policy:  engine,
+	}
+}
+
+// Stream serves the change feed as Server-Sent Events.
+// Resumes after the Last-Event-ID header, or the from_sequence query param, and filters by the stock_id query params.
+func (f *FeedController) Stream(w http.ResponseWriter, r *http.Request) {
+	if err := f.policy.Authorize(r.Context(), policy.WatchStock); err != nil {
+		WriteProblem(f.logger, w, r, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteProblem(f.logger, w, r, errors.New("streaming unsupported"))
+		return
+	}
+
+	fromSeq, ids, err := parseFeedFilters(r)
+	if err != nil {
+		WriteProblem(f.logger, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err = f.watch(r.Context(), fromSeq, ids,
+		func(event *entities.StockEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data); err != nil {
+				return err
+			}
+
+			flusher.Flush()
+
+			return nil
+		},
+		func() error {
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return err
+			}
+
+			flusher.Flush()
+
+			return nil
+		},
+	)
+	if err != nil {
+		f.logger.Errorf("SSE feed closed: %s", err)
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", closeReason(err))
+		flusher.Flush()
+	}
+}
+
+// Socket serves the change feed over a WebSocket, one JSON message per event.
+// Resumes after the from_sequence query param and filters by the stock_id query params.
+func (f *FeedController) Socket(w http.ResponseWriter, r *http.Request) {
+	if err := f.policy.Authorize(r.Context(), policy.WatchStock); err != nil {
+		WriteProblem(f.logger, w, r, err)
+		return
+	}
+
+	fromSeq, ids, err := parseFeedFilters(r)
+	if err != nil {
+		WriteProblem(f.logger, w, r, err)
+		return
+	}
+
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		f.logger.Errorf("WebSocket upgrade failed: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// The read loop handles the pongs and notices the client going away.
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	})
+
+	go func() {
+		defer cancel()
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = f.watch(ctx, fromSeq, ids,
+		func(event *entities.StockEvent) error {
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+			return conn.WriteJSON(event)
+		},
+		func() error {
+			return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+		},
+	)
+	if err != nil {
+		f.logger.Errorf("WebSocket feed closed: %s", err)
+		conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, closeReason(err)),
+			time.Now().Add(wsWriteTimeout),
+		)
+	}
+}
+
+// watch runs the broker subscription, interleaving the events with heartbeats on the caller's goroutine.
+func (f *FeedController) watch(
+	ctx context.Context,
+	fromSeq int64,
+	ids []uuid.UUID,
+	send func(event *entities.StockEvent) error,
+	heartbeat func() error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan *entities.StockEvent)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- f.watcher.Watch(ctx, fromSeq, ids, func(event *entities.StockEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// closeReason the reason a feed closed, as told to the client. Internal errors are withheld, as by WriteProblem.
+func closeReason(err error) string {
+	if errs.KindOf(err) == errs.Internal {
+		return "the feed failed, resume from the last received sequence"
+	}
+
+	return err.Error()
+}
+
+func parseFeedFilters(r *http.Request) (int64, []uuid.UUID, error) {
+	query := r.URL.Query()
+
+	rawSeq := r.Header.Get("Last-Event-ID")
+	if rawSeq == "" {
+		rawSeq = query.Get("from_sequence")
+	}
+
+	var fromSeq int64
+
+	if rawSeq != "" {
+		seq, err := strconv.ParseInt(rawSeq, 10, 64)
+		if err != nil || seq < 0 {
+			return 0, nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid sequence: '%s'", rawSeq)).On("from_sequence")
+		}
+
+		fromSeq = seq
+	}
+
+	ids := make([]uuid.UUID, 0, len(query["stock_id"]))
+
+	for _, id := range query["stock_id"] {
+		if err := val.New().Struct(validators.GetStock{ID: id}); err != nil {
+			return 0, nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid stock_id: '%s'", id)).On("stock_id")
+		}
+
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	return fromSeq, ids, nil
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/policy"
+	"stocks-api/module/resolvers"
+	"stocks-api/support/db"
+)
+
+// graphqlRequest the body of a GraphQL request.
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLController serves the GraphQL schema, the subscriptions over Server-Sent Events.
+type GraphQLController struct {
+	logger   *logrus.Logger
+	resolver *resolvers.Resolver
+	schema   *graphql.Schema
+}
+
+// NewGraphQLController a constructor for the GraphQLController.
+func NewGraphQLController(
+	l *logrus.Logger,
+	db *db.Instance,
+	ctx context.Context,
+	watcher EventWatcher,
+	engine *policy.Engine,
+) *GraphQLController {
+	resolver := resolvers.NewResolver(l, db, ctx, watcher, engine)
+
+	return &GraphQLController{
+		logger:   l,
+		resolver: resolver,
+		schema:   resolver.Schema(),
+	}
+}
+
+// Serve executes a query or mutation, or streams a subscription when the client accepts text/event-stream.
+func (g *GraphQLController) Serve(w http.ResponseWriter, r *http.Request) {
+	req, err := reqToGraphql(r)
+	if err != nil {
+		WriteProblem(g.logger, w, r, malformed(err))
+		return
+	}
+
+	ctx := g.resolver.Loaders(r.Context())
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		g.subscribe(ctx, w, r, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// subscribe streams the responses of an operation as "next" events, followed by a "complete" one.
+func (g *GraphQLController) subscribe(ctx context.Context, w http.ResponseWriter, r *http.Request, req *graphqlRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteProblem(g.logger, w, r, errors.New("streaming unsupported"))
+		return
+	}
+
+	responses, err := g.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		WriteProblem(g.logger, w, r, malformed(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case res, ok := <-responses:
+			if !ok {
+				fmt.Fprint(w, "event: complete\ndata: \n\n")
+				flusher.Flush()
+				return
+			}
+
+			data, err := json.Marshal(res)
+			if err != nil {
+				g.logger.Errorf("Failed to encode a GraphQL response: %s", err)
+				return
+			}
+
+			fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func reqToGraphql(r *http.Request) (*graphqlRequest, error) {
+	req := graphqlRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	if req.Query == "" {
+		return nil, errors.New("query is required")
+	}
+
+	return &req, nil
+}
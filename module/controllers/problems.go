@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/errs"
+)
+
+// problemContentType the media type of the RFC 7807 error responses.
+const problemContentType = "application/problem+json"
+
+// problem an RFC 7807 problem details body.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []invalidParam `json:"invalid_params,omitempty"`
+}
+
+// invalidParam a field failing validation, the RFC 7807 extension member.
+type invalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// problemType the type URI and HTTP status of a kind of domain error.
+type problemType struct {
+	uri    string
+	status int
+}
+
+var problemTypes = map[errs.Kind]problemType{
+	errs.Internal:          {"/problems/internal", http.StatusInternalServerError},
+	errs.NotFound:          {"/problems/not-found", http.StatusNotFound},
+	errs.Conflict:          {"/problems/conflict", http.StatusConflict},
+	errs.InvalidArgument:   {"/problems/malformed-request", http.StatusBadRequest},
+	errs.Aborted:           {"/problems/aborted", http.StatusConflict},
+	errs.Unauthenticated:   {"/problems/unauthenticated", http.StatusUnauthorized},
+	errs.PermissionDenied:  {"/problems/forbidden", http.StatusForbidden},
+	errs.ResourceExhausted: {"/problems/rate-limited", http.StatusTooManyRequests},
+}
+
+// validationProblem the type of the invalid arguments that are well-formed but fail validation.
+var validationProblem = problemType{"/problems/validation", http.StatusUnprocessableEntity}
+
+// malformed classifies an error decoding the request as an invalid argument.
+func malformed(err error) error {
+	return errs.Wrap(errs.InvalidArgument, err)
+}
+
+// WriteProblem writes an error as problem+json, with the status of its kind.
+// Internal errors are logged and their details withheld from the client.
+func WriteProblem(l *logrus.Logger, w http.ResponseWriter, r *http.Request, err error) {
+	kind := errs.KindOf(err)
+
+	pt := problemTypes[kind]
+	if kind == errs.InvalidArgument && errs.IsValidation(err) {
+		pt = validationProblem
+	}
+
+	p := problem{
+		Type:     pt.uri,
+		Title:    http.StatusText(pt.status),
+		Status:   pt.status,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+	}
+
+	if kind == errs.Internal {
+		l.WithContext(r.Context()).Error(err)
+		p.Detail = ""
+	}
+
+	if kind == errs.InvalidArgument {
+		for _, v := range errs.Violations(err) {
+			p.InvalidParams = append(p.InvalidParams, invalidParam{Name: v.Field, Reason: v.Description})
+		}
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(pt.status)
+	json.NewEncoder(w).Encode(p)
+}
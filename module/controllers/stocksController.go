@@ -4,14 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"slices"
+	"strconv"
 
 	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"stocks-api/module/entities"
 	"stocks-api/module/entities/filters"
+	"stocks-api/module/errs"
+	"stocks-api/module/exporters"
+	"stocks-api/module/policy"
 	"stocks-api/module/services"
 	"stocks-api/module/validators"
 	"stocks-api/support/db"
@@ -27,12 +34,32 @@ const (
 
 // A contract to the StockService for high level logic operations.
 type StockService interface {
-	GetAll(ctx context.Context, filters *filters.Pagination) ([]*entities.Stock, error)
+	GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error)
 	GetOne(ctx context.Context, stockId string) (*entities.Stock, error)
 	InsertOne(ctx context.Context, stock *entities.Stock) error
 	UpdateOne(ctx context.Context, stock *entities.Stock, stockId string) error
+	PatchOne(ctx context.Context, stock *entities.Stock, stockId string, fields []string) error
 	DeleteOne(ctx context.Context, stockId string) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, filter *filters.StockFilter) (int, error)
+	Export(ctx context.Context, filter *filters.StockFilter, fn func(stock *entities.Stock) error) error
+	InsertMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+	UpdateMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+	DeleteMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+}
+
+// exportFlushEvery the number of exported items after which the response is flushed to the client.
+const exportFlushEvery = 500
+
+const (
+	defaultPage         = 1
+	defaultItemsPerPage = 20
+)
+
+// batchRequest the body of the batch endpoints.
+type batchRequest struct {
+	Stocks     []*entities.Stock `json:"stocks"`
+	IDs        []string          `json:"ids"`
+	BestEffort bool              `json:"best_effort"`
 }
 
 // StockController handles the business logic when an endpoint is hit.
@@ -40,37 +67,44 @@ type StockController struct {
 	logger  *logrus.Logger
 	db      *db.Instance
 	service StockService
+	policy  *policy.Engine
 	ctx     context.Context
 }
 
 // NewStockController a constructor for the StockController.
-func NewStockController(l *logrus.Logger, db *db.Instance, ctx context.Context) *StockController {
+func NewStockController(l *logrus.Logger, db *db.Instance, ctx context.Context, engine *policy.Engine) *StockController {
 	return &StockController{
 		logger:  l,
 		db:      db,
 		service: services.NewStockService(l, db, ctx),
+		policy:  engine,
 		ctx:     ctx,
 	}
 }
 
 // GetAll returns all records in the database.
 func (s *StockController) GetAll(w http.ResponseWriter, req *http.Request) {
+	if err := s.policy.Authorize(req.Context(), policy.ReadStock); err != nil {
+		WriteProblem(s.logger, w, req, err)
+		return
+	}
+
 	pagination, errParse := parsePagination(req)
 	if errParse != nil {
-		s.logger.Errorf("Failed to parse pagination: %s", errParse.Error())
-
-		json.NewEncoder(w).Encode(errors.New("Failed to parse pagination"))
+		WriteProblem(s.logger, w, req, errParse)
+		return
 	}
 
-	res, errGet := s.service.GetAll(s.ctx, pagination)
+	res, errGet := s.service.GetAll(req.Context(), pagination, nil)
 	if errGet != nil {
-		json.NewEncoder(w).Encode(errGet)
+		WriteProblem(s.logger, w, req, errGet)
+		return
 	}
 
-	count, errCount := s.service.Count(s.ctx)
+	count, errCount := s.service.Count(req.Context(), nil)
 	if errCount != nil {
-		s.logger.Error(errCount)
-		json.NewEncoder(w).Encode(errCount)
+		WriteProblem(s.logger, w, req, errCount)
+		return
 	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -79,13 +113,57 @@ func (s *StockController) GetAll(w http.ResponseWriter, req *http.Request) {
 	})
 }
 
+// List returns a page of the records matching the query string filters.
+func (s *StockController) List(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.ReadStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	pagination, errPagination := parseQueryPagination(r)
+	if errPagination != nil {
+		WriteProblem(s.logger, w, r, errPagination)
+		return
+	}
+
+	filter, errFilter := parseFilter(r)
+	if errFilter != nil {
+		WriteProblem(s.logger, w, r, errFilter)
+		return
+	}
+
+	res, errGet := s.service.GetAll(r.Context(), pagination, filter)
+	if errGet != nil {
+		WriteProblem(s.logger, w, r, errGet)
+		return
+	}
+
+	count, errCount := s.service.Count(r.Context(), filter)
+	if errCount != nil {
+		WriteProblem(s.logger, w, r, errCount)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stocks":         res,
+		"page":           pagination.Page,
+		"items_per_page": pagination.ItemsPerPage,
+		"total_count":    count,
+	})
+}
+
 // GetOne returns a single record in the database.
 func (s *StockController) GetOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.ReadStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
 	vars := mux.Vars(r)
 
-	res, errGet := s.service.GetOne(s.ctx, vars["id"])
+	res, errGet := s.service.GetOne(r.Context(), vars["id"])
 	if errGet != nil {
-		w.Write([]byte(errGet.Error()))
+		WriteProblem(s.logger, w, r, errGet)
 		return
 	}
 
@@ -94,29 +172,42 @@ func (s *StockController) GetOne(w http.ResponseWriter, r *http.Request) {
 
 // InsertOne adds a new record to the database.
 func (s *StockController) InsertOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.CreateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
 	stock, errParse := reqToStock(r)
 	if errParse != nil {
-		w.Write([]byte(errParse.Error()))
+		WriteProblem(s.logger, w, r, errParse)
 		return
 	}
 
 	errValidation := validate(stock, insert)
 	if errValidation != nil {
-		w.Write([]byte(errValidation.Error()))
+		WriteProblem(s.logger, w, r, errs.Invalid(errValidation))
 		return
 	}
 
-	if err := s.service.InsertOne(s.ctx, stock); err != nil {
-		w.Write([]byte(err.Error()))
+	if err := s.service.InsertOne(r.Context(), stock); err != nil {
+		WriteProblem(s.logger, w, r, err)
 		return
 	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/stocks/%s", stock.ID))
+	writeStock(w, http.StatusCreated, stock)
 }
 
 // UpdateOne updates a single record in the database.
 func (s *StockController) UpdateOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.UpdateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
 	stock, errParse := reqToStock(r)
 	if errParse != nil {
-		w.Write([]byte(errParse.Error()))
+		WriteProblem(s.logger, w, r, errParse)
 		return
 	}
 
@@ -124,34 +215,275 @@ func (s *StockController) UpdateOne(w http.ResponseWriter, r *http.Request) {
 
 	errValidation := validate(stock, update)
 	if errValidation != nil {
-		w.Write([]byte(errValidation.Error()))
+		WriteProblem(s.logger, w, r, errs.Invalid(errValidation))
 		return
 	}
 
-	if err := s.service.UpdateOne(s.ctx, stock, vars["id"]); err != nil {
-		w.Write([]byte(err.Error()))
+	if err := s.authorizeQuantity(r.Context(), vars["id"], stock.Quantity); err != nil {
+		WriteProblem(s.logger, w, r, err)
 		return
 	}
+
+	if err := s.service.UpdateOne(r.Context(), stock, vars["id"]); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	writeStock(w, http.StatusOK, stock)
+}
+
+// ReplaceOne replaces a single record in the database, all fields are required.
+func (s *StockController) ReplaceOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.UpdateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	stock, errParse := reqToStock(r)
+	if errParse != nil {
+		WriteProblem(s.logger, w, r, errParse)
+		return
+	}
+
+	errValidation := validate(stock, insert)
+	if errValidation != nil {
+		WriteProblem(s.logger, w, r, errs.Invalid(errValidation))
+		return
+	}
+
+	if err := s.authorizeQuantity(r.Context(), mux.Vars(r)["id"], stock.Quantity); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	if err := s.service.UpdateOne(r.Context(), stock, mux.Vars(r)["id"]); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	writeStock(w, http.StatusOK, stock)
+}
+
+// PatchOne applies a JSON Merge Patch (RFC 7396) to a single record, only the given fields are updated.
+func (s *StockController) PatchOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.UpdateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	stock, fields, errParse := reqToPatch(r)
+	if errParse != nil {
+		WriteProblem(s.logger, w, r, errParse)
+		return
+	}
+
+	if slices.Contains(fields, "quantity") {
+		if err := s.authorizeQuantity(r.Context(), mux.Vars(r)["id"], stock.Quantity); err != nil {
+			WriteProblem(s.logger, w, r, err)
+			return
+		}
+	}
+
+	if err := s.service.PatchOne(r.Context(), stock, mux.Vars(r)["id"], fields); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	writeStock(w, http.StatusOK, stock)
 }
 
 // DeleteOne deletes a single record in the database.
 func (s *StockController) DeleteOne(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.DeleteStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
 	vars := mux.Vars(r)
 
-	if err := s.service.DeleteOne(s.ctx, vars["id"]); err != nil {
-		w.Write([]byte(err.Error()))
+	if err := s.service.DeleteOne(r.Context(), vars["id"]); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+}
+
+// InsertMany adds multiple records to the database.
+func (s *StockController) InsertMany(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.CreateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	batch, errParse := reqToBatch(r)
+	if errParse != nil {
+		WriteProblem(s.logger, w, r, errParse)
+		return
+	}
+
+	items := make([]*entities.BatchItem, 0, len(batch.Stocks))
+
+	for _, stock := range batch.Stocks {
+		items = append(items, &entities.BatchItem{
+			Stock: stock,
+			Err:   errs.Invalid(validate(stock, insert)),
+		})
+	}
+
+	results, err := s.service.InsertMany(r.Context(), items, batch.BestEffort)
+	if err != nil {
+		WriteProblem(s.logger, w, r, err)
 		return
 	}
+
+	writeBatchResults(w, results)
+}
+
+// UpdateMany updates multiple records in the database.
+func (s *StockController) UpdateMany(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.UpdateStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	batch, errParse := reqToBatch(r)
+	if errParse != nil {
+		WriteProblem(s.logger, w, r, errParse)
+		return
+	}
+
+	items := make([]*entities.BatchItem, 0, len(batch.Stocks))
+
+	for _, stock := range batch.Stocks {
+		err := val.New().Struct(validators.GetStock{ID: stock.ID.String()})
+		if err == nil {
+			err = validate(stock, update)
+		}
+
+		item := &entities.BatchItem{
+			Stock: stock,
+			Err:   errs.Invalid(err),
+		}
+
+		if item.Err == nil {
+			item.Err = s.authorizeQuantity(r.Context(), stock.ID.String(), stock.Quantity)
+		}
+
+		items = append(items, item)
+	}
+
+	results, err := s.service.UpdateMany(r.Context(), items, batch.BestEffort)
+	if err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	writeBatchResults(w, results)
+}
+
+// DeleteMany deletes multiple records in the database.
+func (s *StockController) DeleteMany(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.DeleteStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	batch, errParse := reqToBatch(r)
+	if errParse != nil {
+		WriteProblem(s.logger, w, r, errParse)
+		return
+	}
+
+	items := make([]*entities.BatchItem, 0, len(batch.IDs))
+
+	for _, id := range batch.IDs {
+		parsed, _ := uuid.Parse(id)
+
+		items = append(items, &entities.BatchItem{
+			Stock: &entities.Stock{ID: parsed},
+			Err:   errs.Invalid(val.New().Struct(validators.GetStock{ID: id})),
+		})
+	}
+
+	results, err := s.service.DeleteMany(r.Context(), items, batch.BestEffort)
+	if err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	writeBatchResults(w, results)
+}
+
+// Export streams all records matching the query filters as a file download.
+func (s *StockController) Export(w http.ResponseWriter, r *http.Request) {
+	if err := s.policy.Authorize(r.Context(), policy.ExportStock); err != nil {
+		WriteProblem(s.logger, w, r, err)
+		return
+	}
+
+	format, errFormat := exporters.ParseFormat(r.URL.Query().Get("format"))
+	if errFormat != nil {
+		WriteProblem(s.logger, w, r, malformed(errFormat))
+		return
+	}
+
+	filter, errFilter := parseFilter(r)
+	if errFilter != nil {
+		WriteProblem(s.logger, w, r, errFilter)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"stocks.%s\"", format))
+
+	encoder, errEncoder := exporters.NewEncoder(format, w)
+	if errEncoder != nil {
+		WriteProblem(s.logger, w, r, errEncoder)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	count := 0
+
+	err := s.service.Export(r.Context(), filter, func(stock *entities.Stock) error {
+		if err := encoder.Encode(stock); err != nil {
+			return err
+		}
+
+		count++
+		if flusher != nil && count%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		// The headers are already sent, the client sees a truncated download.
+		s.logger.Errorf("Failed to export stocks: %s", err)
+		return
+	}
+
+	if err := encoder.Close(); err != nil {
+		s.logger.Errorf("Failed to finalise the export: %s", err)
+	}
+}
+
+// authorizeQuantity authorizes setting the quantity of a stock item, looking up its current one if needed.
+func (s *StockController) authorizeQuantity(ctx context.Context, stockId string, quantity int64) error {
+	return s.policy.AuthorizeQuantity(ctx, quantity, func() (int64, error) {
+		current, err := s.service.GetOne(ctx, stockId)
+		if err != nil {
+			return 0, err
+		}
+
+		return current.Quantity, nil
+	})
 }
 
 func validate(input *entities.Stock, op OpType) error {
 	vl := val.New()
 
 	if op == insert {
-		return vl.Struct(validators.InsertStock{
-			ID:   input.ID.String(),
-			Name: input.Name,
-		})
+		return vl.Struct(validators.NewInsertStock(input))
 	}
 
 	if op == update {
@@ -167,28 +499,171 @@ func validate(input *entities.Stock, op OpType) error {
 func reqToStock(r *http.Request) (*entities.Stock, error) {
 	reqBody, errRead := ioutil.ReadAll(r.Body)
 	if errRead != nil {
-		return nil, errRead
+		return nil, malformed(errRead)
 	}
 
 	stock := entities.Stock{}
 	if err := json.Unmarshal(reqBody, &stock); err != nil {
-		return nil, err
+		return nil, malformed(err)
 	}
 
 	return &stock, nil
 }
 
+// reqToPatch decodes a merge patch, returning the patched fields along with their values.
+// Removing a field (null) isn't supported, as every stock field is mandatory.
+func reqToPatch(r *http.Request) (*entities.Stock, []string, error) {
+	reqBody, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		return nil, nil, malformed(errRead)
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(reqBody, &patch); err != nil {
+		return nil, nil, malformed(err)
+	}
+
+	stock := &entities.Stock{}
+	fields := make([]string, 0, len(patch))
+
+	for field, raw := range patch {
+		if string(raw) == "null" {
+			return nil, nil, errs.New(errs.InvalidArgument, fmt.Sprintf("field '%s' can't be removed", field)).On(field)
+		}
+
+		var err error
+
+		switch field {
+		case "name":
+			err = json.Unmarshal(raw, &stock.Name)
+		case "quantity":
+			err = json.Unmarshal(raw, &stock.Quantity)
+		}
+
+		if err != nil {
+			return nil, nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid %s: %s", field, raw)).On(field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return stock, fields, nil
+}
+
+func reqToBatch(r *http.Request) (*batchRequest, error) {
+	reqBody, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		return nil, malformed(errRead)
+	}
+
+	batch := batchRequest{}
+	if err := json.Unmarshal(reqBody, &batch); err != nil {
+		return nil, malformed(err)
+	}
+
+	if err := val.New().Struct(validators.Batch{Size: len(batch.Stocks) + len(batch.IDs)}); err != nil {
+		return nil, errs.Invalid(err)
+	}
+
+	return &batch, nil
+}
+
+// writeStock writes a stock item as stored, with the given status.
+func writeStock(w http.ResponseWriter, status int, stock *entities.Stock) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(stock)
+}
+
+func writeBatchResults(w http.ResponseWriter, results entities.BatchResults) {
+	response := make([]map[string]interface{}, 0, len(results))
+
+	for _, r := range results {
+		item := map[string]interface{}{
+			"index": r.Index,
+			"id":    r.ID,
+		}
+
+		if r.Err != nil {
+			item["error"] = r.Err.Error()
+		}
+
+		response = append(response, item)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results": response,
+	})
+}
+
+func parseFilter(r *http.Request) (*filters.StockFilter, error) {
+	query := r.URL.Query()
+	filter := &filters.StockFilter{Name: query.Get("name")}
+
+	for key, dest := range map[string]**int64{
+		"min_quantity": &filter.MinQuantity,
+		"max_quantity": &filter.MaxQuantity,
+	} {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid %s: '%s'", key, raw)).On(key)
+		}
+
+		*dest = &value
+	}
+
+	return filter, nil
+}
+
 func parsePagination(r *http.Request) (*filters.Pagination, error) {
 	reqBody, errRead := ioutil.ReadAll(r.Body)
 	if errRead != nil {
-		return nil, errRead
+		return nil, malformed(errRead)
 	}
 
 	var unm map[string]*filters.Pagination
 
 	if err := json.Unmarshal(reqBody, &unm); err != nil {
-		return nil, err
+		return nil, malformed(err)
+	}
+
+	if unm["pagination"] == nil {
+		return nil, errs.New(errs.InvalidArgument, "Pagination is required").On("pagination")
 	}
 
 	return unm["pagination"], nil
 }
+
+func parseQueryPagination(r *http.Request) (*filters.Pagination, error) {
+	query := r.URL.Query()
+	pagination := &filters.Pagination{Page: defaultPage, ItemsPerPage: defaultItemsPerPage}
+
+	for key, dest := range map[string]*int{
+		"page":           &pagination.Page,
+		"items_per_page": &pagination.ItemsPerPage,
+	} {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid %s: '%s'", key, raw)).On(key)
+		}
+
+		*dest = value
+	}
+
+	err := val.New().Struct(validators.Pagination{Page: pagination.Page, ItemsPerPage: pagination.ItemsPerPage})
+	if err != nil {
+		return nil, errs.Invalid(err)
+	}
+
+	return pagination, nil
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+	"stocks-api/module/services"
+	"stocks-api/module/validators"
+	"stocks-api/support/db"
+)
+
+// WebhookService a contract to the WebhookService.
+type WebhookService interface {
+	GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	GetOne(ctx context.Context, subId string) (*entities.WebhookSubscription, error)
+	InsertOne(ctx context.Context, sub *entities.WebhookSubscription) error
+	UpdateOne(ctx context.Context, sub *entities.WebhookSubscription, subId string) error
+	DeleteOne(ctx context.Context, subId string) error
+	DeadLetters(ctx context.Context) ([]*entities.WebhookDeadLetter, error)
+}
+
+// webhookRequest the body of the create/update subscription endpoints.
+type webhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookController handles the webhook subscription endpoints.
+type WebhookController struct {
+	logger  *logrus.Logger
+	service WebhookService
+	policy  *policy.Engine
+}
+
+// NewWebhookController a constructor for the WebhookController.
+func NewWebhookController(l *logrus.Logger, db *db.Instance, engine *policy.Engine) *WebhookController {
+	return &WebhookController{
+		logger:  l,
+		service: services.NewWebhookService(l, db),
+		policy:  engine,
+	}
+}
+
+// GetAll returns all subscriptions.
+func (c *WebhookController) GetAll(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	subs, err := c.service.GetAll(r.Context())
+	if err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+// GetOne returns a single subscription.
+func (c *WebhookController) GetOne(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	sub, err := c.service.GetOne(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(sub)
+}
+
+// InsertOne registers a new subscription, the response is the only time its secret is returned.
+func (c *WebhookController) InsertOne(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	sub, err := reqToWebhook(r)
+	if err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	if err := c.service.InsertOne(r.Context(), sub); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// UpdateOne updates a single subscription.
+func (c *WebhookController) UpdateOne(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	sub, err := reqToWebhook(r)
+	if err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	if err := c.service.UpdateOne(r.Context(), sub, mux.Vars(r)["id"]); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(sub)
+}
+
+// DeleteOne removes a single subscription.
+func (c *WebhookController) DeleteOne(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	if err := c.service.DeleteOne(r.Context(), mux.Vars(r)["id"]); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeadLetters returns the deliveries that ran out of attempts.
+func (c *WebhookController) DeadLetters(w http.ResponseWriter, r *http.Request) {
+	if err := c.policy.Authorize(r.Context(), policy.ManageWebhooks); err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	letters, err := c.service.DeadLetters(r.Context())
+	if err != nil {
+		WriteProblem(c.logger, w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dead_letters": letters,
+	})
+}
+
+func reqToWebhook(r *http.Request) (*entities.WebhookSubscription, error) {
+	req := webhookRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, malformed(err)
+	}
+
+	if err := val.New().Struct(validators.Webhook{URL: req.URL, EventTypes: req.EventTypes}); err != nil {
+		return nil, errs.Invalid(err)
+	}
+
+	sub := &entities.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}
+
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ApiKey - a long-lived credential of a service client.
+// Only the SHA-256 hash of its secret is stored, the prefix identifies the key without revealing it.
+type ApiKey struct {
+	bun.BaseModel `bun:"table:api_key,alias:api_key"`
+
+	ID        uuid.UUID  `bun:"id,pk,notnull" json:"id" yaml:"id"`
+	Name      string     `bun:"name,notnull" json:"name" yaml:"name"`
+	TenantID  string     `bun:"tenant_id,notnull" json:"tenant_id" yaml:"tenant_id"`
+	Prefix    string     `bun:"prefix,notnull,unique" json:"prefix" yaml:"prefix"`
+	Hash      string     `bun:"hash,notnull" json:"-" yaml:"-"`
+	Roles     []string   `bun:"roles,array" json:"roles" yaml:"roles"`
+	ExpiresAt *time.Time `bun:"expires_at" json:"expires_at" yaml:"expires_at"`
+	RevokedAt *time.Time `bun:"revoked_at" json:"revoked_at" yaml:"revoked_at"`
+	CreatedAt time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+}
+
+// Usable returns true if the key is neither revoked nor expired.
+func (a *ApiKey) Usable(now time.Time) bool {
+	if a.RevokedAt != nil {
+		return false
+	}
+
+	return a.ExpiresAt == nil || now.Before(*a.ExpiresAt)
+}
+
+// BeforeAppendModel DB hooks that will be executed before a DB query.
+func (a *ApiKey) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if a.ID == uuid.Nil {
+			a.ID = uuid.New()
+		}
+
+		if a.TenantID == "" {
+			a.TenantID = TenantFrom(ctx)
+		}
+
+		a.CreatedAt = time.Now()
+	}
+	return nil
+}
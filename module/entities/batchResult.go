@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+)
+
+// BatchResult the outcome of a single item within a batch operation.
+type BatchResult struct {
+	Index int       `json:"index" yaml:"index"`
+	ID    uuid.UUID `json:"id" yaml:"id"`
+	Err   error     `json:"-" yaml:"-"`
+}
+
+// BatchResults a slice of batch results.
+type BatchResults []*BatchResult
+
+// BatchItem a single item within a batch operation, carrying any validation error raised upstream.
+// Stock is always set, even when the item failed validation.
+type BatchItem struct {
+	Stock *Stock
+	Err   error
+}
@@ -0,0 +1,9 @@
+package filters
+
+// StockFilter narrows down the stock items returned by the listing/export operations.
+// Zero values are ignored, the quantity bounds are inclusive.
+type StockFilter struct {
+	Name        string `json:"name" yaml:"name"`
+	MinQuantity *int64 `json:"min_quantity" yaml:"min_quantity"`
+	MaxQuantity *int64 `json:"max_quantity" yaml:"max_quantity"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// IdempotencyRecord - the outcome of a request carrying an idempotency key, replayed to the retries of the request.
+// Fingerprint is a hash of the request, a replay with a different one is rejected.
+// A record without CompletedAt is held by a request still in progress.
+type IdempotencyRecord struct {
+	bun.BaseModel `bun:"table:idempotency_record,alias:idempotency_record"`
+
+	TenantID    string              `bun:"tenant_id,pk" json:"-" yaml:"-"`
+	Key         string              `bun:"key,pk" json:"key" yaml:"key"`
+	Fingerprint string              `bun:"fingerprint,notnull" json:"fingerprint" yaml:"fingerprint"`
+	Status      int                 `bun:"status,notnull" json:"status" yaml:"status"`
+	Headers     map[string][]string `bun:"headers,type:jsonb,nullzero" json:"headers" yaml:"headers"`
+	Response    []byte              `bun:"response" json:"-" yaml:"-"`
+	CreatedAt   time.Time           `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+	CompletedAt *time.Time          `bun:"completed_at" json:"completed_at" yaml:"completed_at"`
+	ExpiresAt   time.Time           `bun:"expires_at,notnull" json:"expires_at" yaml:"expires_at"`
+}
+
+// Completed returns true if the request holding the key has stored its outcome.
+func (i *IdempotencyRecord) Completed() bool {
+	return i.CompletedAt != nil
+}
+
+// BeforeAppendModel DB hooks that will be executed before a DB query.
+func (i *IdempotencyRecord) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if i.TenantID == "" {
+			i.TenantID = TenantFrom(ctx)
+		}
+
+		i.CreatedAt = time.Now()
+	}
+	return nil
+}
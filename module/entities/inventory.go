@@ -0,0 +1,10 @@
+package entities
+
+// Inventory - the stock of a tenant, summed up for the metrics.
+// LowStock counts the items at or below the low stock threshold.
+type Inventory struct {
+	TenantID string `bun:"tenant_id" json:"tenant_id" yaml:"tenant_id"`
+	Skus     int64  `bun:"skus" json:"skus" yaml:"skus"`
+	Units    int64  `bun:"units" json:"units" yaml:"units"`
+	LowStock int64  `bun:"low_stock" json:"low_stock" yaml:"low_stock"`
+}
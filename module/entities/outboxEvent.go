@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// OutboxEvent - a domain event, written in the same transaction as the stock mutation it describes.
+// It is consumed independently by the webhook dispatcher (DispatchedAt) and the broker relay (PublishedAt).
+// Stock holds the item as it was after the change, or before it for deletions.
+type OutboxEvent struct {
+	bun.BaseModel `bun:"table:outbox,alias:outbox"`
+
+	ID           uuid.UUID `bun:"id,pk,notnull" json:"id" yaml:"id"`
+	StockID      uuid.UUID `bun:"stock_id,notnull" json:"stock_id" yaml:"stock_id"`
+	TenantID     string    `bun:"tenant_id,notnull" json:"tenant_id" yaml:"tenant_id"`
+	Type         EventType `bun:"type,notnull" json:"type" yaml:"type"`
+	Stock        *Stock    `bun:"stock,type:jsonb,notnull" json:"stock" yaml:"stock"`
+	CreatedAt    time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+	DispatchedAt time.Time `bun:",nullzero" json:"-" yaml:"-"`
+	PublishedAt  time.Time `bun:",nullzero" json:"-" yaml:"-"`
+}
+
+// NewOutboxEvent a constructor for an OutboxEvent.
+func NewOutboxEvent(t EventType, stock *Stock) *OutboxEvent {
+	return &OutboxEvent{
+		StockID:  stock.ID,
+		TenantID: stock.TenantID,
+		Type:     t,
+		Stock:    stock,
+	}
+}
+
+// BeforeAppendModel DB hooks that will be executed before a DB query.
+func (o *OutboxEvent) BeforeAppendModel(_ context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if o.ID == uuid.Nil {
+			o.ID = uuid.New()
+		}
+
+		o.CreatedAt = time.Now()
+	}
+	return nil
+}
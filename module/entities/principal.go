@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"context"
+)
+
+// PrincipalKind defines how a principal authenticated.
+type PrincipalKind string
+
+const (
+	JWTPrincipal         PrincipalKind = "jwt"
+	ApiKeyPrincipal      PrincipalKind = "api_key"
+	CertificatePrincipal PrincipalKind = "certificate"
+)
+
+// Principal - the authenticated caller of a request.
+// Subject is the JWT subject, the id of the API key, or the common name of the client certificate. Tenant is empty for the principals of the DefaultTenant.
+type Principal struct {
+	Subject string        `json:"subject" yaml:"subject"`
+	Kind    PrincipalKind `json:"kind" yaml:"kind"`
+	Roles   []string      `json:"roles" yaml:"roles"`
+	Tenant  string        `json:"tenant" yaml:"tenant"`
+}
+
+type principalKey struct{}
+
+// WithPrincipal attaches the authenticated principal to a request context.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal of a request context, nil for the unauthenticated ones.
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+
+	return p
+}
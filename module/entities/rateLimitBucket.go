@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RateLimitBucket - the token bucket of a client on a route, shared by the replicas.
+// Tokens is the count left at UpdatedAt, the bucket refills from then on.
+type RateLimitBucket struct {
+	bun.BaseModel `bun:"table:rate_limit_bucket,alias:rate_limit_bucket"`
+
+	Key       string    `bun:"key,pk" json:"key" yaml:"key"`
+	Tokens    float64   `bun:"tokens,notnull" json:"tokens" yaml:"tokens"`
+	UpdatedAt time.Time `bun:"updated_at,notnull" json:"updated_at" yaml:"updated_at"`
+}
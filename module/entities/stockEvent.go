@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// EventType defines the kind of change a stock event represents.
+type EventType string
+
+const (
+	Created EventType = "CREATED"
+	Updated EventType = "UPDATED"
+	Deleted EventType = "DELETED"
+)
+
+// StockEvent - a recorded change of a stock item, written by a DB trigger on every stock mutation.
+// Stock holds the item as it was after the change, or before it for deletions.
+type StockEvent struct {
+	bun.BaseModel `bun:"table:stock_event,alias:stock_event"`
+
+	Seq       int64     `bun:"seq,pk,autoincrement" json:"seq" yaml:"seq"`
+	Type      EventType `bun:"type,notnull" json:"type" yaml:"type"`
+	StockID   uuid.UUID `bun:"stock_id,notnull" json:"stock_id" yaml:"stock_id"`
+	TenantID  string    `bun:"tenant_id,notnull" json:"-" yaml:"-"`
+	Stock     *Stock    `bun:"stock,type:jsonb,notnull" json:"stock" yaml:"stock"`
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"context"
+)
+
+// DefaultTenant the tenant of the calls that don't name one, and of the records predating the multi-tenancy.
+const DefaultTenant = "default"
+
+type tenantKey struct{}
+
+// WithTenant attaches the tenant a request is scoped to to its context.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant of a request context, the DefaultTenant if it has none.
+func TenantFrom(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+
+	return DefaultTenant
+}
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// WebhookSubscription - a registered receiver of the stock events.
+// An empty EventTypes subscribes to every event type.
+type WebhookSubscription struct {
+	bun.BaseModel `bun:"table:webhook_subscription,alias:webhook_subscription"`
+
+	ID         uuid.UUID `bun:"id,pk,notnull" json:"id" yaml:"id"`
+	TenantID   string    `bun:"tenant_id,notnull" json:"-" yaml:"-"`
+	URL        string    `bun:"url,notnull" json:"url" yaml:"url"`
+	Secret     string    `bun:"secret,notnull" json:"secret,omitempty" yaml:"secret,omitempty"`
+	EventTypes []string  `bun:"event_types,array" json:"event_types" yaml:"event_types"`
+	Active     bool      `bun:"active,notnull" json:"active" yaml:"active"`
+	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+	UpdatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"updated_at" yaml:"updated_at"`
+}
+
+// Accepts returns true if the subscription wants the given event type.
+func (w *WebhookSubscription) Accepts(t EventType) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+
+	for _, et := range w.EventTypes {
+		if EventType(et) == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BeforeAppendModel DB hooks that will be executed before a DB query.
+func (w *WebhookSubscription) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if w.ID == uuid.Nil {
+			w.ID = uuid.New()
+		}
+
+		if w.TenantID == "" {
+			w.TenantID = TenantFrom(ctx)
+		}
+
+		w.CreatedAt = time.Now()
+		w.UpdatedAt = w.CreatedAt
+	case *bun.UpdateQuery:
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// WebhookDelivery - a pending delivery of an outbox event to a subscription.
+type WebhookDelivery struct {
+	bun.BaseModel `bun:"table:webhook_delivery,alias:webhook_delivery"`
+
+	ID             uuid.UUID `bun:"id,pk,notnull"`
+	SubscriptionID uuid.UUID `bun:"subscription_id,notnull"`
+	EventID        uuid.UUID `bun:"event_id,notnull"`
+	Attempts       int       `bun:"attempts,notnull"`
+	NextAttemptAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	LastError      string    `bun:"last_error,nullzero"`
+	CreatedAt      time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+
+	Subscription *WebhookSubscription `bun:"rel:belongs-to,join:subscription_id=id"`
+	Event        *OutboxEvent         `bun:"rel:belongs-to,join:event_id=id"`
+}
+
+// WebhookDeadLetter - a delivery that ran out of attempts, kept for inspection and manual replay.
+type WebhookDeadLetter struct {
+	bun.BaseModel `bun:"table:webhook_dead_letter,alias:webhook_dead_letter"`
+
+	ID             uuid.UUID    `bun:"id,pk,notnull" json:"id" yaml:"id"`
+	TenantID       string       `bun:"tenant_id,notnull" json:"-" yaml:"-"`
+	SubscriptionID uuid.UUID    `bun:"subscription_id,notnull" json:"subscription_id" yaml:"subscription_id"`
+	EventID        uuid.UUID    `bun:"event_id,notnull" json:"event_id" yaml:"event_id"`
+	URL            string       `bun:"url,notnull" json:"url" yaml:"url"`
+	Payload        *OutboxEvent `bun:"payload,type:jsonb,notnull" json:"payload" yaml:"payload"`
+	Attempts       int          `bun:"attempts,notnull" json:"attempts" yaml:"attempts"`
+	LastError      string       `bun:"last_error,nullzero" json:"last_error" yaml:"last_error"`
+	CreatedAt      time.Time    `bun:",nullzero,notnull,default:current_timestamp" json:"created_at" yaml:"created_at"`
+}
@@ -0,0 +1,90 @@
+package errs
+
+import (
+	"errors"
+)
+
+// Kind classifies the domain errors, so that every transport can map them to its own status codes.
+type Kind int
+
+const (
+	// Internal an unexpected failure, the zero Kind of every unclassified error.
+	Internal Kind = iota
+	// NotFound the requested record doesn't exist.
+	NotFound
+	// Conflict the record clashes with an existing one.
+	Conflict
+	// InvalidArgument the input is malformed or fails validation.
+	InvalidArgument
+	// Aborted the operation lost a concurrency race and may be retried.
+	Aborted
+	// Unauthenticated the request carries no valid credentials.
+	Unauthenticated
+	// PermissionDenied the caller isn't allowed the operation.
+	PermissionDenied
+	// ResourceExhausted the caller ran out of its request quota, for now.
+	ResourceExhausted
+)
+
+// Error a domain error of a given Kind, optionally tied to an input field.
+type Error struct {
+	Kind    Kind
+	Field   string
+	Message string
+	Err     error
+}
+
+// New a constructor for the Error.
+func New(kind Kind, msg string) *Error {
+	return &Error{
+		Kind:    kind,
+		Message: msg,
+	}
+}
+
+// Wrap classifies an existing error, keeping it reachable through errors.As.
+func Wrap(kind Kind, err error) *Error {
+	return &Error{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+// On ties the error to the given input field.
+func (e *Error) On(field string) *Error {
+	e.Field = field
+
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the Kind of the first domain error in the chain, Internal if there is none.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return Internal
+}
+
+// FieldOf returns the input field of the first domain error in the chain, if any.
+func FieldOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Field
+	}
+
+	return ""
+}
@@ -0,0 +1,62 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	val "github.com/go-playground/validator/v10"
+)
+
+// Violation an input field failing validation.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// Invalid classifies a validator error as an invalid argument, nil stays nil.
+func Invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return Wrap(InvalidArgument, err)
+}
+
+// Violations lists the input fields an error is about, from the validator errors or the field of the domain error.
+func Violations(err error) []Violation {
+	var validationErrs val.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations := make([]Violation, 0, len(validationErrs))
+
+		for _, fe := range validationErrs {
+			violations = append(violations, Violation{
+				Field:       strings.ToLower(fe.Field()),
+				Description: describe(fe),
+			})
+		}
+
+		return violations
+	}
+
+	if field := FieldOf(err); field != "" {
+		return []Violation{{Field: field, Description: err.Error()}}
+	}
+
+	return nil
+}
+
+// IsValidation reports whether the error is a failed validation, rather than a malformed input.
+func IsValidation(err error) bool {
+	var validationErrs val.ValidationErrors
+
+	return errors.As(err, &validationErrs)
+}
+
+func describe(fe val.FieldError) string {
+	if fe.Param() == "" {
+		return fmt.Sprintf("failed the '%s' rule", fe.Tag())
+	}
+
+	return fmt.Sprintf("failed the '%s=%s' rule", fe.Tag(), fe.Param())
+}
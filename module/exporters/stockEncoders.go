@@ -0,0 +1,144 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"stocks-api/module/entities"
+)
+
+// Format defines the export file format.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+	Parquet Format = "parquet"
+)
+
+// parquetRowGroupSize bounds the number of rows the parquet encoder buffers before flushing them.
+const parquetRowGroupSize = 10000
+
+// Encoder writes stock items, one at a time, to the underlying writer.
+// Close must be called once all items are written, it does not close the writer itself.
+type Encoder interface {
+	Encode(stock *entities.Stock) error
+	Close() error
+}
+
+// ParseFormat validates a raw format, an empty one defaults to CSV.
+func ParseFormat(raw string) (Format, error) {
+	switch f := Format(raw); f {
+	case "":
+		return CSV, nil
+	case CSV, NDJSON, Parquet:
+		return f, nil
+	}
+
+	return "", errors.New(fmt.Sprintf("unsupported export format: '%s'", raw))
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	}
+
+	return "text/csv"
+}
+
+// NewEncoder a constructor for the Encoder of the given format.
+func NewEncoder(format Format, w io.Writer) (Encoder, error) {
+	switch format {
+	case CSV:
+		return newCsvEncoder(w)
+	case NDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}, nil
+	case Parquet:
+		return &parquetEncoder{
+			writer: parquet.NewGenericWriter[parquetStock](w, parquet.MaxRowsPerRowGroup(parquetRowGroupSize)),
+		}, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf("unsupported export format: '%s'", format))
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func newCsvEncoder(w io.Writer) (*csvEncoder, error) {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"id", "name", "quantity", "created_at", "updated_at"}); err != nil {
+		return nil, err
+	}
+
+	return &csvEncoder{writer: writer}, nil
+}
+
+func (e *csvEncoder) Encode(stock *entities.Stock) error {
+	return e.writer.Write([]string{
+		stock.ID.String(),
+		stock.Name,
+		strconv.FormatInt(stock.Quantity, 10),
+		stock.CreatedAt.Format(time.RFC3339),
+		stock.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.writer.Flush()
+
+	return e.writer.Error()
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(stock *entities.Stock) error {
+	return e.encoder.Encode(stock)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+// parquetStock the parquet schema of an exported stock item.
+type parquetStock struct {
+	ID        string    `parquet:"id"`
+	Name      string    `parquet:"name"`
+	Quantity  int64     `parquet:"quantity"`
+	CreatedAt time.Time `parquet:"created_at"`
+	UpdatedAt time.Time `parquet:"updated_at"`
+}
+
+type parquetEncoder struct {
+	writer *parquet.GenericWriter[parquetStock]
+}
+
+func (e *parquetEncoder) Encode(stock *entities.Stock) error {
+	_, err := e.writer.Write([]parquetStock{{
+		ID:        stock.ID.String(),
+		Name:      stock.Name,
+		Quantity:  stock.Quantity,
+		CreatedAt: stock.CreatedAt,
+		UpdatedAt: stock.UpdatedAt,
+	}})
+
+	return err
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.writer.Close()
+}
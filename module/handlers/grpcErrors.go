@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/errs"
+)
+
+// grpcCodes maps the domain error kinds to their gRPC codes.
+var grpcCodes = map[errs.Kind]codes.Code{
+	errs.Internal:          codes.Internal,
+	errs.NotFound:          codes.NotFound,
+	errs.Conflict:          codes.AlreadyExists,
+	errs.InvalidArgument:   codes.InvalidArgument,
+	errs.Aborted:           codes.Aborted,
+	errs.Unauthenticated:   codes.Unauthenticated,
+	errs.PermissionDenied:  codes.PermissionDenied,
+	errs.ResourceExhausted: codes.ResourceExhausted,
+}
+
+// toStatusError converts an error to a gRPC status, attaching the field violations of the invalid arguments.
+// Internal errors are replaced by the fallback message, so that no db details reach the clients.
+func toStatusError(err error, fallback string) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	kind := errs.KindOf(err)
+	if kind == errs.Internal {
+		return status.Error(codes.Internal, fallback)
+	}
+
+	return withDetails(status.New(grpcCodes[kind], err.Error()), kind, err).Err()
+}
+
+// withDetails attaches the field violations of the invalid arguments, keeping the bare status if that fails.
+func withDetails(st *status.Status, kind errs.Kind, err error) *status.Status {
+	if kind != errs.InvalidArgument {
+		return st
+	}
+
+	detail := badRequest(err)
+	if len(detail.FieldViolations) == 0 {
+		return st
+	}
+
+	detailed, errDetails := st.WithDetails(detail)
+	if errDetails != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// badRequest lists the field violations of an invalid argument error.
+func badRequest(err error) *errdetails.BadRequest {
+	detail := &errdetails.BadRequest{}
+
+	for _, v := range errs.Violations(err) {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	return detail
+}
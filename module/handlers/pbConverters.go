@@ -3,9 +3,11 @@ package handlers
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	pb "stocks-api/genprotos"
 	"stocks-api/module/entities"
+	"stocks-api/module/entities/filters"
 )
 
 func toStockPb(stock *entities.Stock) *pb.SingleStock {
@@ -26,6 +28,28 @@ func toStockPb(stock *entities.Stock) *pb.SingleStock {
 	}
 }
 
+func toStockEventPb(event *entities.StockEvent) *pb.StockEvent {
+	return &pb.StockEvent{
+		Sequence:   event.Seq,
+		Type:       toEventTypePb(event.Type),
+		Stock:      toStockPb(event.Stock),
+		OccurredAt: timestamppb.New(event.CreatedAt),
+	}
+}
+
+func toEventTypePb(t entities.EventType) pb.StockEventType {
+	switch t {
+	case entities.Created:
+		return pb.StockEventType_STOCK_EVENT_TYPE_CREATED
+	case entities.Updated:
+		return pb.StockEventType_STOCK_EVENT_TYPE_UPDATED
+	case entities.Deleted:
+		return pb.StockEventType_STOCK_EVENT_TYPE_DELETED
+	}
+
+	return pb.StockEventType_STOCK_EVENT_TYPE_UNSPECIFIED
+}
+
 func toStockListPb(stocks []*entities.Stock) []*pb.SingleStock {
 	response := make([]*pb.SingleStock, 0, len(stocks))
 
@@ -37,10 +61,50 @@ func toStockListPb(stocks []*entities.Stock) []*pb.SingleStock {
 }
 
 func fromCreatePb(req *pb.CreateStockRequest) *entities.Stock {
-	return &entities.Stock{
-		Name:     req.GetStock().GetName(),
-		Quantity: req.GetStock().GetQuantity(),
+	return fromNewStockPb(req.GetStock())
+}
+
+// fromNewStockPb converts a new stock, without an ID one is generated on insert.
+func fromNewStockPb(stock *pb.NewStock) *entities.Stock {
+	s := fromIdPb(stock.GetId())
+	s.Name = stock.GetName()
+	s.Quantity = stock.GetQuantity()
+
+	return s
+}
+
+// fromEditableStockPb converts an editable stock, an unparsable ID is left as uuid.Nil for the validators to reject.
+func fromEditableStockPb(stock *pb.EditableStock) *entities.Stock {
+	s := fromIdPb(stock.GetId())
+	s.Name = stock.GetName()
+	s.Quantity = stock.GetQuantity()
+
+	return s
+}
+
+func fromIdPb(id string) *entities.Stock {
+	parsed, _ := uuid.Parse(id)
+
+	return &entities.Stock{ID: parsed}
+}
+
+func toBatchResultsPb(results entities.BatchResults) []*pb.BatchItemResult {
+	response := make([]*pb.BatchItemResult, 0, len(results))
+
+	for _, r := range results {
+		item := &pb.BatchItemResult{
+			Index: int64(r.Index),
+			Id:    r.ID.String(),
+		}
+
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+
+		response = append(response, item)
 	}
+
+	return response
 }
 
 func fromEditPb(req *pb.EditStockRequest) *entities.Stock {
@@ -49,3 +113,19 @@ func fromEditPb(req *pb.EditStockRequest) *entities.Stock {
 		Quantity: req.GetStock().GetQuantity(),
 	}
 }
+
+func fromFilterPb(filter *pb.StockFilter) *filters.StockFilter {
+	f := &filters.StockFilter{Name: filter.GetName()}
+
+	if filter.GetMinQuantity() != nil {
+		min := filter.GetMinQuantity().GetValue()
+		f.MinQuantity = &min
+	}
+
+	if filter.GetMaxQuantity() != nil {
+		max := filter.GetMaxQuantity().GetValue()
+		f.MaxQuantity = &max
+	}
+
+	return f
+}
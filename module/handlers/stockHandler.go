@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"context"
-	"errors"
+	"slices"
 
 	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	pb "stocks-api/genprotos"
 	"stocks-api/module/entities"
 	"stocks-api/module/entities/filters"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
 	"stocks-api/module/services"
 	"stocks-api/module/validators"
 	"stocks-api/support/db"
@@ -16,39 +19,66 @@ import (
 
 // StockService an interface to the service.
 type StockService interface {
-	GetAll(ctx context.Context, pagination *filters.Pagination) ([]*entities.Stock, error)
+	GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error)
 	GetOne(ctx context.Context, stockId string) (*entities.Stock, error)
 	InsertOne(ctx context.Context, stock *entities.Stock) error
 	UpdateOne(ctx context.Context, stock *entities.Stock, stockId string) error
+	PatchOne(ctx context.Context, stock *entities.Stock, stockId string, fields []string) error
 	DeleteOne(ctx context.Context, stockId string) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, filter *filters.StockFilter) (int, error)
+	Export(ctx context.Context, filter *filters.StockFilter, fn func(stock *entities.Stock) error) error
+	InsertMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+	UpdateMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+	DeleteMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
 }
 
+// EventWatcher a contract to the stock event broker.
+type EventWatcher interface {
+	Watch(ctx context.Context, fromSeq int64, stockIDs []uuid.UUID, fn func(event *entities.StockEvent) error) error
+}
+
+// exportChunkSize the number of stock items sent per ExportStocks message.
+const exportChunkSize = 100
+
 // StockHandler handles all gRPC stock requests.
 type StockHandler struct {
 	logger  *logrus.Logger
 	service StockService
+	watcher EventWatcher
+	policy  *policy.Engine
 	*pb.UnimplementedStockServiceServer
 }
 
 // NewStockHandler is a constructor for a new Stock Handler.
-func NewStockHandler(l *logrus.Logger, db *db.Instance, ctx context.Context) *StockHandler {
+func NewStockHandler(
+	l *logrus.Logger,
+	db *db.Instance,
+	ctx context.Context,
+	watcher EventWatcher,
+	engine *policy.Engine,
+) *StockHandler {
 	return &StockHandler{
 		logger:                          l,
 		service:                         services.NewStockService(l, db, ctx),
+		watcher:                         watcher,
+		policy:                          engine,
 		UnimplementedStockServiceServer: &pb.UnimplementedStockServiceServer{},
 	}
 }
 
 // GetStock returns a single stock item, fetched by ID.
 func (s *StockHandler) GetStock(ctx context.Context, request *pb.GetStockRequest) (*pb.GetStockResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.ReadStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
 	if err := validateGet(request); err != nil {
-		return nil, err
+		return nil, toStatusError(errs.Invalid(err), "")
 	}
 
 	stock, err := s.service.GetOne(ctx, request.GetId())
 	if err != nil {
-		return nil, errors.New("Failed to fetch stock item")
+		return nil, toStatusError(err, "Failed to fetch stock item")
 	}
 
 	return &pb.GetStockResponse{
@@ -58,26 +88,34 @@ func (s *StockHandler) GetStock(ctx context.Context, request *pb.GetStockRequest
 
 // ListStocks lists all stocks available in the db.
 func (s *StockHandler) ListStocks(ctx context.Context, req *pb.ListStocksRequest) (*pb.ListStocksResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.ReadStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
 	if req.GetPagination() == nil {
-		return nil, errors.New("Pagination is required")
+		return nil, toStatusError(errs.New(errs.InvalidArgument, "Pagination is required").On("pagination"), "")
 	}
 
-	// TODO: validation
 	pagination := &filters.Pagination{
 		Page:         int(req.GetPagination().GetPage()),
 		ItemsPerPage: int(req.GetPagination().GetItemsPerPage()),
 	}
 
-	stocks, err := s.service.GetAll(ctx, pagination)
+	err := val.New().Struct(validators.Pagination{Page: pagination.Page, ItemsPerPage: pagination.ItemsPerPage})
 	if err != nil {
-		s.logger.Error(err)
-		return nil, errors.New("Failed to list stocks")
+		return nil, toStatusError(errs.Invalid(err), "")
 	}
 
-	count, err := s.service.Count(ctx)
+	stocks, err := s.service.GetAll(ctx, pagination, nil)
 	if err != nil {
-		s.logger.Error(err)
-		return nil, errors.New("Failed to get count")
+		s.logger.WithContext(ctx).Error(err)
+		return nil, toStatusError(err, "Failed to list stocks")
+	}
+
+	count, err := s.service.Count(ctx, nil)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, toStatusError(err, "Failed to get count")
 	}
 
 	return &pb.ListStocksResponse{
@@ -86,58 +124,245 @@ func (s *StockHandler) ListStocks(ctx context.Context, req *pb.ListStocksRequest
 	}, nil
 }
 
-// CreateStock creates a new stock item
+// CreateStock creates a new stock item, returning it as stored.
 func (s *StockHandler) CreateStock(ctx context.Context, request *pb.CreateStockRequest) (*pb.CreateStockResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.CreateStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
 	if err := validateCreate(request); err != nil {
-		return nil, errors.New("Failed to create stock")
+		return nil, toStatusError(errs.Invalid(err), "")
 	}
 
 	stock := fromCreatePb(request)
 
 	if err := s.service.InsertOne(ctx, stock); err != nil {
-		return nil, err
+		return nil, toStatusError(err, "Failed to create stock")
 	}
 
-	return &pb.CreateStockResponse{}, nil
+	return &pb.CreateStockResponse{Stock: toStockPb(stock)}, nil
 }
 
-// EditStock modifies an existing stock item
+// EditStock modifies an existing stock item, only the fields of the update mask if one is given.
+// The item is returned as stored.
 func (s *StockHandler) EditStock(ctx context.Context, request *pb.EditStockRequest) (*pb.EditStockResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.UpdateStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
 	if err := validateUpdate(request); err != nil {
-		return nil, errors.New("Failed to update stock")
+		return nil, toStatusError(errs.Invalid(err), "")
 	}
 
 	stock := fromEditPb(request)
+	paths := request.GetUpdateMask().GetPaths()
 
-	if err := s.service.UpdateOne(ctx, stock, request.GetStock().GetId()); err != nil {
-		return nil, errors.New("Failed to update stock")
+	if len(paths) == 0 || slices.Contains(paths, "quantity") {
+		if err := s.authorizeQuantity(ctx, request.GetStock().GetId(), stock.Quantity); err != nil {
+			return nil, toStatusError(err, "Failed to update stock")
+		}
 	}
 
-	return &pb.EditStockResponse{}, nil
+	var err error
+
+	if len(paths) > 0 {
+		err = s.service.PatchOne(ctx, stock, request.GetStock().GetId(), paths)
+	} else {
+		err = s.service.UpdateOne(ctx, stock, request.GetStock().GetId())
+	}
+
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update stock")
+	}
+
+	return &pb.EditStockResponse{Stock: toStockPb(stock)}, nil
 }
 
 // DeleteStock removes a given stock item.
 func (s *StockHandler) DeleteStock(ctx context.Context, request *pb.DeleteStockRequest) (*pb.DeleteStockResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.DeleteStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
 	if request.GetId() == "" {
-		return nil, errors.New("StockID is required")
+		return nil, toStatusError(errs.New(errs.InvalidArgument, "StockID is required").On("id"), "")
 	}
 
 	if err := s.service.DeleteOne(ctx, request.GetId()); err != nil {
-		return nil, err
+		return nil, toStatusError(err, "Failed to delete stock")
 	}
 
 	return &pb.DeleteStockResponse{}, nil
 }
 
+// BatchCreateStocks creates multiple stock items.
+func (s *StockHandler) BatchCreateStocks(ctx context.Context, request *pb.BatchCreateStocksRequest) (*pb.BatchCreateStocksResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.CreateStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
+	if err := validateBatch(len(request.GetStocks())); err != nil {
+		return nil, toStatusError(errs.Invalid(err), "")
+	}
+
+	items := make([]*entities.BatchItem, 0, len(request.GetStocks()))
+
+	for _, stock := range request.GetStocks() {
+		items = append(items, &entities.BatchItem{
+			Stock: fromNewStockPb(stock),
+			Err:   errs.Invalid(validateNewStock(stock)),
+		})
+	}
+
+	results, err := s.service.InsertMany(ctx, items, request.GetBestEffort())
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, toStatusError(err, "Failed to create stocks")
+	}
+
+	return &pb.BatchCreateStocksResponse{
+		Results: toBatchResultsPb(results),
+	}, nil
+}
+
+// BatchEditStocks modifies multiple existing stock items.
+func (s *StockHandler) BatchEditStocks(ctx context.Context, request *pb.BatchEditStocksRequest) (*pb.BatchEditStocksResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.UpdateStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
+	if err := validateBatch(len(request.GetStocks())); err != nil {
+		return nil, toStatusError(errs.Invalid(err), "")
+	}
+
+	items := make([]*entities.BatchItem, 0, len(request.GetStocks()))
+
+	for _, stock := range request.GetStocks() {
+		item := &entities.BatchItem{
+			Stock: fromEditableStockPb(stock),
+			Err:   errs.Invalid(validateEditableStock(stock)),
+		}
+
+		if item.Err == nil {
+			item.Err = s.authorizeQuantity(ctx, stock.GetId(), stock.GetQuantity())
+		}
+
+		items = append(items, item)
+	}
+
+	results, err := s.service.UpdateMany(ctx, items, request.GetBestEffort())
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, toStatusError(err, "Failed to update stocks")
+	}
+
+	return &pb.BatchEditStocksResponse{
+		Results: toBatchResultsPb(results),
+	}, nil
+}
+
+// BatchDeleteStocks removes multiple stock items.
+func (s *StockHandler) BatchDeleteStocks(ctx context.Context, request *pb.BatchDeleteStocksRequest) (*pb.BatchDeleteStocksResponse, error) {
+	if err := s.policy.Authorize(ctx, policy.DeleteStock); err != nil {
+		return nil, toStatusError(err, "")
+	}
+
+	if err := validateBatch(len(request.GetIds())); err != nil {
+		return nil, toStatusError(errs.Invalid(err), "")
+	}
+
+	items := make([]*entities.BatchItem, 0, len(request.GetIds()))
+
+	for _, id := range request.GetIds() {
+		items = append(items, &entities.BatchItem{
+			Stock: fromIdPb(id),
+			Err:   errs.Invalid(val.New().Struct(validators.GetStock{ID: id})),
+		})
+	}
+
+	results, err := s.service.DeleteMany(ctx, items, request.GetBestEffort())
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+		return nil, toStatusError(err, "Failed to delete stocks")
+	}
+
+	return &pb.BatchDeleteStocksResponse{
+		Results: toBatchResultsPb(results),
+	}, nil
+}
+
+// ExportStocks streams all stock items matching the filter, without loading them all in memory.
+func (s *StockHandler) ExportStocks(request *pb.ExportStocksRequest, stream pb.StockService_ExportStocksServer) error {
+	if err := s.policy.Authorize(stream.Context(), policy.ExportStock); err != nil {
+		return toStatusError(err, "")
+	}
+
+	chunk := make([]*pb.SingleStock, 0, exportChunkSize)
+
+	err := s.service.Export(stream.Context(), fromFilterPb(request.GetFilter()), func(stock *entities.Stock) error {
+		chunk = append(chunk, toStockPb(stock))
+		if len(chunk) < exportChunkSize {
+			return nil
+		}
+
+		errSend := stream.Send(&pb.ExportStocksResponse{Stocks: chunk})
+		chunk = make([]*pb.SingleStock, 0, exportChunkSize)
+
+		return errSend
+	})
+	if err != nil {
+		s.logger.WithContext(stream.Context()).Error(err)
+		return toStatusError(err, "Failed to export stocks")
+	}
+
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	return stream.Send(&pb.ExportStocksResponse{Stocks: chunk})
+}
+
+// WatchStocks streams the stock changes, optionally resuming after a given sequence.
+func (s *StockHandler) WatchStocks(request *pb.WatchStocksRequest, stream pb.StockService_WatchStocksServer) error {
+	if err := s.policy.Authorize(stream.Context(), policy.WatchStock); err != nil {
+		return toStatusError(err, "")
+	}
+
+	ids := make([]uuid.UUID, 0, len(request.GetStockIds()))
+
+	for _, id := range request.GetStockIds() {
+		if err := val.New().Struct(validators.GetStock{ID: id}); err != nil {
+			return toStatusError(errs.Invalid(err), "")
+		}
+
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	err := s.watcher.Watch(stream.Context(), request.GetFromSequence(), ids, func(event *entities.StockEvent) error {
+		return stream.Send(toStockEventPb(event))
+	})
+
+	return toStatusError(err, "Failed to watch stocks")
+}
+
+// authorizeQuantity authorizes setting the quantity of a stock item, looking up its current one if needed.
+func (s *StockHandler) authorizeQuantity(ctx context.Context, stockId string, quantity int64) error {
+	return s.policy.AuthorizeQuantity(ctx, quantity, func() (int64, error) {
+		current, err := s.service.GetOne(ctx, stockId)
+		if err != nil {
+			return 0, err
+		}
+
+		return current.Quantity, nil
+	})
+}
+
 func validateGet(r *pb.GetStockRequest) error {
 	return val.New().Struct(validators.GetStock{ID: r.GetId()})
 }
 
 func validateCreate(r *pb.CreateStockRequest) error {
-	return val.New().Struct(validators.InsertStock{
-		Name:     r.GetStock().GetName(),
-		Quantity: int(r.GetStock().GetQuantity()),
-	})
+	return validateNewStock(r.GetStock())
 }
 
 func validateUpdate(r *pb.EditStockRequest) error {
@@ -146,3 +371,26 @@ func validateUpdate(r *pb.EditStockRequest) error {
 		Quantity: int(r.GetStock().GetQuantity()),
 	})
 }
+
+func validateNewStock(s *pb.NewStock) error {
+	return val.New().Struct(validators.InsertStock{
+		ID:       s.GetId(),
+		Name:     s.GetName(),
+		Quantity: int(s.GetQuantity()),
+	})
+}
+
+func validateEditableStock(s *pb.EditableStock) error {
+	if err := val.New().Struct(validators.GetStock{ID: s.GetId()}); err != nil {
+		return err
+	}
+
+	return val.New().Struct(validators.UpdateStock{
+		Name:     s.GetName(),
+		Quantity: int(s.GetQuantity()),
+	})
+}
+
+func validateBatch(size int) error {
+	return val.New().Struct(validators.Batch{Size: size})
+}
@@ -0,0 +1,87 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet the raw contents of an import file, the first row being the header.
+type Sheet struct {
+	Header  []string
+	Records [][]string
+}
+
+// ReadFile loads a CSV or XLSX file, based on its extension.
+// For XLSX files the given sheet is used, or the first one if empty.
+func ReadFile(path string, sheet string) (*Sheet, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return ReadCSV(f)
+	case ".xlsx":
+		return readXLSX(path, sheet)
+	}
+
+	return nil, errors.New(fmt.Sprintf("unsupported file type: %s", path))
+}
+
+// ReadCSV loads a CSV document.
+func ReadCSV(r io.Reader) (*Sheet, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return toSheet(rows)
+}
+
+func readXLSX(path string, sheet string) (*Sheet, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSheet(rows)
+}
+
+func toSheet(rows [][]string) (*Sheet, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("the file is empty, a header row is required")
+	}
+
+	header := make([]string, 0, len(rows[0]))
+	for _, h := range rows[0] {
+		header = append(header, strings.TrimSpace(h))
+	}
+
+	return &Sheet{
+		Header:  header,
+		Records: rows[1:],
+	}, nil
+}
@@ -0,0 +1,417 @@
+package importers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/services"
+	"stocks-api/module/validators"
+	"stocks-api/support/db"
+)
+
+// Action defines what the import does with a single row.
+type Action string
+
+const (
+	Create    Action = "CREATE"
+	Update    Action = "UPDATE"
+	Unchanged Action = "UNCHANGED"
+	Reject    Action = "REJECT"
+)
+
+// Importable stock fields, used as keys of the ColumnMapping.
+const (
+	FieldID       = "id"
+	FieldName     = "name"
+	FieldQuantity = "quantity"
+)
+
+// StockService a contract to the StockService, the importer goes through it to keep the validation identical to the APIs.
+type StockService interface {
+	FindByNames(ctx context.Context, names []string) ([]*entities.Stock, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Stock, error)
+	Check(stock *entities.Stock) error
+	PatchOne(ctx context.Context, stock *entities.Stock, stockId string, fields []string) error
+	InsertMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+	UpdateMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error)
+}
+
+// ColumnMapping maps the stock fields to the column names of the import file.
+type ColumnMapping map[string]string
+
+// PlannedRow a single row of the import file and what is going to happen to it.
+// Without a quantity, the row keeps the stored quantity of the stock item it updates.
+type PlannedRow struct {
+	Line        int
+	Values      []string
+	Action      Action
+	Stock       *entities.Stock
+	HasQuantity bool
+	Existing    *entities.Stock
+	Err         error
+}
+
+// Report the outcome of an import, or of a dry-run.
+type Report struct {
+	Header []string
+	Rows   []*PlannedRow
+}
+
+// StockImporter upserts stock items, matched by id when the file has one and by name otherwise, from a file.
+type StockImporter struct {
+	logger  *logrus.Logger
+	service StockService
+}
+
+// NewStockImporter a constructor for the StockImporter.
+func NewStockImporter(l *logrus.Logger, db *db.Instance, ctx context.Context) *StockImporter {
+	return &StockImporter{
+		logger:  l,
+		service: services.NewStockService(l, db, ctx),
+	}
+}
+
+// ParseMapping parses a "field=column,field=column" mapping, the fields not mentioned map to a column of the same name.
+func ParseMapping(raw string) (ColumnMapping, error) {
+	mapping := ColumnMapping{
+		FieldID:       FieldID,
+		FieldName:     FieldName,
+		FieldQuantity: FieldQuantity,
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.New(fmt.Sprintf("malformed column mapping: '%s'", pair))
+		}
+
+		field := strings.ToLower(strings.TrimSpace(parts[0]))
+		if _, ok := mapping[field]; !ok {
+			return nil, errors.New(fmt.Sprintf("unknown stock field: '%s'", field))
+		}
+
+		mapping[field] = strings.TrimSpace(parts[1])
+	}
+
+	return mapping, nil
+}
+
+// Plan works out what would change when importing the sheet, without persisting anything.
+func (i *StockImporter) Plan(ctx context.Context, sheet *Sheet, mapping ColumnMapping) (*Report, error) {
+	columns, err := resolveColumns(sheet.Header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Header: sheet.Header}
+	seen := map[string]int{}
+	seenIDs := map[uuid.UUID]int{}
+	names := make([]string, 0, len(sheet.Records))
+	ids := make([]uuid.UUID, 0, len(sheet.Records))
+
+	for n, values := range sheet.Records {
+		row := &PlannedRow{Line: n + 2, Values: values}
+		row.Stock, row.HasQuantity, row.Err = toStock(values, columns)
+
+		if row.Err == nil {
+			row.Err = dedupe(row, seen, seenIDs)
+		}
+
+		if row.Err == nil {
+			names = append(names, row.Stock.Name)
+
+			if row.Stock.ID != uuid.Nil {
+				ids = append(ids, row.Stock.ID)
+			}
+		}
+
+		report.Rows = append(report.Rows, row)
+	}
+
+	named, err := i.service.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	identified, err := i.service.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*entities.Stock, len(named))
+	for _, stock := range named {
+		byName[stock.Name] = stock
+	}
+
+	byID := make(map[uuid.UUID]*entities.Stock, len(identified))
+	for _, stock := range identified {
+		byID[stock.ID] = stock
+	}
+
+	for _, row := range report.Rows {
+		if row.Err == nil {
+			row.Err = match(row, byID, byName)
+		}
+
+		if row.Err == nil && row.Existing != nil && !row.HasQuantity {
+			row.Stock.Quantity = row.Existing.Quantity
+		}
+
+		if row.Err == nil {
+			row.Err = i.check(row)
+		}
+
+		row.Action = plannedAction(row)
+	}
+
+	return report, nil
+}
+
+// dedupe rejects the rows naming a stock item, or an id, of an earlier row of the file.
+func dedupe(row *PlannedRow, seen map[string]int, seenIDs map[uuid.UUID]int) error {
+	if line, ok := seen[row.Stock.Name]; ok {
+		return errors.New(fmt.Sprintf("duplicate of line %d", line))
+	}
+
+	if line, ok := seenIDs[row.Stock.ID]; ok && row.Stock.ID != uuid.Nil {
+		return errors.New(fmt.Sprintf("duplicate of line %d", line))
+	}
+
+	seen[row.Stock.Name] = row.Line
+	if row.Stock.ID != uuid.Nil {
+		seenIDs[row.Stock.ID] = row.Line
+	}
+
+	return nil
+}
+
+// match finds the stored stock item of a row, by its id when it has one, by its name otherwise.
+// A row may not take the name of another stock item, whether it creates or renames one.
+func match(row *PlannedRow, byID map[uuid.UUID]*entities.Stock, byName map[string]*entities.Stock) error {
+	named := byName[row.Stock.Name]
+
+	if row.Stock.ID == uuid.Nil {
+		row.Existing = named
+		return nil
+	}
+
+	row.Existing = byID[row.Stock.ID]
+
+	if named != nil && (row.Existing == nil || named.ID != row.Existing.ID) {
+		return errors.New(fmt.Sprintf("name already used by stock with id: %s", named.ID))
+	}
+
+	return nil
+}
+
+// Apply persists the planned creates and updates, rows failing to persist are turned into rejects.
+// The updates without a quantity only rename their stock item.
+func (i *StockImporter) Apply(ctx context.Context, report *Report) error {
+	creates := report.rowsFor(Create)
+	updates := make([]*PlannedRow, 0)
+	renames := make([]*PlannedRow, 0)
+
+	for _, r := range report.rowsFor(Update) {
+		r.Stock.ID = r.Existing.ID
+
+		if r.HasQuantity {
+			updates = append(updates, r)
+		} else {
+			renames = append(renames, r)
+		}
+	}
+
+	if err := i.apply(ctx, creates, i.service.InsertMany); err != nil {
+		return err
+	}
+
+	if err := i.apply(ctx, updates, i.service.UpdateMany); err != nil {
+		return err
+	}
+
+	return i.apply(ctx, renames, i.renameMany)
+}
+
+// Count returns the number of rows planned for the given action.
+func (r *Report) Count(action Action) int {
+	return len(r.rowsFor(action))
+}
+
+// WriteRejects writes the rejected rows as CSV, with the reason in an extra "error" column.
+func (r *Report) WriteRejects(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(append(append([]string{}, r.Header...), "error")); err != nil {
+		return err
+	}
+
+	for _, row := range r.rowsFor(Reject) {
+		if err := writer.Write(append(append([]string{}, row.Values...), row.Err.Error())); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func (r *Report) rowsFor(action Action) []*PlannedRow {
+	rows := make([]*PlannedRow, 0)
+
+	for _, row := range r.Rows {
+		if row.Action == action {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
+func (i *StockImporter) apply(
+	ctx context.Context,
+	rows []*PlannedRow,
+	fn func(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error),
+) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	items := make([]*entities.BatchItem, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, &entities.BatchItem{Stock: r.Stock})
+	}
+
+	results, err := fn(ctx, items, true)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			i.logger.Errorf("line %d: %s", rows[res.Index].Line, res.Err)
+
+			rows[res.Index].Err = res.Err
+			rows[res.Index].Action = Reject
+		}
+	}
+
+	return nil
+}
+
+// renameMany updates the name alone of the stock items, one at a time.
+func (i *StockImporter) renameMany(ctx context.Context, items []*entities.BatchItem, _ bool) (entities.BatchResults, error) {
+	results := make(entities.BatchResults, 0, len(items))
+
+	for idx, item := range items {
+		err := i.service.PatchOne(ctx, item.Stock, item.Stock.ID.String(), []string{FieldName})
+		results = append(results, &entities.BatchResult{Index: idx, ID: item.Stock.ID, Err: err})
+	}
+
+	return results, nil
+}
+
+// check runs the same validation the APIs apply on create/update.
+func (i *StockImporter) check(row *PlannedRow) error {
+	stock := row.Stock
+	vl := val.New()
+
+	if row.Existing == nil {
+		if err := vl.Struct(validators.NewInsertStock(stock)); err != nil {
+			return err
+		}
+	} else {
+		if err := vl.Struct(validators.UpdateStock{
+			Name:     stock.Name,
+			Quantity: int(stock.Quantity),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return i.service.Check(stock)
+}
+
+func plannedAction(row *PlannedRow) Action {
+	switch {
+	case row.Err != nil:
+		return Reject
+	case row.Existing == nil:
+		return Create
+	case row.Existing.Quantity == row.Stock.Quantity && row.Existing.Name == row.Stock.Name:
+		return Unchanged
+	}
+
+	return Update
+}
+
+// resolveColumns maps every stock field to the index of its column, -1 when absent.
+func resolveColumns(header []string, mapping ColumnMapping) (map[string]int, error) {
+	columns := make(map[string]int, len(mapping))
+
+	for field, column := range mapping {
+		columns[field] = -1
+
+		for idx, h := range header {
+			if strings.EqualFold(h, column) {
+				columns[field] = idx
+			}
+		}
+	}
+
+	if columns[FieldName] == -1 {
+		return nil, errors.New(fmt.Sprintf("missing column '%s' for the stock name", mapping[FieldName]))
+	}
+
+	return columns, nil
+}
+
+// toStock reads the stock item of a row, and whether the row has a quantity.
+func toStock(values []string, columns map[string]int) (*entities.Stock, bool, error) {
+	cell := func(field string) string {
+		idx := columns[field]
+		if idx < 0 || idx >= len(values) {
+			return ""
+		}
+
+		return strings.TrimSpace(values[idx])
+	}
+
+	stock := &entities.Stock{Name: cell(FieldName)}
+
+	if raw := cell(FieldID); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, false, errors.New(fmt.Sprintf("invalid id: '%s'", raw))
+		}
+
+		stock.ID = id
+	}
+
+	raw := cell(FieldQuantity)
+	if raw == "" {
+		return stock, false, nil
+	}
+
+	quantity, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, false, errors.New(fmt.Sprintf("invalid quantity: '%s'", raw))
+	}
+
+	stock.Quantity = quantity
+
+	return stock, true, nil
+}
@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+)
+
+// Engine decides which principals may perform an action, shared by the gRPC handlers and the HTTP controllers.
+type Engine struct {
+	threshold int64
+	grants    map[string]map[Action]bool
+}
+
+// NewEngine a constructor for the Engine.
+func NewEngine(p *Policy) *Engine {
+	grants := make(map[string]map[Action]bool, len(p.Roles))
+
+	for role, granted := range p.Roles {
+		grants[role] = make(map[Action]bool, len(granted))
+
+		for _, action := range granted {
+			grants[role][action] = true
+		}
+	}
+
+	return &Engine{
+		threshold: p.AdjustThreshold,
+		grants:    grants,
+	}
+}
+
+// Allowed returns true if any role of the principal grants the action.
+// Calls only lack a principal when the authentication is disabled, they are allowed everything.
+func (e *Engine) Allowed(p *entities.Principal, action Action) bool {
+	if p == nil {
+		return true
+	}
+
+	for _, role := range p.Roles {
+		if e.grants[role][action] || e.grants[role][Any] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authorize returns a permission denied error, unless the principal of the context is allowed the action.
+func (e *Engine) Authorize(ctx context.Context, action Action) error {
+	if e.Allowed(entities.PrincipalFrom(ctx), action) {
+		return nil
+	}
+
+	return errs.New(errs.PermissionDenied, fmt.Sprintf("%s is not allowed", action))
+}
+
+// AuthorizeQuantity authorizes setting a stock quantity, the changes beyond the threshold requiring AdjustStock.
+// The current quantity is only looked up for the principals that aren't allowed AdjustStock.
+func (e *Engine) AuthorizeQuantity(ctx context.Context, quantity int64, current func() (int64, error)) error {
+	if e.threshold == 0 || e.Allowed(entities.PrincipalFrom(ctx), AdjustStock) {
+		return nil
+	}
+
+	was, err := current()
+	if err != nil {
+		return err
+	}
+
+	if delta := quantity - was; delta > e.threshold || -delta > e.threshold {
+		return errs.New(errs.PermissionDenied, fmt.Sprintf(
+			"changing the quantity by %d is beyond the threshold of %d, %s is required", delta, e.threshold, AdjustStock,
+		)).On("quantity")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	"stocks-api/support/config"
+)
+
+// Action an operation guarded by the policy.
+type Action string
+
+const (
+	ReadStock   Action = "stock:read"
+	ExportStock Action = "stock:export"
+	WatchStock  Action = "stock:watch"
+	CreateStock Action = "stock:create"
+	UpdateStock Action = "stock:update"
+	DeleteStock Action = "stock:delete"
+	// AdjustStock changing a stock quantity by more than the adjust threshold.
+	AdjustStock Action = "stock:adjust"
+	// ManageWebhooks reading and changing the webhook subscriptions.
+	ManageWebhooks Action = "webhook:manage"
+	// Any grants every action.
+	Any Action = "*"
+)
+
+// actions the known actions, the policy files can't grant any other.
+var actions = map[Action]bool{
+	ReadStock:      true,
+	ExportStock:    true,
+	WatchStock:     true,
+	CreateStock:    true,
+	UpdateStock:    true,
+	DeleteStock:    true,
+	AdjustStock:    true,
+	ManageWebhooks: true,
+	Any:            true,
+}
+
+// Policy the actions granted to every role.
+// AdjustThreshold is the largest quantity change allowed without the AdjustStock action, 0 for no limit.
+type Policy struct {
+	AdjustThreshold int64               `yaml:"adjust_threshold"`
+	Roles           map[string][]Action `yaml:"roles"`
+}
+
+// Default the policy used when no policy file is configured.
+func Default() *Policy {
+	return &Policy{
+		AdjustThreshold: 100,
+		Roles: map[string][]Action{
+			"viewer":  {ReadStock, ExportStock, WatchStock},
+			"clerk":   {ReadStock, ExportStock, WatchStock, CreateStock, UpdateStock},
+			"manager": {ReadStock, ExportStock, WatchStock, CreateStock, UpdateStock, DeleteStock, AdjustStock},
+			"admin":   {Any},
+		},
+	}
+}
+
+// Load reads a YAML policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, errors.New(fmt.Sprintf("malformed policy file %s: %s", path, err))
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid policy file %s: %s", path, err))
+	}
+
+	return p, nil
+}
+
+// FromConfig returns the policy of the policy file, the Default one if it is unset.
+func FromConfig(c config.Policy) (*Policy, error) {
+	if c.File == "" {
+		return Default(), nil
+	}
+
+	return Load(c.File)
+}
+
+func (p *Policy) validate() error {
+	if p.AdjustThreshold < 0 {
+		return errors.New("adjust_threshold can't be negative")
+	}
+
+	for role, granted := range p.Roles {
+		for _, action := range granted {
+			if !actions[action] {
+				return errors.New(fmt.Sprintf("role '%s' grants unknown action '%s'", role, action))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/db"
+)
+
+// ApiKeyRepo the repo provides low level logic operations for the API keys.
+type ApiKeyRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewApiKeyRepo a constructor for the Api Key Repo.
+func NewApiKeyRepo(l *logrus.Logger, db *db.Instance) *ApiKeyRepo {
+	return &ApiKeyRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// GetAll returns all keys, newest first.
+func (a *ApiKeyRepo) GetAll(ctx context.Context) ([]*entities.ApiKey, error) {
+	var x []*entities.ApiKey
+
+	err := a.db.Base.NewSelect().
+		Model(&x).
+		OrderExpr("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		a.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// FindByPrefix returns the key with the given prefix, if found.
+func (a *ApiKeyRepo) FindByPrefix(ctx context.Context, prefix string) (*entities.ApiKey, error) {
+	x := entities.ApiKey{}
+
+	err := a.db.Base.NewSelect().
+		Model(&x).
+		Where("prefix = ?", prefix).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.New(errs.NotFound, fmt.Sprintf("api key with prefix: %s doesn't exist", prefix))
+	}
+
+	if err != nil {
+		a.logger.Error(err)
+		return nil, err
+	}
+
+	return &x, nil
+}
+
+// InsertOne adds a new key.
+func (a *ApiKeyRepo) InsertOne(ctx context.Context, key *entities.ApiKey) error {
+	_, err := a.db.Base.NewInsert().
+		Model(key).
+		Exec(ctx)
+	if err != nil {
+		a.logger.Error(err)
+		return dbError(err)
+	}
+
+	return nil
+}
+
+// Revoke marks a key as revoked, if found and not revoked already.
+func (a *ApiKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	res, err := a.db.Base.NewUpdate().
+		Model(new(entities.ApiKey)).
+		Set("revoked_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		a.logger.Error(err)
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errs.New(errs.NotFound, fmt.Sprintf("active api key with id: %s doesn't exist", id))
+	}
+
+	return nil
+}
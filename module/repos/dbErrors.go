@@ -0,0 +1,28 @@
+package repos
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+	"stocks-api/module/errs"
+)
+
+// dbError classifies the Postgres errors that are caused by the request rather than the db, by their SQLSTATE.
+func dbError(err error) error {
+	var pgErr pgdriver.Error
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch code := pgErr.Field('C'); {
+	case code == "23505":
+		return errs.Wrap(errs.Conflict, err)
+	case code == "40001", code == "40P01", code == "55P03":
+		return errs.Wrap(errs.Aborted, err)
+	case strings.HasPrefix(code, "22"):
+		return errs.Wrap(errs.InvalidArgument, err)
+	}
+
+	return err
+}
@@ -0,0 +1,152 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/db"
+)
+
+// IdempotencyRepo the repo provides low level logic operations for the idempotency records.
+type IdempotencyRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewIdempotencyRepo a constructor for the Idempotency Repo.
+func NewIdempotencyRepo(l *logrus.Logger, db *db.Instance) *IdempotencyRepo {
+	return &IdempotencyRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// Claim stores a new record in progress, taking over an expired record of the same key
+// or one left in progress for longer than the lock. Returns false if the key is held already.
+func (i *IdempotencyRepo) Claim(ctx context.Context, record *entities.IdempotencyRecord, lock time.Duration) (bool, error) {
+	now := time.Now()
+
+	var affected int64
+
+	err := tenantTx(ctx, i.db, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewInsert().
+			Model(record).
+			On("CONFLICT (tenant_id, key) DO UPDATE").
+			Set("fingerprint = EXCLUDED.fingerprint").
+			Set("status = 0").
+			Set("headers = NULL").
+			Set("response = NULL").
+			Set("created_at = EXCLUDED.created_at").
+			Set("completed_at = NULL").
+			Set("expires_at = EXCLUDED.expires_at").
+			Where("idempotency_record.expires_at < ?", now).
+			WhereOr("idempotency_record.completed_at IS NULL AND idempotency_record.created_at < ?", now.Add(-lock)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		affected, _ = res.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		i.logger.Error(err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Find returns the record of the key, if found.
+func (i *IdempotencyRepo) Find(ctx context.Context, key string) (*entities.IdempotencyRecord, error) {
+	x := entities.IdempotencyRecord{}
+
+	err := tenantTx(ctx, i.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().Model(&x)).
+			Where("key = ?", key).
+			Scan(ctx)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.New(errs.NotFound, fmt.Sprintf("idempotency record with key: %s doesn't exist", key))
+	}
+
+	if err != nil {
+		i.logger.Error(err)
+		return nil, err
+	}
+
+	return &x, nil
+}
+
+// Complete stores the outcome of the request holding the key.
+func (i *IdempotencyRepo) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	now := time.Now()
+	record.CompletedAt = &now
+
+	err := tenantTx(ctx, i.db, func(ctx context.Context, tx bun.Tx) error {
+		_, err := scoped(ctx, tx.NewUpdate().Model(record)).
+			Column("status", "headers", "response", "completed_at").
+			Where("key = ?", record.Key).
+			Where("completed_at IS NULL").
+			Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		i.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Release drops the record of a request that failed, so that it can be retried with the same key.
+func (i *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	err := tenantTx(ctx, i.db, func(ctx context.Context, tx bun.Tx) error {
+		_, err := scoped(ctx, tx.NewDelete().Model(new(entities.IdempotencyRecord))).
+			Where("key = ?", key).
+			Where("completed_at IS NULL").
+			Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		i.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge deletes the records of every tenant that expired before the given time, returning their number.
+func (i *IdempotencyRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := allTenantsTx(ctx, i.db, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model(new(entities.IdempotencyRecord)).
+			Where("expires_at < ?", before).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		purged, _ = res.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		i.logger.Error(err)
+		return 0, err
+	}
+
+	return purged, nil
+}
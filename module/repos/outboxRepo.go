@@ -0,0 +1,74 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/support/db"
+)
+
+// OutboxRepo the repo provides the low level outbox operations of the broker relay.
+type OutboxRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewOutboxRepo a constructor for the Outbox Repo.
+func NewOutboxRepo(l *logrus.Logger, db *db.Instance) *OutboxRepo {
+	return &OutboxRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// PublishPending passes up to limit unpublished events, oldest first, to fn and marks them as published if it succeeds.
+// The events stay locked for other relays meanwhile. Returns the number of events published.
+func (o *OutboxRepo) PublishPending(ctx context.Context, limit int, fn func(ctx context.Context, events ...*entities.OutboxEvent) error) (int, error) {
+	published := 0
+
+	err := allTenantsTx(ctx, o.db, func(ctx context.Context, tx bun.Tx) error {
+		var events []*entities.OutboxEvent
+
+		err := tx.NewSelect().
+			Model(&events).
+			Where("published_at IS NULL").
+			OrderExpr("created_at ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		if err := fn(ctx, events...); err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(events))
+		for _, ev := range events {
+			ids = append(ids, ev.ID)
+		}
+
+		_, err = tx.NewUpdate().
+			Model(new(entities.OutboxEvent)).
+			Set("published_at = ?", time.Now()).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		published = len(events)
+
+		return nil
+	})
+	if err != nil {
+		o.logger.Error(err)
+	}
+
+	return published, err
+}
@@ -0,0 +1,75 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/support/db"
+)
+
+// RateLimitRepo the repo provides low level logic operations for the rate limit buckets.
+type RateLimitRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewRateLimitRepo a constructor for the Rate Limit Repo.
+func NewRateLimitRepo(l *logrus.Logger, db *db.Instance) *RateLimitRepo {
+	return &RateLimitRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// Take updates the bucket of the key with fn, holding a lock on it so that the replicas take their turns.
+// A bucket seen for the first time is passed to fn empty, without UpdatedAt.
+func (r *RateLimitRepo) Take(ctx context.Context, key string, fn func(bucket *entities.RateLimitBucket)) error {
+	err := r.db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		bucket := &entities.RateLimitBucket{Key: key}
+
+		err := tx.NewSelect().
+			Model(bucket).
+			For("UPDATE").
+			WherePK().
+			Scan(ctx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		fn(bucket)
+
+		_, err = tx.NewInsert().
+			Model(bucket).
+			On("CONFLICT (key) DO UPDATE").
+			Set("tokens = EXCLUDED.tokens").
+			Set("updated_at = EXCLUDED.updated_at").
+			Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge deletes the buckets left untouched since the given time, returning their number.
+func (r *RateLimitRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.Base.NewDelete().
+		Model(new(entities.RateLimitBucket)).
+		Where("updated_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		r.logger.Error(err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
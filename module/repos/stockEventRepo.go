@@ -0,0 +1,116 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/support/db"
+)
+
+// StockEventRepo the repo provides read access to the recorded stock events.
+type StockEventRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewStockEventRepo a constructor for the Stock Event Repo.
+func NewStockEventRepo(l *logrus.Logger, db *db.Instance) *StockEventRepo {
+	return &StockEventRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// EventsSince returns up to limit events recorded after the given sequence, oldest first.
+// When a tenant or stockIDs are given, only the events of that tenant and of those stock items are returned,
+// the events of every tenant otherwise.
+func (s *StockEventRepo) EventsSince(
+	ctx context.Context,
+	seq int64,
+	tenant string,
+	stockIDs []uuid.UUID,
+	limit int,
+) ([]*entities.StockEvent, error) {
+	var x []*entities.StockEvent
+
+	inTx := allTenantsTx
+	if tenant != "" {
+		ctx, inTx = entities.WithTenant(ctx, tenant), tenantTx
+	}
+
+	err := inTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		q := tx.NewSelect().
+			Model(&x).
+			Where("seq > ?", seq).
+			OrderExpr("seq ASC").
+			Limit(limit)
+
+		if tenant != "" {
+			q = scoped(ctx, q)
+		}
+
+		if len(stockIDs) > 0 {
+			q = q.Where("stock_id IN (?)", bun.In(stockIDs))
+		}
+
+		return q.Scan(ctx)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// LatestSeq returns the sequence of the last recorded event, 0 if there are none.
+func (s *StockEventRepo) LatestSeq(ctx context.Context) (int64, error) {
+	var seq int64
+
+	err := allTenantsTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		return tx.NewSelect().
+			Model(new(entities.StockEvent)).
+			ColumnExpr("COALESCE(MAX(seq), 0)").
+			Scan(ctx, &seq)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// LatestOf returns up to last events of each of the given stock items, newest first.
+func (s *StockEventRepo) LatestOf(ctx context.Context, stockIDs []uuid.UUID, last int) ([]*entities.StockEvent, error) {
+	var x []*entities.StockEvent
+
+	if len(stockIDs) == 0 {
+		return x, nil
+	}
+
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		ranked := scoped(ctx, tx.NewSelect().
+			Model(new(entities.StockEvent)).
+			ColumnExpr("stock_event.*").
+			ColumnExpr("row_number() OVER (PARTITION BY stock_id ORDER BY seq DESC) AS rank").
+			Where("stock_id IN (?)", bun.In(stockIDs)))
+
+		return tx.NewSelect().
+			With("ranked", ranked).
+			Model(&x).
+			ModelTableExpr("ranked AS stock_event").
+			Where("rank <= ?", last).
+			OrderExpr("seq DESC").
+			Scan(ctx)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/uptrace/bun"
 	"stocks-api/module/entities"
 	"stocks-api/module/entities/filters"
+	"stocks-api/module/errs"
 	"stocks-api/support/db"
 )
 
@@ -28,124 +29,343 @@ func NewStockRepo(l *logrus.Logger, db *db.Instance) *StockRepo {
 	}
 }
 
-// Count counts all records in the db.
-func (s *StockRepo) Count(ctx context.Context) (int, error) {
-	return s.db.Base.NewSelect().
-		Model(new(entities.Stock)).
-		Count(ctx)
+// Count counts all records in the db matching the filter, if any.
+func (s *StockRepo) Count(ctx context.Context, filter *filters.StockFilter) (int, error) {
+	count := 0
+
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+
+		count, err = applyFilter(scoped(ctx, tx.NewSelect().Model(new(entities.Stock))), filter).
+			Count(ctx)
+
+		return err
+	})
+
+	return count, err
 }
 
-// GetAll returns all records from the database.
-func (s *StockRepo) GetAll(ctx context.Context, pagination *filters.Pagination) ([]*entities.Stock, error) {
+// GetAll returns a page of the records matching the filter, if any, from the database.
+func (s *StockRepo) GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error) {
 	var x []*entities.Stock
 
-	s.db.Base.NewSelect().
-		Model(new(entities.Stock)).
-		OrderExpr("created_at ASC").
-		Limit(pagination.ItemsPerPage).
-		Offset(filters.GenerateOffset(pagination.Page, pagination.ItemsPerPage)).
-		Scan(ctx, &x)
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		return applyFilter(scoped(ctx, tx.NewSelect().Model(new(entities.Stock))), filter).
+			OrderExpr("created_at ASC").
+			Limit(pagination.ItemsPerPage).
+			Offset(filters.GenerateOffset(pagination.Page, pagination.ItemsPerPage)).
+			Scan(ctx, &x)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
 
 	return x, nil
 }
 
+// Stream passes every record matching the filter to fn, one at a time, without loading them all in memory.
+func (s *StockRepo) Stream(ctx context.Context, filter *filters.StockFilter, fn func(stock *entities.Stock) error) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		rows, err := applyFilter(scoped(ctx, tx.NewSelect().Model(new(entities.Stock))), filter).
+			OrderExpr("created_at ASC").
+			Rows(ctx)
+		if err != nil {
+			s.logger.Error(err)
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			stock := new(entities.Stock)
+
+			if err := s.db.Base.ScanRow(ctx, rows, stock); err != nil {
+				s.logger.Error(err)
+				return err
+			}
+
+			if err := fn(stock); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
 // GetOne returns a single record from the database, if found.
 func (s *StockRepo) GetOne(ctx context.Context, id uuid.UUID) (*entities.Stock, error) {
 	var x entities.Stock
 
-	exists, errExists := s.db.Base.NewSelect().
-		Model(&x).
-		Where("id = ?", id).
-		Exists(ctx)
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
+			For("SHARE").
+			Model(&x).
+			Where("id = ?", id)).
+			Scan(ctx)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.New(errs.NotFound, fmt.Sprintf("record with id: %s doesn't exist", id))
+	}
 
-	if errExists != nil {
-		s.logger.Error(errExists)
-		return nil, errExists
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
 	}
 
-	if exists {
-		s.db.Base.NewSelect().
-			For("SHARE").
+	return &x, nil
+}
+
+// FindByNames returns all records matching any of the given names.
+func (s *StockRepo) FindByNames(ctx context.Context, names []string) ([]*entities.Stock, error) {
+	var x []*entities.Stock
+
+	if len(names) == 0 {
+		return x, nil
+	}
+
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
 			Model(&x).
-			Where("id = ?", id).
+			Where("name IN (?)", bun.In(names))).
 			Scan(ctx)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
 
-		return &x, nil
+	return x, nil
+}
+
+// FindByIDs returns all records matching any of the given ids.
+func (s *StockRepo) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Stock, error) {
+	var x []*entities.Stock
+
+	if len(ids) == 0 {
+		return x, nil
 	}
 
-	return nil, errors.New(fmt.Sprintf("record with id: %s doesn't exist", id))
+	err := tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
+			Model(&x).
+			Where("id IN (?)", bun.In(ids))).
+			Scan(ctx)
+	})
+	if err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
 }
 
-// InsertOne adds a new record in the database.
+// InsertOne adds a new record in the database, the stock is refreshed with the stored record.
 func (s *StockRepo) InsertOne(ctx context.Context, stock *entities.Stock) error {
-	return s.db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
 		_, err := tx.NewInsert().
 			Model(stock).
+			Returning("*").
 			Exec(ctx)
+		if err != nil {
+			s.logger.Error(err)
+			return dbError(err)
+		}
 
-		s.logger.Error(err)
-
-		return err
+		return recordEvent(ctx, tx, entities.Created, stock)
 	})
 }
 
-// UpdateOne updates a single record in the database, if found.
-func (s *StockRepo) UpdateOne(ctx context.Context, stock *entities.Stock) error {
-	currentRecord := entities.Stock{}
+// UpdateOne updates a single record in the database, if found, the stock is refreshed with the stored record.
+// When columns are given only those are updated.
+func (s *StockRepo) UpdateOne(ctx context.Context, stock *entities.Stock, columns ...string) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		currentRecord := entities.Stock{}
 
-	errExists := s.db.Base.NewSelect().
-		For("UPDATE").
-		Model(stock).
-		Where("id = ?", stock.ID).
-		Scan(ctx, &currentRecord)
-	if errExists != nil {
-		s.logger.Error(errExists)
-		return errExists
-	}
+		errExists := scoped(ctx, tx.NewSelect().
+			For("UPDATE").
+			Model(&currentRecord).
+			Where("id = ?", stock.ID)).
+			Scan(ctx)
+		if errors.Is(errExists, sql.ErrNoRows) {
+			return errs.New(errs.NotFound, fmt.Sprintf("record with id: %s doesn't exist", stock.ID))
+		}
 
-	return s.db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		stock.CreatedAt = currentRecord.CreatedAt
+		if errExists != nil {
+			s.logger.Error(errExists)
+			return errExists
+		}
 
-		// TODO: handle "dirty" values !
+		stock.CreatedAt = currentRecord.CreatedAt
+		stock.TenantID = currentRecord.TenantID
 
-		_, err := tx.NewUpdate().
+		q := scoped(ctx, tx.NewUpdate().
 			Model(stock).
-			WherePK().
-			Exec(ctx)
+			WherePK()).
+			Returning("*")
 
-		s.logger.Error(err)
+		if len(columns) > 0 {
+			q = q.Column(append(columns, "updated_at")...)
+		}
 
-		return err
+		_, err := q.Exec(ctx)
+		if err != nil {
+			s.logger.Error(err)
+			return dbError(err)
+		}
+
+		return recordEvent(ctx, tx, entities.Updated, stock)
 	})
 }
 
 // DeleteOne removes a record from the database, if found.
 func (s *StockRepo) DeleteOne(ctx context.Context, id uuid.UUID) error {
-	exists, errExists := s.db.Base.NewSelect().
-		For("UPDATE").
-		Model(new(entities.Stock)).
-		Where("id = ?", id).
-		Exists(ctx)
-	if errExists != nil {
-		s.logger.Error(errExists)
-		return errExists
-	}
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		deleted := new(entities.Stock)
+
+		res, err := scoped(ctx, tx.NewDelete().
+			Model(deleted).
+			Where("id = ?", id)).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			s.logger.Error(err)
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			err := errs.New(errs.NotFound, fmt.Sprintf("record with id: %s doesn't exist", id))
+
+			s.logger.Error(err)
+			return err
+		}
+
+		return recordEvent(ctx, tx, entities.Deleted, deleted)
+	})
+}
+
+// InsertMany adds multiple records in the database, within a single transaction.
+func (s *StockRepo) InsertMany(ctx context.Context, stocks []*entities.Stock) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		for i, stock := range stocks {
+			if _, err := tx.NewInsert().Model(stock).Exec(ctx); err != nil {
+				s.logger.Error(err)
+				return fmt.Errorf("item %d: %w", i, dbError(err))
+			}
+
+			if err := recordEvent(ctx, tx, entities.Created, stock); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdateMany updates multiple records in the database, within a single transaction.
+func (s *StockRepo) UpdateMany(ctx context.Context, stocks []*entities.Stock) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		for i, stock := range stocks {
+			currentRecord := entities.Stock{}
+
+			errExists := scoped(ctx, tx.NewSelect().
+				For("UPDATE").
+				Model(&currentRecord).
+				Where("id = ?", stock.ID)).
+				Scan(ctx)
+			if errors.Is(errExists, sql.ErrNoRows) {
+				return fmt.Errorf("item %d: %w", i, errs.New(errs.NotFound, fmt.Sprintf("record with id: %s doesn't exist", stock.ID)))
+			}
+
+			if errExists != nil {
+				s.logger.Error(errExists)
+				return fmt.Errorf("item %d: %w", i, errExists)
+			}
+
+			stock.CreatedAt = currentRecord.CreatedAt
+			stock.TenantID = currentRecord.TenantID
+
+			if _, err := scoped(ctx, tx.NewUpdate().Model(stock).WherePK()).Exec(ctx); err != nil {
+				s.logger.Error(err)
+				return fmt.Errorf("item %d: %w", i, dbError(err))
+			}
+
+			if err := recordEvent(ctx, tx, entities.Updated, stock); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteMany removes multiple records from the database, within a single transaction.
+func (s *StockRepo) DeleteMany(ctx context.Context, ids []uuid.UUID) error {
+	return tenantTx(ctx, s.db, func(ctx context.Context, tx bun.Tx) error {
+		for i, id := range ids {
+			deleted := new(entities.Stock)
+
+			res, err := scoped(ctx, tx.NewDelete().
+				Model(deleted).
+				Where("id = ?", id)).
+				Returning("*").
+				Exec(ctx)
+			if err != nil {
+				s.logger.Error(err)
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+
+			if affected, _ := res.RowsAffected(); affected == 0 {
+				return fmt.Errorf("item %d: %w", i, errs.New(errs.NotFound, fmt.Sprintf("record with id: %s doesn't exist", id)))
+			}
 
-	if !exists {
-		err := errors.New(fmt.Sprintf("record with id: %s doesn't exist", id))
+			if err := recordEvent(ctx, tx, entities.Deleted, deleted); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
 
+// Inventory sums up the stock of every tenant, counting the items at or below the threshold as low on stock.
+// Unlike the other operations, it isn't bound to the tenant of the context.
+func (s *StockRepo) Inventory(ctx context.Context, threshold int64) ([]*entities.Inventory, error) {
+	var x []*entities.Inventory
+
+	if err := s.db.Base.NewRaw("SELECT * FROM stock_inventory(?)", threshold).Scan(ctx, &x); err != nil {
 		s.logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	return s.db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		_, err := tx.NewDelete().
-			Model(new(entities.Stock)).
-			Where("id = ?", id).
-			Exec(ctx)
+	return x, nil
+}
 
-		s.logger.Error(err)
+// recordEvent writes the domain event of a mutation to the outbox, within the mutation's transaction.
+func recordEvent(ctx context.Context, tx bun.Tx, t entities.EventType, stock *entities.Stock) error {
+	_, err := tx.NewInsert().
+		Model(entities.NewOutboxEvent(t, stock)).
+		Exec(ctx)
 
-		return err
-	})
+	return err
+}
+
+func applyFilter(q *bun.SelectQuery, filter *filters.StockFilter) *bun.SelectQuery {
+	if filter == nil {
+		return q
+	}
+
+	if filter.Name != "" {
+		q = q.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+
+	if filter.MinQuantity != nil {
+		q = q.Where("quantity >= ?", *filter.MinQuantity)
+	}
+
+	if filter.MaxQuantity != nil {
+		q = q.Where("quantity <= ?", *filter.MaxQuantity)
+	}
+
+	return q
 }
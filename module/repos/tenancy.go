@@ -0,0 +1,43 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/support/db"
+)
+
+// whereQuery the bun queries that can be restricted by a WHERE clause.
+type whereQuery[Q any] interface {
+	Where(query string, args ...interface{}) Q
+}
+
+// scoped restricts a query on a tenant-scoped table to the rows of the tenant of the context.
+func scoped[Q whereQuery[Q]](ctx context.Context, q Q) Q {
+	return q.Where("?TableAlias.tenant_id = ?", entities.TenantFrom(ctx))
+}
+
+// tenantTx runs fn in a transaction bound to the tenant of the context, for the row-level security policies.
+func tenantTx(ctx context.Context, db *db.Instance, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config('app.tenant_id', ?, true)", entities.TenantFrom(ctx)); err != nil {
+			return err
+		}
+
+		return fn(ctx, tx)
+	})
+}
+
+// allTenantsTx runs fn in a transaction across every tenant, for the background jobs the row-level security policies
+// would otherwise hide every row from.
+func allTenantsTx(ctx context.Context, db *db.Instance, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return db.Base.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config('app.all_tenants', 'on', true)"); err != nil {
+			return err
+		}
+
+		return fn(ctx, tx)
+	})
+}
@@ -0,0 +1,355 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/db"
+)
+
+// WebhookRepo the repo provides low level logic operations for the webhook subscriptions and their deliveries.
+type WebhookRepo struct {
+	logger *logrus.Logger
+	db     *db.Instance
+}
+
+// NewWebhookRepo a constructor for the Webhook Repo.
+func NewWebhookRepo(l *logrus.Logger, db *db.Instance) *WebhookRepo {
+	return &WebhookRepo{
+		logger: l,
+		db:     db,
+	}
+}
+
+// GetAll returns all subscriptions.
+func (w *WebhookRepo) GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	var x []*entities.WebhookSubscription
+
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
+			Model(&x)).
+			OrderExpr("created_at ASC").
+			Scan(ctx)
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// GetOne returns a single subscription, if found.
+func (w *WebhookRepo) GetOne(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	x := entities.WebhookSubscription{}
+
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
+			Model(&x).
+			Where("id = ?", id)).
+			Scan(ctx)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.New(errs.NotFound, fmt.Sprintf("subscription with id: %s doesn't exist", id))
+	}
+
+	if err != nil {
+		w.logger.Error(err)
+		return nil, err
+	}
+
+	return &x, nil
+}
+
+// InsertOne adds a new subscription.
+func (w *WebhookRepo) InsertOne(ctx context.Context, sub *entities.WebhookSubscription) error {
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(sub).
+			Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		w.logger.Error(err)
+	}
+
+	return err
+}
+
+// UpdateOne updates a single subscription, if found.
+func (w *WebhookRepo) UpdateOne(ctx context.Context, sub *entities.WebhookSubscription) error {
+	var affected int64
+
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		res, err := scoped(ctx, tx.NewUpdate().
+			Model(sub).
+			Column("url", "event_types", "active", "updated_at").
+			WherePK()).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		affected, _ = res.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return err
+	}
+
+	if affected == 0 {
+		return errs.New(errs.NotFound, fmt.Sprintf("subscription with id: %s doesn't exist", sub.ID))
+	}
+
+	return nil
+}
+
+// DeleteOne removes a subscription and its pending deliveries, if found.
+func (w *WebhookRepo) DeleteOne(ctx context.Context, id uuid.UUID) error {
+	var affected int64
+
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		res, err := scoped(ctx, tx.NewDelete().
+			Model(new(entities.WebhookSubscription)).
+			Where("id = ?", id)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		affected, _ = res.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return err
+	}
+
+	if affected == 0 {
+		return errs.New(errs.NotFound, fmt.Sprintf("subscription with id: %s doesn't exist", id))
+	}
+
+	return nil
+}
+
+// DeadLetters returns the deliveries that ran out of attempts, newest first.
+func (w *WebhookRepo) DeadLetters(ctx context.Context, limit int) ([]*entities.WebhookDeadLetter, error) {
+	var x []*entities.WebhookDeadLetter
+
+	err := tenantTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		return scoped(ctx, tx.NewSelect().
+			Model(&x)).
+			OrderExpr("created_at DESC").
+			Limit(limit).
+			Scan(ctx)
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// FanOut turns up to limit undispatched outbox events into one delivery per matching active subscription of their tenant.
+// Returns the number of events dispatched.
+func (w *WebhookRepo) FanOut(ctx context.Context, limit int) (int, error) {
+	dispatched := 0
+
+	err := allTenantsTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		var events []*entities.OutboxEvent
+
+		err := tx.NewSelect().
+			Model(&events).
+			Where("dispatched_at IS NULL").
+			OrderExpr("created_at ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		var subs []*entities.WebhookSubscription
+
+		if err := tx.NewSelect().Model(&subs).Where("active").Scan(ctx); err != nil {
+			return err
+		}
+
+		deliveries := make([]*entities.WebhookDelivery, 0)
+		ids := make([]uuid.UUID, 0, len(events))
+
+		for _, ev := range events {
+			ids = append(ids, ev.ID)
+
+			for _, sub := range subs {
+				if sub.TenantID == ev.TenantID && sub.Accepts(ev.Type) {
+					deliveries = append(deliveries, &entities.WebhookDelivery{
+						ID:             uuid.New(),
+						SubscriptionID: sub.ID,
+						EventID:        ev.ID,
+						NextAttemptAt:  time.Now(),
+					})
+				}
+			}
+		}
+
+		if len(deliveries) > 0 {
+			if _, err := tx.NewInsert().Model(&deliveries).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewUpdate().
+			Model(new(entities.OutboxEvent)).
+			Set("dispatched_at = ?", time.Now()).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		dispatched = len(events)
+
+		return nil
+	})
+	if err != nil {
+		w.logger.Error(err)
+	}
+
+	return dispatched, err
+}
+
+// ClaimDue returns up to limit deliveries due for an attempt, leasing them for the given duration
+// so that other dispatchers skip them meanwhile.
+func (w *WebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entities.WebhookDelivery, error) {
+	var deliveries []*entities.WebhookDelivery
+
+	err := allTenantsTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&deliveries).
+			Relation("Subscription").
+			Relation("Event").
+			Where("webhook_delivery.next_attempt_at <= ?", time.Now()).
+			OrderExpr("webhook_delivery.next_attempt_at ASC").
+			Limit(limit).
+			For("UPDATE OF webhook_delivery SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+
+		_, err = tx.NewUpdate().
+			Model(new(entities.WebhookDelivery)).
+			Set("next_attempt_at = ?", time.Now().Add(lease)).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Delivered removes a successful delivery.
+func (w *WebhookRepo) Delivered(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	_, err := w.db.Base.NewDelete().
+		Model(delivery).
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// Purge deletes the outbox events of every tenant dispatched before the given time, and delivered to every subscription
+// since, returning their number. If relayed, the events not published by the broker relay yet are kept too.
+func (w *WebhookRepo) Purge(ctx context.Context, before time.Time, relayed bool) (int64, error) {
+	var purged int64
+
+	err := allTenantsTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		q := tx.NewDelete().
+			Model(new(entities.OutboxEvent)).
+			Where("dispatched_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM webhook_delivery WHERE webhook_delivery.event_id = ?TableAlias.id)")
+
+		if relayed {
+			q = q.Where("published_at IS NOT NULL")
+		}
+
+		res, err := q.Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		purged, _ = res.RowsAffected()
+
+		return nil
+	})
+	if err != nil {
+		w.logger.Error(err)
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// Reschedule records a failed attempt and when to try again.
+func (w *WebhookRepo) Reschedule(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	_, err := w.db.Base.NewUpdate().
+		Model(delivery).
+		Column("attempts", "next_attempt_at", "last_error").
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// DeadLetter moves a delivery that ran out of attempts to the dead letter table.
+func (w *WebhookRepo) DeadLetter(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	return allTenantsTx(ctx, w.db, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(&entities.WebhookDeadLetter{
+				ID:             delivery.ID,
+				TenantID:       delivery.Subscription.TenantID,
+				SubscriptionID: delivery.SubscriptionID,
+				EventID:        delivery.EventID,
+				URL:            delivery.Subscription.URL,
+				Payload:        delivery.Event,
+				Attempts:       delivery.Attempts,
+				LastError:      delivery.LastError,
+			}).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model(delivery).
+			WherePK().
+			Exec(ctx)
+
+		return err
+	})
+}
@@ -0,0 +1,56 @@
+package resolvers
+
+import (
+	"stocks-api/module/errs"
+)
+
+// errorCodes the codes exposed in the extensions of the GraphQL errors, named after their gRPC counterparts.
+var errorCodes = map[errs.Kind]string{
+	errs.Internal:          "INTERNAL",
+	errs.NotFound:          "NOT_FOUND",
+	errs.Conflict:          "ALREADY_EXISTS",
+	errs.InvalidArgument:   "INVALID_ARGUMENT",
+	errs.Aborted:           "ABORTED",
+	errs.Unauthenticated:   "UNAUTHENTICATED",
+	errs.PermissionDenied:  "PERMISSION_DENIED",
+	errs.ResourceExhausted: "RESOURCE_EXHAUSTED",
+}
+
+// resolverError a domain error, classified in the GraphQL error extensions.
+type resolverError struct {
+	err error
+}
+
+func (e *resolverError) Error() string {
+	if errs.KindOf(e.err) == errs.Internal {
+		return "internal error"
+	}
+
+	return e.err.Error()
+}
+
+// Extensions exposes the error code and the violated field, if any.
+func (e *resolverError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{
+		"code": errorCodes[errs.KindOf(e.err)],
+	}
+
+	if field := errs.FieldOf(e.err); field != "" {
+		ext["field"] = field
+	}
+
+	return ext
+}
+
+// wrap classifies an error for the GraphQL response, logging the internal ones.
+func (r *Resolver) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errs.KindOf(err) == errs.Internal {
+		r.logger.Error(err)
+	}
+
+	return &resolverError{err: err}
+}
@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+	"stocks-api/module/entities"
+)
+
+type loadersKey struct{}
+
+// movementKey a movements lookup, the items requested with the same page size are batched together.
+type movementKey struct {
+	StockID uuid.UUID
+	Last    int
+}
+
+// loaders the per-request batching loaders, so that nested fields don't trigger a query per item (N+1).
+type loaders struct {
+	movements *dataloader.Loader[movementKey, []*entities.StockEvent]
+}
+
+// WithLoaders attaches fresh loaders to the context of a request.
+func WithLoaders(ctx context.Context, service StockService) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		movements: dataloader.NewBatchedLoader(movementsBatch(service)),
+	})
+}
+
+// loadersFrom returns the loaders of the request, or fresh ones if none were attached.
+func loadersFrom(ctx context.Context, service StockService) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+
+	return WithLoaders(ctx, service).Value(loadersKey{}).(*loaders)
+}
+
+// movementsBatch loads the movements of all requested items with one query per distinct page size.
+func movementsBatch(service StockService) dataloader.BatchFunc[movementKey, []*entities.StockEvent] {
+	return func(ctx context.Context, keys []movementKey) []*dataloader.Result[[]*entities.StockEvent] {
+		ids := map[int][]uuid.UUID{}
+		for _, key := range keys {
+			ids[key.Last] = append(ids[key.Last], key.StockID)
+		}
+
+		movements := map[int]map[uuid.UUID][]*entities.StockEvent{}
+		failures := map[int]error{}
+
+		for last, stockIDs := range ids {
+			movements[last], failures[last] = service.Movements(ctx, stockIDs, last)
+		}
+
+		results := make([]*dataloader.Result[[]*entities.StockEvent], 0, len(keys))
+		for _, key := range keys {
+			results = append(results, &dataloader.Result[[]*entities.StockEvent]{
+				Data:  movements[key.Last][key.StockID],
+				Error: failures[key.Last],
+			})
+		}
+
+		return results
+	}
+}
@@ -0,0 +1,101 @@
+package resolvers
+
+import (
+	"context"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+	"stocks-api/module/validators"
+)
+
+type newStockInput struct {
+	ID       *graphql.ID
+	Name     string
+	Quantity Long
+}
+
+type stockPatchInput struct {
+	Name     *string
+	Quantity *Long
+}
+
+// CreateStock creates a stock item.
+func (r *Resolver) CreateStock(ctx context.Context, args struct{ Input newStockInput }) (*stockResolver, error) {
+	if err := r.policy.Authorize(ctx, policy.CreateStock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	stock := &entities.Stock{
+		Name:     args.Input.Name,
+		Quantity: int64(args.Input.Quantity),
+	}
+
+	input := validators.InsertStock{Name: stock.Name, Quantity: int(stock.Quantity)}
+	if args.Input.ID != nil {
+		input.ID = string(*args.Input.ID)
+	}
+
+	if err := val.New().Struct(input); err != nil {
+		return nil, r.wrap(errs.Invalid(err))
+	}
+
+	if input.ID != "" {
+		stock.ID, _ = uuid.Parse(input.ID)
+	}
+
+	if err := r.service.InsertOne(ctx, stock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	return &stockResolver{stock: stock, service: r.service}, nil
+}
+
+// UpdateStock updates the given fields of a stock item.
+func (r *Resolver) UpdateStock(ctx context.Context, args struct {
+	ID    graphql.ID
+	Patch stockPatchInput
+}) (*stockResolver, error) {
+	if err := r.policy.Authorize(ctx, policy.UpdateStock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	stock := &entities.Stock{}
+	fields := make([]string, 0, 2)
+
+	if args.Patch.Name != nil {
+		stock.Name = *args.Patch.Name
+		fields = append(fields, "name")
+	}
+
+	if args.Patch.Quantity != nil {
+		stock.Quantity = int64(*args.Patch.Quantity)
+		fields = append(fields, "quantity")
+
+		if err := r.policy.AuthorizeQuantity(ctx, stock.Quantity, r.quantity(ctx, string(args.ID))); err != nil {
+			return nil, r.wrap(err)
+		}
+	}
+
+	if err := r.service.PatchOne(ctx, stock, string(args.ID), fields); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	return &stockResolver{stock: stock, service: r.service}, nil
+}
+
+// DeleteStock removes a stock item, returning its id.
+func (r *Resolver) DeleteStock(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	if err := r.policy.Authorize(ctx, policy.DeleteStock); err != nil {
+		return "", r.wrap(err)
+	}
+
+	if err := r.service.DeleteOne(ctx, string(args.ID)); err != nil {
+		return "", r.wrap(err)
+	}
+
+	return args.ID, nil
+}
@@ -0,0 +1,97 @@
+package resolvers
+
+import (
+	"context"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/graph-gophers/graphql-go"
+	"stocks-api/module/entities/filters"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+	"stocks-api/module/validators"
+)
+
+type stockFilterInput struct {
+	Name        *string
+	MinQuantity *Long
+	MaxQuantity *Long
+}
+
+// Stock resolves a single stock item, null if it doesn't exist.
+func (r *Resolver) Stock(ctx context.Context, args struct{ ID graphql.ID }) (*stockResolver, error) {
+	if err := r.policy.Authorize(ctx, policy.ReadStock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	stock, err := r.service.GetOne(ctx, string(args.ID))
+	if errs.KindOf(err) == errs.NotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+
+	return &stockResolver{stock: stock, service: r.service}, nil
+}
+
+// Stocks resolves a page of the stock items matching the filter.
+func (r *Resolver) Stocks(ctx context.Context, args struct {
+	Page         int32
+	ItemsPerPage int32
+	Filter       *stockFilterInput
+}) (*stockPageResolver, error) {
+	if err := r.policy.Authorize(ctx, policy.ReadStock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	pagination := &filters.Pagination{Page: int(args.Page), ItemsPerPage: int(args.ItemsPerPage)}
+
+	err := val.New().Struct(validators.Pagination{Page: pagination.Page, ItemsPerPage: pagination.ItemsPerPage})
+	if err != nil {
+		return nil, r.wrap(errs.Invalid(err))
+	}
+
+	filter := toFilter(args.Filter)
+
+	stocks, err := r.service.GetAll(ctx, pagination, filter)
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+
+	count, err := r.service.Count(ctx, filter)
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+
+	page := &stockPageResolver{totalCount: int32(count)}
+	for _, stock := range stocks {
+		page.stocks = append(page.stocks, &stockResolver{stock: stock, service: r.service})
+	}
+
+	return page, nil
+}
+
+func toFilter(input *stockFilterInput) *filters.StockFilter {
+	if input == nil {
+		return nil
+	}
+
+	filter := &filters.StockFilter{}
+
+	if input.Name != nil {
+		filter.Name = *input.Name
+	}
+
+	if input.MinQuantity != nil {
+		min := int64(*input.MinQuantity)
+		filter.MinQuantity = &min
+	}
+
+	if input.MaxQuantity != nil {
+		max := int64(*input.MaxQuantity)
+		filter.MaxQuantity = &max
+	}
+
+	return filter
+}
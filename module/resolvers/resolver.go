@@ -0,0 +1,83 @@
+package resolvers
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/entities/filters"
+	"stocks-api/module/policy"
+	"stocks-api/module/services"
+	"stocks-api/support/db"
+)
+
+// maxDepth bounds the nesting of the queries.
+const maxDepth = 8
+
+//go:embed schema.graphql
+var schemaString string
+
+// StockService a contract to the StockService.
+type StockService interface {
+	GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error)
+	GetOne(ctx context.Context, stockId string) (*entities.Stock, error)
+	InsertOne(ctx context.Context, stock *entities.Stock) error
+	PatchOne(ctx context.Context, stock *entities.Stock, stockId string, fields []string) error
+	DeleteOne(ctx context.Context, stockId string) error
+	Count(ctx context.Context, filter *filters.StockFilter) (int, error)
+	Movements(ctx context.Context, stockIDs []uuid.UUID, last int) (map[uuid.UUID][]*entities.StockEvent, error)
+}
+
+// EventWatcher a contract to the stock event broker.
+type EventWatcher interface {
+	Watch(ctx context.Context, fromSeq int64, stockIDs []uuid.UUID, fn func(event *entities.StockEvent) error) error
+}
+
+// Resolver the root resolver of the GraphQL schema.
+type Resolver struct {
+	logger  *logrus.Logger
+	service StockService
+	watcher EventWatcher
+	policy  *policy.Engine
+}
+
+// NewResolver a constructor for the Resolver.
+func NewResolver(
+	l *logrus.Logger,
+	db *db.Instance,
+	ctx context.Context,
+	watcher EventWatcher,
+	engine *policy.Engine,
+) *Resolver {
+	return &Resolver{
+		logger:  l,
+		service: services.NewStockService(l, db, ctx),
+		watcher: watcher,
+		policy:  engine,
+	}
+}
+
+// quantity the current quantity of a stock item, for the authorization of the quantity changes.
+func (r *Resolver) quantity(ctx context.Context, stockId string) func() (int64, error) {
+	return func() (int64, error) {
+		current, err := r.service.GetOne(ctx, stockId)
+		if err != nil {
+			return 0, err
+		}
+
+		return current.Quantity, nil
+	}
+}
+
+// Schema parses the GraphQL schema, resolved by the Resolver.
+func (r *Resolver) Schema() *graphql.Schema {
+	return graphql.MustParseSchema(schemaString, r, graphql.MaxDepth(maxDepth))
+}
+
+// Loaders attaches the batching loaders to the context of a request.
+func (r *Resolver) Loaders(ctx context.Context) context.Context {
+	return WithLoaders(ctx, r.service)
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Long the Long scalar, GraphQL's own Int being limited to 32 bits.
+type Long int64
+
+// ImplementsGraphQLType maps the type to the Long scalar of the schema.
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL accepts the Long literals and variables, as numbers or numeric strings.
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case int32:
+		*l = Long(v)
+	case int64:
+		*l = Long(v)
+	case float64:
+		if v != math.Trunc(v) {
+			return errors.New(fmt.Sprintf("invalid Long: %v", v))
+		}
+
+		*l = Long(v)
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.New(fmt.Sprintf("invalid Long: '%s'", v))
+		}
+
+		*l = Long(parsed)
+	default:
+		return errors.New(fmt.Sprintf("invalid Long: %v", input))
+	}
+
+	return nil
+}
+
+// MarshalJSON writes the Long as a JSON number.
+func (l Long) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(l))
+}
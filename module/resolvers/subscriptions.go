@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+)
+
+// StockChanged streams the stock changes, until the subscriber goes away or lags behind.
+func (r *Resolver) StockChanged(ctx context.Context, args struct {
+	FromSequence *Long
+	StockIds     *[]graphql.ID
+}) (<-chan *eventResolver, error) {
+	if err := r.policy.Authorize(ctx, policy.WatchStock); err != nil {
+		return nil, r.wrap(err)
+	}
+
+	var fromSeq int64
+	if args.FromSequence != nil {
+		fromSeq = int64(*args.FromSequence)
+	}
+
+	var ids []uuid.UUID
+
+	if args.StockIds != nil {
+		for _, raw := range *args.StockIds {
+			id, err := uuid.Parse(string(raw))
+			if err != nil {
+				return nil, r.wrap(errs.New(errs.InvalidArgument, fmt.Sprintf("invalid stock id: '%s'", raw)).On("stockIds"))
+			}
+
+			ids = append(ids, id)
+		}
+	}
+
+	events := make(chan *eventResolver)
+
+	go func() {
+		defer close(events)
+
+		err := r.watcher.Watch(ctx, fromSeq, ids, func(event *entities.StockEvent) error {
+			select {
+			case events <- &eventResolver{event: event, service: r.service}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			r.logger.Errorf("GraphQL subscription closed: %s", err)
+		}
+	}()
+
+	return events, nil
+}
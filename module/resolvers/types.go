@@ -0,0 +1,110 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+)
+
+// maxMovements bounds the movements returned per stock item.
+const maxMovements = 100
+
+type stockResolver struct {
+	stock   *entities.Stock
+	service StockService
+}
+
+func (s *stockResolver) ID() graphql.ID {
+	return graphql.ID(s.stock.ID.String())
+}
+
+func (s *stockResolver) Name() string {
+	return s.stock.Name
+}
+
+func (s *stockResolver) Quantity() Long {
+	return Long(s.stock.Quantity)
+}
+
+func (s *stockResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: s.stock.CreatedAt}
+}
+
+func (s *stockResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: s.stock.UpdatedAt}
+}
+
+// Movements resolves the last changes of the item, batched with the ones of its siblings.
+func (s *stockResolver) Movements(ctx context.Context, args struct{ Last int32 }) ([]*movementResolver, error) {
+	if args.Last < 1 || args.Last > maxMovements {
+		return nil, &resolverError{err: errs.New(errs.InvalidArgument, "last must be between 1 and 100").On("last")}
+	}
+
+	events, err := loadersFrom(ctx, s.service).movements.Load(ctx, movementKey{StockID: s.stock.ID, Last: int(args.Last)})()
+	if err != nil {
+		return nil, &resolverError{err: err}
+	}
+
+	movements := make([]*movementResolver, 0, len(events))
+	for _, ev := range events {
+		movements = append(movements, &movementResolver{event: ev})
+	}
+
+	return movements, nil
+}
+
+type movementResolver struct {
+	event *entities.StockEvent
+}
+
+func (m *movementResolver) Sequence() Long {
+	return Long(m.event.Seq)
+}
+
+func (m *movementResolver) Type() string {
+	return string(m.event.Type)
+}
+
+func (m *movementResolver) Quantity() Long {
+	return Long(m.event.Stock.Quantity)
+}
+
+func (m *movementResolver) OccurredAt() graphql.Time {
+	return graphql.Time{Time: m.event.CreatedAt}
+}
+
+type eventResolver struct {
+	event   *entities.StockEvent
+	service StockService
+}
+
+func (e *eventResolver) Sequence() Long {
+	return Long(e.event.Seq)
+}
+
+func (e *eventResolver) Type() string {
+	return string(e.event.Type)
+}
+
+func (e *eventResolver) Stock() *stockResolver {
+	return &stockResolver{stock: e.event.Stock, service: e.service}
+}
+
+func (e *eventResolver) OccurredAt() graphql.Time {
+	return graphql.Time{Time: e.event.CreatedAt}
+}
+
+type stockPageResolver struct {
+	stocks     []*stockResolver
+	totalCount int32
+}
+
+func (p *stockPageResolver) Stocks() []*stockResolver {
+	return p.stocks
+}
+
+func (p *stockPageResolver) TotalCount() int32 {
+	return p.totalCount
+}
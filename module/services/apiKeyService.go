@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/repos"
+	"stocks-api/support/db"
+)
+
+// ApiKeyPrefix the scheme every API key starts with, telling them apart from the JWTs.
+const ApiKeyPrefix = "sk_"
+
+// ApiKeyStore a contract to the Api Key Repo.
+type ApiKeyStore interface {
+	GetAll(ctx context.Context) ([]*entities.ApiKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*entities.ApiKey, error)
+	InsertOne(ctx context.Context, key *entities.ApiKey) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+// ApiKeyService provides high level logic for the API keys.
+type ApiKeyService struct {
+	repo   ApiKeyStore
+	logger *logrus.Logger
+}
+
+// NewApiKeyService a constructor for the Api Key Service.
+func NewApiKeyService(l *logrus.Logger, db *db.Instance) *ApiKeyService {
+	return &ApiKeyService{
+		repo:   repos.NewApiKeyRepo(l, db),
+		logger: l,
+	}
+}
+
+// GetAll returns all keys, without their hashes.
+func (a *ApiKeyService) GetAll(ctx context.Context) ([]*entities.ApiKey, error) {
+	return a.repo.GetAll(ctx)
+}
+
+// Mint adds a new key, valid for the given ttl, or forever if it is zero.
+// The plaintext key is only ever returned here.
+func (a *ApiKeyService) Mint(
+	ctx context.Context,
+	name string,
+	roles []string,
+	ttl time.Duration,
+) (*entities.ApiKey, string, error) {
+	if name == "" {
+		return nil, "", errs.New(errs.InvalidArgument, "api key name is required").On("name")
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &entities.ApiKey{
+		Name:   name,
+		Prefix: prefix,
+		Hash:   HashApiKeySecret(secret),
+		Roles:  roles,
+	}
+
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := a.repo.InsertOne(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, fmt.Sprintf("%s%s_%s", ApiKeyPrefix, prefix, secret), nil
+}
+
+// Revoke revokes a key, it can't be used from then on.
+func (a *ApiKeyService) Revoke(ctx context.Context, keyId string) error {
+	id, errParse := parseID(keyId)
+	if errParse != nil {
+		return errParse
+	}
+
+	return a.repo.Revoke(ctx, id)
+}
+
+// Authenticate returns the principal of a plaintext key, if it is known and usable.
+func (a *ApiKeyService) Authenticate(ctx context.Context, plaintext string) (*entities.Principal, error) {
+	prefix, secret, err := ParseApiKey(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := a.repo.FindByPrefix(ctx, prefix)
+	if errs.KindOf(err) == errs.NotFound {
+		return nil, errs.New(errs.Unauthenticated, "unknown api key")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(HashApiKeySecret(secret))) != 1 {
+		return nil, errs.New(errs.Unauthenticated, "unknown api key")
+	}
+
+	if !key.Usable(time.Now()) {
+		return nil, errs.New(errs.Unauthenticated, "api key is revoked or expired")
+	}
+
+	return &entities.Principal{
+		Subject: key.ID.String(),
+		Kind:    entities.ApiKeyPrincipal,
+		Roles:   key.Roles,
+		Tenant:  key.TenantID,
+	}, nil
+}
+
+// ParseApiKey splits a plaintext key into its lookup prefix and its secret.
+func ParseApiKey(plaintext string) (string, string, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(plaintext, ApiKeyPrefix), "_")
+	if !strings.HasPrefix(plaintext, ApiKeyPrefix) || !ok || prefix == "" || secret == "" {
+		return "", "", errs.New(errs.Unauthenticated, "malformed api key")
+	}
+
+	return prefix, secret, nil
+}
+
+// HashApiKeySecret the hex encoded SHA-256 of a key secret, as stored in the database.
+func HashApiKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -2,29 +2,49 @@ package services
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"stocks-api/module/entities"
 	"stocks-api/module/entities/filters"
+	"stocks-api/module/errs"
 	"stocks-api/module/repos"
 	"stocks-api/support/db"
+	"stocks-api/support/tracing"
 )
 
 // StockStore a contract to the Stock Repo.
 type StockStore interface {
-	GetAll(ctx context.Context, pagination *filters.Pagination) ([]*entities.Stock, error)
+	GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error)
 	GetOne(ctx context.Context, id uuid.UUID) (*entities.Stock, error)
 	InsertOne(ctx context.Context, stock *entities.Stock) error
-	UpdateOne(ctx context.Context, stock *entities.Stock) error
+	UpdateOne(ctx context.Context, stock *entities.Stock, columns ...string) error
 	DeleteOne(ctx context.Context, id uuid.UUID) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, filter *filters.StockFilter) (int, error)
+	FindByNames(ctx context.Context, names []string) ([]*entities.Stock, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Stock, error)
+	Stream(ctx context.Context, filter *filters.StockFilter, fn func(stock *entities.Stock) error) error
+	InsertMany(ctx context.Context, stocks []*entities.Stock) error
+	UpdateMany(ctx context.Context, stocks []*entities.Stock) error
+	DeleteMany(ctx context.Context, ids []uuid.UUID) error
+}
+
+// patchableColumns the stock fields a partial update may set, mapped to their columns.
+var patchableColumns = map[string]string{
+	"name":     "name",
+	"quantity": "quantity",
+}
+
+// EventStore a contract to the Stock Event Repo.
+type EventStore interface {
+	LatestOf(ctx context.Context, stockIDs []uuid.UUID, last int) ([]*entities.StockEvent, error)
 }
 
 // StockService provides high level logic.
 type StockService struct {
 	repo    StockStore
+	events  EventStore
 	logger  *logrus.Logger
 	db      *db.Instance
 	Context context.Context
@@ -34,19 +54,26 @@ type StockService struct {
 func NewStockService(l *logrus.Logger, db *db.Instance, ctx context.Context) *StockService {
 	return &StockService{
 		repo:    repos.NewStockRepo(l, db),
+		events:  repos.NewStockEventRepo(l, db),
 		db:      db,
 		Context: ctx,
 	}
 }
 
-// GetAll returns all records in the db.
-func (s *StockService) GetAll(ctx context.Context, filters *filters.Pagination) ([]*entities.Stock, error) {
-	return s.repo.GetAll(ctx, filters)
+// GetAll returns a page of the records in the db, narrowed down by the filter if given.
+func (s *StockService) GetAll(ctx context.Context, pagination *filters.Pagination, filter *filters.StockFilter) ([]*entities.Stock, error) {
+	ctx, span := tracing.Start(ctx, "StockService.GetAll")
+	defer span.End()
+
+	return s.repo.GetAll(ctx, pagination, filter)
 }
 
 // GetOne returns a single record in the db.
 func (s *StockService) GetOne(ctx context.Context, stockId string) (*entities.Stock, error) {
-	id, errParse := uuid.Parse(stockId)
+	ctx, span := tracing.Start(ctx, "StockService.GetOne")
+	defer span.End()
+
+	id, errParse := parseID(stockId)
 	if errParse != nil {
 		return nil, errParse
 	}
@@ -54,8 +81,35 @@ func (s *StockService) GetOne(ctx context.Context, stockId string) (*entities.St
 	return s.repo.GetOne(ctx, id)
 }
 
+// FindByNames returns all records matching any of the given names.
+func (s *StockService) FindByNames(ctx context.Context, names []string) ([]*entities.Stock, error) {
+	ctx, span := tracing.Start(ctx, "StockService.FindByNames")
+	defer span.End()
+
+	return s.repo.FindByNames(ctx, names)
+}
+
+// FindByIDs returns all records matching any of the given ids.
+func (s *StockService) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Stock, error) {
+	ctx, span := tracing.Start(ctx, "StockService.FindByIDs")
+	defer span.End()
+
+	return s.repo.FindByIDs(ctx, ids)
+}
+
+// Export passes every record matching the filter to fn, streamed from the db.
+func (s *StockService) Export(ctx context.Context, filter *filters.StockFilter, fn func(stock *entities.Stock) error) error {
+	ctx, span := tracing.Start(ctx, "StockService.Export")
+	defer span.End()
+
+	return s.repo.Stream(ctx, filter, fn)
+}
+
 // InsertOne adds a new record in the db.
 func (s *StockService) InsertOne(ctx context.Context, stock *entities.Stock) error {
+	ctx, span := tracing.Start(ctx, "StockService.InsertOne")
+	defer span.End()
+
 	if err := checkQuantity(stock); err != nil {
 		return err
 	}
@@ -65,7 +119,10 @@ func (s *StockService) InsertOne(ctx context.Context, stock *entities.Stock) err
 
 // UpdateOne updates a single record in the db.
 func (s *StockService) UpdateOne(ctx context.Context, stock *entities.Stock, stockId string) error {
-	id, errParse := uuid.Parse(stockId)
+	ctx, span := tracing.Start(ctx, "StockService.UpdateOne")
+	defer span.End()
+
+	id, errParse := parseID(stockId)
 	if errParse != nil {
 		return errParse
 	}
@@ -79,9 +136,44 @@ func (s *StockService) UpdateOne(ctx context.Context, stock *entities.Stock, sto
 	return s.repo.UpdateOne(ctx, stock)
 }
 
+// PatchOne updates only the given fields of a single record in the db, leaving the others untouched.
+func (s *StockService) PatchOne(ctx context.Context, stock *entities.Stock, stockId string, fields []string) error {
+	ctx, span := tracing.Start(ctx, "StockService.PatchOne")
+	defer span.End()
+
+	id, errParse := parseID(stockId)
+	if errParse != nil {
+		return errParse
+	}
+
+	columns, err := patchColumns(fields)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		if column == "name" && stock.Name == "" {
+			return errs.New(errs.InvalidArgument, "Name can't be empty").On("name")
+		}
+
+		if column == "quantity" {
+			if err := checkQuantity(stock); err != nil {
+				return err
+			}
+		}
+	}
+
+	stock.ID = id
+
+	return s.repo.UpdateOne(ctx, stock, columns...)
+}
+
 // DeleteOne removes a record from the db.
 func (s *StockService) DeleteOne(ctx context.Context, stockId string) error {
-	id, errParse := uuid.Parse(stockId)
+	ctx, span := tracing.Start(ctx, "StockService.DeleteOne")
+	defer span.End()
+
+	id, errParse := parseID(stockId)
 	if errParse != nil {
 		return errParse
 	}
@@ -89,16 +181,203 @@ func (s *StockService) DeleteOne(ctx context.Context, stockId string) error {
 	return s.repo.DeleteOne(ctx, id)
 }
 
-// Count returns the number of all records in the db.
-func (s *StockService) Count(ctx context.Context) (int, error) {
-	return s.repo.Count(ctx)
+// Movements returns up to last recorded changes of each of the given stock items, newest first.
+func (s *StockService) Movements(ctx context.Context, stockIDs []uuid.UUID, last int) (map[uuid.UUID][]*entities.StockEvent, error) {
+	ctx, span := tracing.Start(ctx, "StockService.Movements")
+	defer span.End()
+
+	events, err := s.events.LatestOf(ctx, stockIDs, last)
+	if err != nil {
+		return nil, err
+	}
+
+	movements := make(map[uuid.UUID][]*entities.StockEvent, len(stockIDs))
+	for _, ev := range events {
+		movements[ev.StockID] = append(movements[ev.StockID], ev)
+	}
+
+	return movements, nil
+}
+
+// Count returns the number of the records in the db, narrowed down by the filter if given.
+func (s *StockService) Count(ctx context.Context, filter *filters.StockFilter) (int, error) {
+	ctx, span := tracing.Start(ctx, "StockService.Count")
+	defer span.End()
+
+	return s.repo.Count(ctx, filter)
+}
+
+// InsertMany adds multiple records in the db.
+// Unless bestEffort is set, the whole batch is rejected if a single item fails.
+func (s *StockService) InsertMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error) {
+	ctx, span := tracing.Start(ctx, "StockService.InsertMany")
+	defer span.End()
+
+	if bestEffort {
+		return runEach(items, func(stock *entities.Stock) error {
+			return s.InsertOne(ctx, stock)
+		}), nil
+	}
+
+	stocks, err := collectValid(items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.InsertMany(ctx, stocks); err != nil {
+		return nil, err
+	}
+
+	return toResults(stocks), nil
+}
+
+// UpdateMany updates multiple records in the db, each item must carry its ID.
+// Unless bestEffort is set, the whole batch is rejected if a single item fails.
+func (s *StockService) UpdateMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error) {
+	ctx, span := tracing.Start(ctx, "StockService.UpdateMany")
+	defer span.End()
+
+	if bestEffort {
+		return runEach(items, func(stock *entities.Stock) error {
+			return s.UpdateOne(ctx, stock, stock.ID.String())
+		}), nil
+	}
+
+	stocks, err := collectValid(items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMany(ctx, stocks); err != nil {
+		return nil, err
+	}
+
+	return toResults(stocks), nil
+}
+
+// DeleteMany removes multiple records from the db, each item must carry its ID.
+// Unless bestEffort is set, the whole batch is rejected if a single item fails.
+func (s *StockService) DeleteMany(ctx context.Context, items []*entities.BatchItem, bestEffort bool) (entities.BatchResults, error) {
+	ctx, span := tracing.Start(ctx, "StockService.DeleteMany")
+	defer span.End()
+
+	if bestEffort {
+		return runEach(items, func(stock *entities.Stock) error {
+			return s.repo.DeleteOne(ctx, stock.ID)
+		}), nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(items))
+
+	for i, item := range items {
+		if item.Err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, item.Err)
+		}
+
+		ids = append(ids, item.Stock.ID)
+	}
+
+	if err := s.repo.DeleteMany(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	results := make(entities.BatchResults, 0, len(ids))
+	for i, id := range ids {
+		results = append(results, &entities.BatchResult{Index: i, ID: id})
+	}
+
+	return results, nil
+}
+
+// runEach applies fn to every valid item independently, collecting per-item results.
+func runEach(items []*entities.BatchItem, fn func(stock *entities.Stock) error) entities.BatchResults {
+	results := make(entities.BatchResults, 0, len(items))
+
+	for i, item := range items {
+		err := item.Err
+		if err == nil {
+			err = fn(item.Stock)
+		}
+
+		results = append(results, &entities.BatchResult{Index: i, ID: item.Stock.ID, Err: err})
+	}
+
+	return results
+}
+
+// collectValid unwraps the batch items, failing on the first invalid one.
+func collectValid(items []*entities.BatchItem) ([]*entities.Stock, error) {
+	stocks := make([]*entities.Stock, 0, len(items))
+
+	for i, item := range items {
+		if item.Err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, item.Err)
+		}
+
+		if err := checkQuantity(item.Stock); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		stocks = append(stocks, item.Stock)
+	}
+
+	return stocks, nil
+}
+
+func toResults(stocks []*entities.Stock) entities.BatchResults {
+	results := make(entities.BatchResults, 0, len(stocks))
+
+	for i, stock := range stocks {
+		results = append(results, &entities.BatchResult{Index: i, ID: stock.ID})
+	}
+
+	return results
+}
+
+// Check runs the service level checks on a record, without persisting it.
+func (s *StockService) Check(stock *entities.Stock) error {
+	return checkQuantity(stock)
 }
 
 // checkQuantity a custom quantity check, due to the unique way Go handles zero values.
 func checkQuantity(s *entities.Stock) error {
 	if s.Quantity < 0 {
-		return errors.New("Input quantity is less than 0")
+		return errs.New(errs.InvalidArgument, "Input quantity is less than 0").On("quantity")
 	}
 
 	return nil
 }
+
+// patchColumns maps the patched fields to their columns, rejecting the unknown and read-only ones.
+func patchColumns(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, errs.New(errs.InvalidArgument, "No fields to update")
+	}
+
+	columns := make([]string, 0, len(fields))
+	seen := map[string]bool{}
+
+	for _, field := range fields {
+		column, ok := patchableColumns[field]
+		if !ok {
+			return nil, errs.New(errs.InvalidArgument, fmt.Sprintf("field '%s' can't be updated", field)).On(field)
+		}
+
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// parseID parses a record id, classifying a malformed one as an invalid argument.
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, errs.New(errs.InvalidArgument, fmt.Sprintf("invalid id: '%s'", raw)).On("id")
+	}
+
+	return id, nil
+}
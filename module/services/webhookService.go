@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/repos"
+	"stocks-api/support/db"
+)
+
+// deadLettersLimit the number of dead letters listed at most.
+const deadLettersLimit = 100
+
+// WebhookStore a contract to the Webhook Repo.
+type WebhookStore interface {
+	GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	GetOne(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error)
+	InsertOne(ctx context.Context, sub *entities.WebhookSubscription) error
+	UpdateOne(ctx context.Context, sub *entities.WebhookSubscription) error
+	DeleteOne(ctx context.Context, id uuid.UUID) error
+	DeadLetters(ctx context.Context, limit int) ([]*entities.WebhookDeadLetter, error)
+}
+
+// WebhookService provides high level logic for the webhook subscriptions.
+type WebhookService struct {
+	repo   WebhookStore
+	logger *logrus.Logger
+}
+
+// NewWebhookService a constructor for the Webhook Service.
+func NewWebhookService(l *logrus.Logger, db *db.Instance) *WebhookService {
+	return &WebhookService{
+		repo:   repos.NewWebhookRepo(l, db),
+		logger: l,
+	}
+}
+
+// GetAll returns all subscriptions, without their secrets.
+func (w *WebhookService) GetAll(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	subs, err := w.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+
+	return subs, nil
+}
+
+// GetOne returns a single subscription, without its secret.
+func (w *WebhookService) GetOne(ctx context.Context, subId string) (*entities.WebhookSubscription, error) {
+	id, errParse := parseID(subId)
+	if errParse != nil {
+		return nil, errParse
+	}
+
+	sub, err := w.repo.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.Secret = ""
+
+	return sub, nil
+}
+
+// InsertOne adds a new subscription, generating its signing secret if none is given.
+// The secret is only ever returned here.
+func (w *WebhookService) InsertOne(ctx context.Context, sub *entities.WebhookSubscription) error {
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+
+		sub.Secret = secret
+	}
+
+	return w.repo.InsertOne(ctx, sub)
+}
+
+// UpdateOne updates the URL, event types and active flag of a subscription.
+func (w *WebhookService) UpdateOne(ctx context.Context, sub *entities.WebhookSubscription, subId string) error {
+	id, errParse := parseID(subId)
+	if errParse != nil {
+		return errParse
+	}
+
+	sub.ID = id
+
+	if err := w.repo.UpdateOne(ctx, sub); err != nil {
+		return err
+	}
+
+	sub.Secret = ""
+
+	return nil
+}
+
+// DeleteOne removes a subscription.
+func (w *WebhookService) DeleteOne(ctx context.Context, subId string) error {
+	id, errParse := parseID(subId)
+	if errParse != nil {
+		return errParse
+	}
+
+	return w.repo.DeleteOne(ctx, id)
+}
+
+// DeadLetters returns the most recent deliveries that ran out of attempts.
+func (w *WebhookService) DeadLetters(ctx context.Context) ([]*entities.WebhookDeadLetter, error) {
+	return w.repo.DeadLetters(ctx, deadLettersLimit)
+}
+
+func generateSecret() (string, error) {
+	return randomHex(32)
+}
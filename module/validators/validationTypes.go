@@ -1,19 +1,52 @@
 package validators
 
+import (
+	"github.com/google/uuid"
+	"stocks-api/module/entities"
+)
+
 // InsertStock a custom validation struct for the insertion fields.
+// The id is optional, when supplied it must be a UUIDv4 like the ids the other requests take.
 type InsertStock struct {
-	ID       string `validate:"" json:"id"`
+	ID       string `validate:"omitempty,uuid4" json:"id"`
 	Name     string `validate:"required,alphanumunicode" json:"name"`
-	Quantity int    `validate:"required,numeric" json:"quantity"`
+	Quantity int    `validate:"min=0" json:"quantity"`
+}
+
+// NewInsertStock the insertion fields of a stock item, without an id unless the caller supplied one.
+func NewInsertStock(stock *entities.Stock) InsertStock {
+	input := InsertStock{Name: stock.Name, Quantity: int(stock.Quantity)}
+	if stock.ID != uuid.Nil {
+		input.ID = stock.ID.String()
+	}
+
+	return input
 }
 
 // UpdateStock a custom validation struct for the update fields.
 type UpdateStock struct {
-	Name     string `validate:"" json:"name"`
-	Quantity int    `validate:"" json:"name"`
+	Name     string `validate:"required" json:"name"`
+	Quantity int    `validate:"min=0" json:"quantity"`
 }
 
 // GetStock a validator for the single get request.
 type GetStock struct {
 	ID string `validate:"required,uuid4" json:"id"`
 }
+
+// Batch a validator for the size of the batch requests.
+type Batch struct {
+	Size int `validate:"min=1,max=1000" json:"size"`
+}
+
+// Webhook a validator for the webhook subscription fields.
+type Webhook struct {
+	URL        string   `validate:"required,url" json:"url"`
+	EventTypes []string `validate:"dive,oneof=CREATED UPDATED DELETED" json:"event_types"`
+}
+
+// Pagination a validator for the query string pagination.
+type Pagination struct {
+	Page         int `validate:"min=1" json:"page"`
+	ItemsPerPage int `validate:"min=1,max=100" json:"items_per_page"`
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/services"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+// ApiKeyAuthenticator a contract to the Api Key Service.
+type ApiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (*entities.Principal, error)
+}
+
+// Authenticator resolves the credentials of a request, a JWT or an API key, to its principal.
+type Authenticator struct {
+	logger *logrus.Logger
+	jwt    *JWTVerifier
+	keys   ApiKeyAuthenticator
+}
+
+// NewAuthenticator a constructor for the Authenticator.
+// Without a verifier only the API keys are accepted.
+func NewAuthenticator(l *logrus.Logger, verifier *JWTVerifier, keys ApiKeyAuthenticator) *Authenticator {
+	return &Authenticator{
+		logger: l,
+		jwt:    verifier,
+		keys:   keys,
+	}
+}
+
+// FromConfig returns the authenticator of the auth config, nil if the authentication is disabled.
+func FromConfig(l *logrus.Logger, db *db.Instance, c config.Auth) (*Authenticator, error) {
+	if c.Disabled {
+		return nil, nil
+	}
+
+	var verifier *JWTVerifier
+
+	cfg := JWTConfig{
+		Secret:   []byte(c.JWTSecret),
+		JWKSFile: c.JWKSFile,
+		Issuer:   c.Issuer,
+		Audience: c.Audience,
+	}
+
+	if len(cfg.Secret) > 0 || cfg.JWKSFile != "" {
+		var err error
+
+		if verifier, err = NewJWTVerifier(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewAuthenticator(l, verifier, services.NewApiKeyService(l, db)), nil
+}
+
+// Authenticate returns the principal of a token, telling the API keys apart by their prefix.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*entities.Principal, error) {
+	if token == "" {
+		return nil, errs.New(errs.Unauthenticated, "missing credentials")
+	}
+
+	if strings.HasPrefix(token, services.ApiKeyPrefix) {
+		return a.keys.Authenticate(ctx, token)
+	}
+
+	if a.jwt == nil {
+		return nil, errs.New(errs.Unauthenticated, "bearer tokens are not accepted, use an api key")
+	}
+
+	return a.jwt.Verify(token)
+}
+
+// AuthenticateCertificate returns the principal of a verified client certificate: its common name, the tenant of its
+// organization and the roles of its organizational units.
+func (a *Authenticator) AuthenticateCertificate(cert *x509.Certificate) (*entities.Principal, error) {
+	if cert.Subject.CommonName == "" {
+		return nil, errs.New(errs.Unauthenticated, "client certificate without a common name")
+	}
+
+	var tenant string
+
+	if len(cert.Subject.Organization) > 0 {
+		if tenant = cert.Subject.Organization[0]; !tenantPattern.MatchString(tenant) {
+			return nil, errs.New(errs.Unauthenticated, fmt.Sprintf("client certificate of invalid tenant: '%s'", tenant))
+		}
+	}
+
+	return &entities.Principal{
+		Subject: cert.Subject.CommonName,
+		Kind:    entities.CertificatePrincipal,
+		Roles:   cert.Subject.OrganizationalUnit,
+		Tenant:  tenant,
+	}, nil
+}
+
+// Credentials picks the token of the Authorization bearer value, or else of the API key header value.
+func Credentials(authorization string, apiKey string) (string, error) {
+	if authorization == "" {
+		return apiKey, nil
+	}
+
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", errs.New(errs.Unauthenticated, "unsupported authorization scheme")
+	}
+
+	return strings.TrimSpace(token), nil
+}
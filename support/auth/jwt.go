@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+)
+
+// leeway the clock skew tolerated on the time based claims.
+const leeway = 30 * time.Second
+
+// JWTConfig the keys and the expected claims of the accepted tokens.
+// HS256 tokens are accepted when Secret is set, RS256 ones when JWKSFile is.
+type JWTConfig struct {
+	Secret   []byte
+	JWKSFile string
+	Issuer   string
+	Audience string
+}
+
+// claims the registered claims, along with the roles granted to the subject and its tenant.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles    []string `json:"roles"`
+	TenantID string   `json:"tenant_id"`
+}
+
+// JWTVerifier verifies the signature and the claims of the bearer tokens.
+type JWTVerifier struct {
+	secret  []byte
+	keys    map[string]*rsa.PublicKey
+	methods []string
+	parser  *jwt.Parser
+}
+
+// NewJWTVerifier a constructor for the JWTVerifier, loading the JWKS file if one is given.
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{secret: cfg.Secret}
+
+	if len(cfg.Secret) > 0 {
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		v.keys = keys
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(v.methods) == 0 {
+		return nil, errors.New("no JWT secret nor JWKS file configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify returns the principal of a valid token.
+func (v *JWTVerifier) Verify(raw string) (*entities.Principal, error) {
+	c := &claims{}
+
+	if _, err := v.parser.ParseWithClaims(raw, c, v.key); err != nil {
+		return nil, errs.New(errs.Unauthenticated, fmt.Sprintf("invalid token: %s", err))
+	}
+
+	if c.Subject == "" {
+		return nil, errs.New(errs.Unauthenticated, "invalid token: missing subject")
+	}
+
+	return &entities.Principal{
+		Subject: c.Subject,
+		Kind:    entities.JWTPrincipal,
+		Roles:   c.Roles,
+		Tenant:  c.TenantID,
+	}, nil
+}
+
+// key picks the verification key by the algorithm of the token, and by its key id for RS256.
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := v.keys[kid]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("unknown key id: '%s'", kid))
+		}
+
+		return key, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unexpected signing method: %s", token.Method.Alg()))
+	}
+}
+
+// jwk a single RSA key of a JSON Web Key Set, as in RFC 7517.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// loadJWKS reads the RSA signing keys of a local JWKS file, by key id.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, errors.New(fmt.Sprintf("malformed JWKS file %s: %s", path, err))
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			return nil, errors.New(fmt.Sprintf("malformed key '%s' in JWKS file %s", k.Kid, path))
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New(fmt.Sprintf("no RSA signing keys in JWKS file %s", path))
+	}
+
+	return keys, nil
+}
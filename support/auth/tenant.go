@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+)
+
+// tenantPattern the accepted tenant ids, safe to use in logs, headers and subjects.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Tenant resolves the tenant a call is scoped to, from its principal and the requested tenant header.
+// Authenticated calls are bound to the tenant of their principal, the header may only repeat it.
+// Anonymous calls, only served when the authentication is disabled, pick their tenant by the header.
+func Tenant(principal *entities.Principal, requested string) (string, error) {
+	if requested != "" && !tenantPattern.MatchString(requested) {
+		return "", errs.New(errs.InvalidArgument, fmt.Sprintf("invalid tenant: '%s'", requested)).On("tenant")
+	}
+
+	if principal == nil {
+		if requested == "" {
+			return entities.DefaultTenant, nil
+		}
+
+		return requested, nil
+	}
+
+	tenant := principal.Tenant
+	if tenant == "" {
+		tenant = entities.DefaultTenant
+	}
+
+	if requested != "" && requested != tenant {
+		return "", errs.New(errs.PermissionDenied, fmt.Sprintf("not a member of tenant '%s'", requested))
+	}
+
+	return tenant, nil
+}
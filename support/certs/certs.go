@@ -0,0 +1,251 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/support/config"
+)
+
+const (
+	// ForwardedHeader carries the client certificate of a call proxied by the REST gateway, as base64 DER.
+	ForwardedHeader = "X-Client-Certificate"
+	// ForwardedMetadataKey the gRPC metadata key the ForwardedHeader is proxied as.
+	ForwardedMetadataKey = "x-client-certificate"
+)
+
+// Reloader serves the certificate of both servers and verifies the client certificates, reloading their files once
+// they change on disk, so that a renewed certificate is picked up without a restart.
+type Reloader struct {
+	logger    *logrus.Logger
+	config    config.TLS
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamp     string
+}
+
+// NewReloader a constructor for the Reloader, loading the files once.
+func NewReloader(l *logrus.Logger, cfg config.TLS) (*Reloader, error) {
+	r := &Reloader{
+		logger: l,
+		config: cfg,
+	}
+
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FromConfig returns the reloader of the TLS config, nil if TLS is disabled.
+func FromConfig(l *logrus.Logger, cfg config.TLS) (*Reloader, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	return NewReloader(l, cfg)
+}
+
+// Reload reads the files again if they changed since they were last loaded, reporting whether they had.
+// The certificate in use is kept when the new files are invalid, e.g. written halfway.
+func (r *Reloader) Reload() (bool, error) {
+	stamp, err := r.stampOf()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := stamp == r.stamp
+	r.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("failed to load the TLS certificate: %s", err))
+	}
+
+	var clientCAs *x509.CertPool
+
+	if r.config.Mutual() {
+		data, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return false, err
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return false, errors.New(fmt.Sprintf("no PEM certificate in %s", r.config.ClientCAFile))
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCAs, r.stamp = &cert, clientCAs, stamp
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// Run checks the files for changes every reload interval.
+func (r *Reloader) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				r.logger.Warningf("TLS certificate not reloaded, keeping the current one: %s", err)
+			} else if reloaded {
+				r.logger.Info("TLS certificate reloaded")
+			}
+		}
+	}
+}
+
+// stampOf the modification times and sizes of the files, telling whether they changed.
+func (r *Reloader) stampOf() (string, error) {
+	var stamp string
+
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+
+		stamp += fmt.Sprintf("%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+
+	return stamp, nil
+}
+
+// Certificate the certificate in use.
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert
+}
+
+// Server the TLS config both servers are served with, picking the certificate in use on each handshake.
+// With mutual TLS the callers may, or must, present a certificate issued by the client CAs.
+func (r *Reloader) Server() *tls.Config {
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+
+	if r.config.Mutual() {
+		// The chains are verified against the client CAs in use, or else as the gateway's own certificate.
+		c.ClientAuth = tls.RequestClientCert
+		if r.config.ClientAuth == "require" {
+			c.ClientAuth = tls.RequireAnyClientCert
+		}
+
+		c.VerifyConnection = r.verifyClient
+	}
+
+	return c
+}
+
+// Gateway the TLS config the REST gateway dials the gRPC server with, presenting the certificate of the servers.
+// The server is trusted by that certificate too, which needn't name the loopback address it's dialed on.
+func (r *Reloader) Gateway() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The chain isn't verified against the roots, the certificate is compared instead.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if !r.own(state) {
+				return errors.New("the gRPC server presented an unexpected certificate")
+			}
+
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+}
+
+// verifyClient verifies the client certificate of a handshake, resumed ones included.
+func (r *Reloader) verifyClient(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 || r.own(state) {
+		return nil
+	}
+
+	r.mu.RLock()
+	roots := r.clientCAs
+	r.mu.RUnlock()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	return err
+}
+
+// own whether the peer of a connection presented the certificate of the servers.
+func (r *Reloader) own(state tls.ConnectionState) bool {
+	cert := r.Certificate()
+
+	return len(state.PeerCertificates) > 0 && bytes.Equal(state.PeerCertificates[0].Raw, cert.Certificate[0])
+}
+
+// Client the client certificate of a connection, verified on handshake. Nil without one, or for the REST gateway.
+func (r *Reloader) Client(state *tls.ConnectionState) *x509.Certificate {
+	if r == nil || state == nil || len(state.PeerCertificates) == 0 || r.own(*state) {
+		return nil
+	}
+
+	return state.PeerCertificates[0]
+}
+
+// Forwarded the client certificate the REST gateway forwarded, nil unless the connection is the gateway's own.
+// The HTTP server only forwards the certificates it verified.
+func (r *Reloader) Forwarded(state *tls.ConnectionState, header string) (*x509.Certificate, error) {
+	if r == nil || state == nil || header == "" || !r.own(*state) {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(raw)
+}
+
+// Forward the header value carrying a client certificate to the gRPC server.
+func Forward(cert *x509.Certificate) string {
+	return base64.StdEncoding.EncodeToString(cert.Raw)
+}
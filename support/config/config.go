@@ -0,0 +1,376 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Config the settings of the servers, the database and the features, loaded once at startup.
+// Each setting is named by its path in the config file, and keeps the env param it was read from before.
+type Config struct {
+	Server      Server      `yaml:"server"`
+	GRPC        GRPC        `yaml:"grpc"`
+	TLS         TLS         `yaml:"tls"`
+	DB          DB          `yaml:"db"`
+	Log         Log         `yaml:"log"`
+	Auth        Auth        `yaml:"auth"`
+	Policy      Policy      `yaml:"policy"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	RateLimit   RateLimit   `yaml:"rate_limit"`
+	Metrics     Metrics     `yaml:"metrics"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Publisher   Publisher   `yaml:"publisher"`
+	Shutdown    Shutdown    `yaml:"shutdown"`
+	Features    Features    `yaml:"features"`
+}
+
+// Server the bind address of the HTTP server.
+type Server struct {
+	Address string `yaml:"address" env:"SERVER_ADDRESS" usage:"the address the HTTP server binds to"`
+	Port    int    `yaml:"port" env:"SERVER_PORT" usage:"the port the HTTP server binds to, a random one if 0"`
+}
+
+// GRPC the bind address of the gRPC server.
+type GRPC struct {
+	Address string `yaml:"address" env:"GRPC_ADDRESS" usage:"the address the gRPC server binds to"`
+	Port    int    `yaml:"port" env:"GRPC_PORT" usage:"the port the gRPC server binds to"`
+}
+
+// TLS the certificate both servers are served with, plaintext if unset, and the CAs of the client certificates.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" env:"TLS_CERT_FILE" usage:"the PEM certificate chain of the servers"`
+	KeyFile        string        `yaml:"key_file" env:"TLS_KEY_FILE" usage:"the PEM private key of the certificate"`
+	ClientCAFile   string        `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE" usage:"the PEM CAs of the client certificates, authenticating the callers by them"`
+	ClientAuth     string        `yaml:"client_auth" env:"TLS_CLIENT_AUTH" usage:"whether the callers may or must present a client certificate: optional or require"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL" usage:"how often the certificate files are checked for changes"`
+}
+
+// DB the connection to Postgres and its pool.
+type DB struct {
+	DSN             string        `yaml:"dsn" env:"DB_DSN" secret:"true" usage:"the connection URL, overriding the host, port, user, password, name and ssl settings"`
+	Host            string        `yaml:"host" env:"DB_HOST" usage:"the host of the database"`
+	Port            int           `yaml:"port" env:"DB_PORT" usage:"the port of the database"`
+	User            string        `yaml:"user" env:"DB_USER" usage:"the user of the database"`
+	Password        string        `yaml:"password" env:"DB_PASS" secret:"true" usage:"the password of the user"`
+	Name            string        `yaml:"name" env:"DB_NAME" usage:"the name of the database"`
+	SSLMode         string        `yaml:"ssl_mode" env:"DB_SSL_MODE" usage:"disable, require, verify-ca or verify-full"`
+	SSLRootCert     string        `yaml:"ssl_root_cert" env:"DB_SSL_ROOT_CERT" usage:"the PEM CAs the certificate of the database is verified against, the system ones if unset"`
+	SSLCert         string        `yaml:"ssl_cert" env:"DB_SSL_CERT" usage:"the PEM client certificate presented to the database"`
+	SSLKey          string        `yaml:"ssl_key" env:"DB_SSL_KEY" usage:"the PEM private key of the client certificate"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" usage:"the connections open at most, unlimited if 0"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" usage:"the idle connections kept at most"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" usage:"how long a connection is reused at most, forever if 0"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" usage:"how long a connection stays idle at most, forever if 0"`
+	Debug           int           `yaml:"debug" env:"BUNDEBUG" usage:"0 logs no query, 1 the failed ones, 2 all of them"`
+}
+
+// Log the level and the format of the log entries.
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" usage:"the lowest level logged: trace, debug, info, warning, error, fatal or panic"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"text or json"`
+}
+
+// Auth the authentication of the calls, see auth.Authenticator.
+type Auth struct {
+	Disabled  bool   `yaml:"disabled" env:"AUTH_DISABLED" usage:"serve every call anonymously"`
+	JWTSecret string `yaml:"jwt_secret" env:"JWT_HS256_SECRET" secret:"true" usage:"the secret of the HS256 tokens"`
+	JWKSFile  string `yaml:"jwks_file" env:"JWT_JWKS_FILE" usage:"the JWKS of the RS256 and ES256 tokens"`
+	Issuer    string `yaml:"issuer" env:"JWT_ISSUER" usage:"the issuer the tokens must carry"`
+	Audience  string `yaml:"audience" env:"JWT_AUDIENCE" usage:"the audience the tokens must carry"`
+}
+
+// Policy the access policy of the principals, see policy.Policy.
+type Policy struct {
+	File string `yaml:"file" env:"POLICY_FILE" usage:"the YAML policy file, the default policy if unset"`
+}
+
+// Idempotency the retention of the idempotency records.
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" usage:"how long the responses are replayed"`
+}
+
+// RateLimit the rate limiting of the calls, see ratelimit.Limiter.
+type RateLimit struct {
+	Disabled bool   `yaml:"disabled" env:"RATE_LIMIT_DISABLED" usage:"let every call through"`
+	File     string `yaml:"file" env:"RATE_LIMIT_FILE" usage:"the YAML limits file, the default limits if unset"`
+	Store    string `yaml:"store" env:"RATE_LIMIT_STORE" usage:"where the buckets are kept: memory or postgres"`
+}
+
+// Metrics the Prometheus metrics.
+type Metrics struct {
+	Disabled          bool  `yaml:"disabled" env:"METRICS_DISABLED" usage:"don't expose /metrics"`
+	LowStockThreshold int64 `yaml:"low_stock_threshold" env:"LOW_STOCK_THRESHOLD" usage:"the units at or below which an item is low on stock"`
+}
+
+// Tracing the OpenTelemetry tracing.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" usage:"otlp, stdout or none"`
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" usage:"the OTLP/HTTP collector the otlp exporter sends to"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" usage:"the ratio of the traces started by the servers to sample"`
+}
+
+// Publisher the message broker the outbox events are relayed to.
+type Publisher struct {
+	Kind              string   `yaml:"kind" env:"EVENT_PUBLISHER" usage:"nats, kafka or memory, none if unset"`
+	NatsURL           string   `yaml:"nats_url" env:"NATS_URL" usage:"the URL of the NATS server"`
+	NatsSubjectPrefix string   `yaml:"nats_subject_prefix" env:"NATS_SUBJECT_PREFIX" usage:"the prefix of the NATS subjects"`
+	KafkaBrokers      []string `yaml:"kafka_brokers" env:"KAFKA_BROKERS" usage:"the Kafka brokers, comma separated"`
+	KafkaTopic        string   `yaml:"kafka_topic" env:"KAFKA_TOPIC" usage:"the Kafka topic"`
+}
+
+// Shutdown the draining of the servers.
+type Shutdown struct {
+	Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long the calls in progress are waited for"`
+}
+
+// Features the toggles of the features being phased in or out.
+type Features struct {
+	LegacyRoutes bool `yaml:"legacy_routes" env:"LEGACY_ROUTES" usage:"serve the unversioned routes until their sunset"`
+}
+
+// Default the config used for the settings left unset.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Address: "localhost",
+			Port:    9989,
+		},
+		GRPC: GRPC{
+			Address: "localhost",
+			Port:    9999,
+		},
+		TLS: TLS{
+			ClientAuth:     "optional",
+			ReloadInterval: time.Minute,
+		},
+		DB: DB{
+			Host:            "localhost",
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "text",
+		},
+		Idempotency: Idempotency{
+			TTL: 24 * time.Hour,
+		},
+		RateLimit: RateLimit{
+			Store: "memory",
+		},
+		Metrics: Metrics{
+			LowStockThreshold: 10,
+		},
+		Tracing: Tracing{
+			SampleRatio: 1,
+		},
+		Shutdown: Shutdown{
+			Timeout: 30 * time.Second,
+		},
+		Features: Features{
+			LegacyRoutes: true,
+		},
+	}
+}
+
+// Addr the address the HTTP server binds to.
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+}
+
+// Addr the address the gRPC server binds to.
+func (g GRPC) Addr() string {
+	return net.JoinHostPort(g.Address, strconv.Itoa(g.Port))
+}
+
+// Target the address the REST gateway dials the gRPC server on, the loopback one if it binds to every interface.
+func (g GRPC) Target() string {
+	host := g.Address
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(g.Port))
+}
+
+// Enabled whether the servers are served over TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// Mutual whether the callers are authenticated by their client certificate.
+func (t TLS) Mutual() bool {
+	return t.ClientCAFile != ""
+}
+
+// URL the connection URL of the database, the DSN if set. The ssl settings are applied apart from it.
+func (d DB) URL() string {
+	if d.DSN != "" {
+		return d.DSN
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.User, d.Password),
+		Host:   net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		Path:   "/" + d.Name,
+	}
+
+	return u.String()
+}
+
+var (
+	// sslModes the modes of libpq the driver supports, it can't fall back to plaintext as allow and prefer do.
+	sslModes    = map[string]bool{"disable": true, "require": true, "verify-ca": true, "verify-full": true}
+	clientAuths = map[string]bool{"optional": true, "require": true}
+	logFormats  = map[string]bool{"text": true, "json": true}
+	limitStores = map[string]bool{"memory": true, "postgres": true}
+	exporters   = map[string]bool{"": true, "none": true, "otlp": true, "stdout": true}
+	publishers  = map[string]bool{"": true, "memory": true, "nats": true, "kafka": true}
+)
+
+// Validate reports every invalid setting at once, named by its path in the config file.
+func (c *Config) Validate() error {
+	var errs []error
+
+	invalid := func(path string, format string, args ...interface{}) {
+		errs = append(errs, errors.New(fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...))))
+	}
+
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		invalid("server.port", "%d is not a port", c.Server.Port)
+	}
+
+	if c.GRPC.Port < 0 || c.GRPC.Port > 65535 {
+		invalid("grpc.port", "%d is not a port", c.GRPC.Port)
+	}
+
+	if c.TLS.Enabled() {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			invalid("tls", "cert_file and key_file go together")
+		}
+
+		if _, err := os.Stat(c.TLS.CertFile); c.TLS.CertFile != "" && err != nil {
+			invalid("tls.cert_file", "%s", err)
+		}
+
+		if _, err := os.Stat(c.TLS.KeyFile); c.TLS.KeyFile != "" && err != nil {
+			invalid("tls.key_file", "%s", err)
+		}
+
+		if c.TLS.ReloadInterval <= 0 {
+			invalid("tls.reload_interval", "must be positive")
+		}
+	}
+
+	if c.TLS.Mutual() {
+		if !c.TLS.Enabled() {
+			invalid("tls.client_ca_file", "requires the cert_file and key_file of the servers")
+		}
+
+		if _, err := os.Stat(c.TLS.ClientCAFile); err != nil {
+			invalid("tls.client_ca_file", "%s", err)
+		}
+
+		if !clientAuths[c.TLS.ClientAuth] {
+			invalid("tls.client_auth", "unknown mode '%s'", c.TLS.ClientAuth)
+		}
+	}
+
+	if c.DB.DSN != "" {
+		if _, err := url.Parse(c.DB.DSN); err != nil {
+			invalid("db.dsn", "malformed URL")
+		}
+	} else {
+		if c.DB.Host == "" || c.DB.User == "" || c.DB.Name == "" {
+			invalid("db", "host, user and name are required without a dsn")
+		}
+
+		if c.DB.Port <= 0 || c.DB.Port > 65535 {
+			invalid("db.port", "%d is not a port", c.DB.Port)
+		}
+
+		if !sslModes[c.DB.SSLMode] {
+			invalid("db.ssl_mode", "unknown mode '%s'", c.DB.SSLMode)
+		}
+
+		if (c.DB.SSLCert == "") != (c.DB.SSLKey == "") {
+			invalid("db", "ssl_cert and ssl_key go together")
+		}
+
+		files := [][2]string{{"db.ssl_root_cert", c.DB.SSLRootCert}, {"db.ssl_cert", c.DB.SSLCert}, {"db.ssl_key", c.DB.SSLKey}}
+
+		for _, file := range files {
+			if _, err := os.Stat(file[1]); file[1] != "" && err != nil {
+				invalid(file[0], "%s", err)
+			}
+		}
+	}
+
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 || c.DB.ConnMaxLifetime < 0 || c.DB.ConnMaxIdleTime < 0 {
+		invalid("db", "the pool settings can't be negative")
+	}
+
+	if c.DB.Debug < 0 || c.DB.Debug > 2 {
+		invalid("db.debug", "%d is not 0, 1 or 2", c.DB.Debug)
+	}
+
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		invalid("log.level", "unknown level '%s'", c.Log.Level)
+	}
+
+	if !logFormats[c.Log.Format] {
+		invalid("log.format", "unknown format '%s'", c.Log.Format)
+	}
+
+	if c.Idempotency.TTL <= 0 {
+		invalid("idempotency.ttl", "must be positive")
+	}
+
+	if !limitStores[c.RateLimit.Store] {
+		invalid("rate_limit.store", "unknown store '%s'", c.RateLimit.Store)
+	}
+
+	if c.Metrics.LowStockThreshold < 0 {
+		invalid("metrics.low_stock_threshold", "can't be negative")
+	}
+
+	if !exporters[c.Tracing.Exporter] {
+		invalid("tracing.exporter", "unknown exporter '%s'", c.Tracing.Exporter)
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "%g is not between 0 and 1", c.Tracing.SampleRatio)
+	}
+
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || u.Host == "" {
+			invalid("tracing.endpoint", "malformed URL")
+		}
+	}
+
+	if !publishers[c.Publisher.Kind] {
+		invalid("publisher.kind", "unknown publisher '%s'", c.Publisher.Kind)
+	}
+
+	if c.Publisher.Kind == "kafka" && (len(c.Publisher.KafkaBrokers) == 0 || c.Publisher.KafkaTopic == "") {
+		invalid("publisher", "kafka_brokers and kafka_topic are required by kafka")
+	}
+
+	if c.Shutdown.Timeout <= 0 {
+		invalid("shutdown.timeout", "must be positive")
+	}
+
+	return errors.Join(errs...)
+}
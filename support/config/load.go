@@ -0,0 +1,217 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// FileFlag the flag naming the config file, CONFIG_FILE if unset.
+const FileFlag = "config"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting a leaf of the Config, named by its path in the config file.
+type setting struct {
+	path  string
+	field reflect.StructField
+	value reflect.Value
+}
+
+// settings walks the leaves of the config, section by section.
+func settings(c *Config) []setting {
+	var all []setting
+
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			path := prefix + field.Tag.Get("yaml")
+
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), path+".")
+				continue
+			}
+
+			all = append(all, setting{path: path, field: field, value: v.Field(i)})
+		}
+	}
+
+	walk(reflect.ValueOf(c).Elem(), "")
+
+	return all
+}
+
+// set parses the raw value of a setting, the way the env params and the flags carry them.
+func (s setting) set(raw string) error {
+	var err error
+
+	switch {
+	case s.value.Type() == durationType:
+		var d time.Duration
+		if d, err = time.ParseDuration(raw); err == nil {
+			s.value.SetInt(int64(d))
+		}
+	case s.value.Kind() == reflect.String:
+		s.value.SetString(raw)
+	case s.value.Kind() == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(raw); err == nil {
+			s.value.SetBool(b)
+		}
+	case s.value.Kind() == reflect.Int || s.value.Kind() == reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(raw, 10, 64); err == nil {
+			s.value.SetInt(n)
+		}
+	case s.value.Kind() == reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(raw, 64); err == nil {
+			s.value.SetFloat(f)
+		}
+	case s.value.Kind() == reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return errors.New(fmt.Sprintf("unsupported setting %s", s.path))
+	}
+
+	return err
+}
+
+// kind the type of a setting, as shown by the flag usage.
+func (s setting) kind() string {
+	switch {
+	case s.value.Type() == durationType:
+		return "duration"
+	case s.value.Kind() == reflect.Slice:
+		return "strings"
+	default:
+		return s.value.Kind().String()
+	}
+}
+
+// flagValue keeps the raw value of a flag, parsed once the file and the env params are applied.
+type flagValue struct {
+	raw  string
+	kind string
+}
+
+func (f *flagValue) String() string {
+	return f.raw
+}
+
+func (f *flagValue) Set(raw string) error {
+	f.raw = raw
+	return nil
+}
+
+func (f *flagValue) Type() string {
+	return f.kind
+}
+
+// RegisterFlags adds the --config flag, and a flag per setting named after its path, e.g. --server.port.
+func RegisterFlags(flags *pflag.FlagSet) {
+	flags.String(FileFlag, "", "the YAML or TOML config file, CONFIG_FILE if unset")
+
+	for _, s := range settings(Default()) {
+		usage := s.field.Tag.Get("usage")
+		if env := s.field.Tag.Get("env"); env != "" {
+			usage = fmt.Sprintf("%s (%s)", usage, env)
+		}
+
+		f := flags.VarPF(&flagValue{kind: s.kind()}, s.path, "", usage)
+		if s.value.Kind() == reflect.Bool {
+			f.NoOptDefVal = "true"
+		}
+	}
+}
+
+// Load returns the Default config, overridden by the config file, then by the env params, then by the flags.
+// The env params left empty are ignored. The flags may be nil, or a set the RegisterFlags were added to.
+func Load(flags *pflag.FlagSet) (*Config, error) {
+	c := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if flags != nil && flags.Changed(FileFlag) {
+		path, _ = flags.GetString(FileFlag)
+	}
+
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	all := settings(c)
+
+	for _, s := range all {
+		env := s.field.Tag.Get("env")
+		if raw := os.Getenv(env); env != "" && raw != "" {
+			if err := s.set(raw); err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid %s env param: %s", env, err))
+			}
+		}
+	}
+
+	if flags != nil {
+		for _, s := range all {
+			if f := flags.Lookup(s.path); f != nil && f.Changed {
+				if err := s.set(f.Value.String()); err != nil {
+					return nil, errors.New(fmt.Sprintf("invalid --%s flag: %s", s.path, err))
+				}
+			}
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid config:\n%s", err))
+	}
+
+	return c, nil
+}
+
+// loadFile applies a YAML config file, or a TOML one by its .toml extension. Unknown settings are rejected.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(path) == ".toml" {
+		tree := map[string]interface{}{}
+		if err := toml.Unmarshal(data, &tree); err != nil {
+			return errors.New(fmt.Sprintf("malformed config file %s: %s", path, err))
+		}
+
+		// Decoded the way the YAML files are, for the durations and the unknown settings.
+		if data, err = yaml.Marshal(tree); err != nil {
+			return err
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return errors.New(fmt.Sprintf("malformed config file %s: %s", path, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces the secrets set, when the config is printed.
+const Redacted = "[REDACTED]"
+
+// Print writes the config as a YAML config file, the secrets redacted and the durations written out.
+func Print(w io.Writer, c *Config) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{}
+
+	for _, s := range settings(c) {
+		section, key, _ := strings.Cut(s.path, ".")
+
+		node, ok := sections[section]
+		if !ok {
+			node = &yaml.Node{Kind: yaml.MappingNode}
+			sections[section] = node
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: section}, node)
+		}
+
+		var value interface{} = s.value.Interface()
+
+		switch {
+		case s.field.Tag.Get("secret") == "true" && !s.value.IsZero():
+			value = Redacted
+		case s.value.Type() == durationType:
+			value = time.Duration(s.value.Int()).String()
+		}
+
+		v := &yaml.Node{}
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
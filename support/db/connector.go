@@ -1,8 +1,9 @@
 package db
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -11,76 +12,97 @@ import (
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/extra/bundebug"
+	"stocks-api/support/config"
 )
 
-const envParams string = "{\n  \"host\": \"DB_HOST\",\n  \"port\": \"DB_PORT\",\n  \"user\": \"DB_USER\",\n  \"pass\": \"DB_PASS\",\n  \"db_name\": \"DB_NAME\"\n}"
+// NewConnection - initialises a db connection, along with its pool.
+// The ssl settings apply unless a DSN is set, its sslmode and sslrootcert params applying instead.
+func NewConnection(cfg config.DB) (*bun.DB, error) {
+	opts := []pgdriver.Option{pgdriver.WithDSN(cfg.URL())}
 
-type dbConn struct {
-	Host   string `json:"host"`
-	Port   string `json:"port"`
-	User   string `json:"user"`
-	Pass   string `json:"pass"`
-	DbName string `json:"db_name"`
-}
-
-func unwrapEnv() (*dbConn, error) {
-	params := map[string]string{}
-	if err := json.Unmarshal([]byte(envParams), &params); err != nil {
-		return nil, err
-	}
-
-	for key, param := range params {
-		val, ok := os.LookupEnv(param)
-		if !ok {
-			return nil, errors.New(fmt.Sprintf("failed to load '%s' env param", param))
+	if cfg.DSN == "" {
+		tlsConfig, err := SSLConfig(cfg)
+		if err != nil {
+			return nil, err
 		}
 
-		params[key] = val
+		opts = append(opts, pgdriver.WithTLSConfig(tlsConfig))
 	}
 
-	m, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
+	conn := sql.OpenDB(pgdriver.NewConnector(opts...))
 
-	c := dbConn{}
+	conn.SetMaxOpenConns(cfg.MaxOpenConns)
+	conn.SetMaxIdleConns(cfg.MaxIdleConns)
+	conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	if err := json.Unmarshal(m, &c); err != nil {
-		return nil, err
+	db := bun.NewDB(conn, pgdialect.New())
+
+	if cfg.Debug > 0 {
+		db.AddQueryHook(bundebug.NewQueryHook(
+			bundebug.WithVerbose(cfg.Debug > 1),
+		))
 	}
 
-	return &c, nil
+	return db, nil
 }
 
-func generateDsn() (string, error) {
-	db, err := unwrapEnv()
-	if err != nil {
-		return "", err
+// SSLConfig the TLS config of the ssl mode, nil if it's disabled, the way libpq reads it:
+// "require" encrypts the connections, "verify-ca" verifies the certificate of the database against the root CAs,
+// and "verify-full" its host name too. With an ssl_root_cert, "require" verifies the certificate as "verify-ca" does.
+func SSLConfig(cfg config.DB) (*tls.Config, error) {
+	if cfg.SSLMode == "disable" {
+		return nil, nil
 	}
 
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		db.User,
-		db.Pass,
-		db.Host,
-		db.Port,
-		db.DbName,
-	), nil
-}
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.Host,
+	}
 
-// NewConnection - initialises a db connection.
-func NewConnection() (*bun.DB, error) {
-	dsn, err := generateDsn()
-	if err != nil {
-		return nil, err
+	if cfg.SSLRootCert != "" {
+		data, err := os.ReadFile(cfg.SSLRootCert)
+		if err != nil {
+			return nil, err
+		}
+
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New(fmt.Sprintf("no PEM certificate in %s", cfg.SSLRootCert))
+		}
 	}
 
-	conn := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
-	db := bun.NewDB(conn, pgdialect.New())
+	if cfg.SSLCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.SSLCert, cfg.SSLKey)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to load the DB client certificate: %s", err))
+		}
 
-	db.AddQueryHook(bundebug.NewQueryHook(
-		bundebug.WithVerbose(true),
-		bundebug.FromEnv("BUNDEBUG"),
-	))
+		c.Certificates = []tls.Certificate{cert}
+	}
 
-	return db, nil
+	switch {
+	case cfg.SSLMode == "verify-full":
+		// The chain and the host name are verified as usual.
+	case cfg.SSLMode == "verify-ca" || cfg.SSLRootCert != "":
+		// The chain is verified, not the host name, which tls.Config can't skip on its own.
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = func(state tls.ConnectionState) error {
+			intermediates := x509.NewCertPool()
+			for _, cert := range state.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         c.RootCAs,
+				Intermediates: intermediates,
+			})
+
+			return err
+		}
+	default:
+		c.InsecureSkipVerify = true
+	}
+
+	return c, nil
 }
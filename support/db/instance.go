@@ -1,6 +1,10 @@
 package db
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
 )
@@ -18,12 +22,16 @@ func NewInstance(db *bun.DB, logger *logrus.Logger) *Instance {
 	}
 }
 
-// Health - checks if a database connection is present.
-func (i *Instance) Health() bool {
-	if err := i.Base.Ping(); err != nil {
-		i.logger.Fatalf("database unreachable, err: %s", err.Error())
-		return false
+// Close - closes the connection pool, once the queries in progress are done.
+func (i *Instance) Close(_ context.Context) error {
+	return i.Base.Close()
+}
+
+// Health - checks if the database is reachable, leaving it to the caller to decide what an outage means.
+func (i *Instance) Health(ctx context.Context) error {
+	if err := i.Base.PingContext(ctx); err != nil {
+		return errors.New(fmt.Sprintf("database unreachable, err: %s", err.Error()))
 	}
 
-	return true
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/migrate"
+	sqlMigrations "stocks-api/migrations"
 )
 
 // Migrator handles everything migration related.
@@ -24,6 +25,16 @@ func NewMigrator(logger *logrus.Logger, db *Instance, migrator *migrate.Migrator
 	}
 }
 
+// FromInstance a migrator of the SQL migrations embedded in the binary.
+func FromInstance(logger *logrus.Logger, db *Instance) (*Migrator, error) {
+	migrations := migrate.NewMigrations(migrate.WithMigrationsDirectory("migrations"))
+	if err := migrations.Discover(sqlMigrations.MigrationFilesScan()); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return NewMigrator(logger, db, migrate.NewMigrator(db.Base, migrations)), nil
+}
+
 // Init initialises bun's migration tables.
 func (m *Migrator) Init(ctx context.Context) error {
 	return m.bunMigrator.Init(ctx)
@@ -58,6 +69,16 @@ func (m *Migrator) Migrate(ctx context.Context) error {
 	return nil
 }
 
+// Pending returns the number of migrations not applied yet.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	ms, err := m.bunMigrator.MigrationsWithStatus(ctx)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return len(ms.Unapplied()), nil
+}
+
 // Rollback rolls-back the last migration group.
 func (m *Migrator) Rollback(ctx context.Context) error {
 	group, err := m.bunMigrator.Rollback(ctx)
@@ -0,0 +1,330 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/repos"
+	"stocks-api/support/db"
+)
+
+const (
+	// notifyChannel the Postgres channel the stock event trigger notifies on.
+	notifyChannel = "stock_events"
+	// pollInterval events are also polled, in case a notification was lost while reconnecting.
+	pollInterval = 5 * time.Second
+	fetchLimit   = 500
+	// gapTimeout how long a missing seq is waited for. The seqs are assigned on insert but the events only show up on
+	// commit, so a missing seq is either still in flight or was rolled back, which can't be told apart.
+	gapTimeout = time.Minute
+	// subscriptionBuffer the number of events a subscriber may lag behind before being dropped.
+	subscriptionBuffer = 256
+)
+
+// ErrLagging is returned to subscribers that could not keep up with the event rate.
+var ErrLagging error = errs.New(errs.Aborted, "subscriber is lagging behind, resume from the last received sequence")
+
+// ErrClosed is returned to subscribers once the broker has stopped, as the server shuts down.
+var ErrClosed error = errs.New(errs.Aborted, "server is shutting down, resume from the last received sequence")
+
+// EventStore a contract to the Stock Event Repo.
+type EventStore interface {
+	EventsSince(ctx context.Context, seq int64, tenant string, stockIDs []uuid.UUID, limit int) ([]*entities.StockEvent, error)
+	LatestSeq(ctx context.Context) (int64, error)
+}
+
+type subscription struct {
+	events chan *entities.StockEvent
+}
+
+// Broker fans the stock events out to all in-process subscribers, driven by Postgres LISTEN/NOTIFY.
+// The events may commit out of seq order, so the seqs missing below the highest published one are waited for.
+type Broker struct {
+	logger *logrus.Logger
+	db     *db.Instance
+	store  EventStore
+	mu     sync.Mutex
+	subs   map[*subscription]struct{}
+	closed bool
+	// lastSeq every event up to it was published, or given up on.
+	lastSeq int64
+	// published the seqs above lastSeq that were published, and gaps the ones missing, since they were first missed.
+	published map[int64]struct{}
+	gaps      map[int64]time.Time
+}
+
+// NewBroker a constructor for the Broker, listening for the new events on the db, or only polling the store if nil.
+func NewBroker(l *logrus.Logger, store EventStore, db *db.Instance) *Broker {
+	return &Broker{
+		logger:    l,
+		db:        db,
+		store:     store,
+		subs:      map[*subscription]struct{}{},
+		published: map[int64]struct{}{},
+		gaps:      map[int64]time.Time{},
+	}
+}
+
+// FromDB the broker of the stock events of the db.
+func FromDB(l *logrus.Logger, db *db.Instance) *Broker {
+	return NewBroker(l, repos.NewStockEventRepo(l, db), db)
+}
+
+// Run listens for new events and publishes them, until the context is done. The subscribers are then let go.
+func (b *Broker) Run(ctx context.Context) error {
+	defer b.close()
+
+	lastSeq, err := b.store.LatestSeq(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.setLastSeq(lastSeq)
+
+	var notifications <-chan pgdriver.Notification
+
+	if b.db != nil {
+		ln := pgdriver.NewListener(b.db.Base)
+		defer ln.Close()
+
+		if err := ln.Listen(ctx, notifyChannel); err != nil {
+			return err
+		}
+
+		notifications = ln.Channel()
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifications:
+		case <-ticker.C:
+		}
+
+		b.Fetch(ctx)
+	}
+}
+
+// Watch passes the events of the given stock items (all if empty) to fn, until the context is done or fn fails.
+// Only the events of the tenant of the context are passed. When fromSeq is set, the events recorded after it are replayed first.
+func (b *Broker) Watch(ctx context.Context, fromSeq int64, stockIDs []uuid.UUID, fn func(event *entities.StockEvent) error) error {
+	sub, watermark := b.subscribe()
+	defer b.unsubscribe(sub)
+
+	tenant := entities.TenantFrom(ctx)
+	last := fromSeq
+	// replayed the replayed events the broker may publish again, those above its watermark as of the subscription.
+	replayed := map[int64]struct{}{}
+
+	for fromSeq > 0 {
+		events, err := b.store.EventsSince(ctx, last, tenant, stockIDs, fetchLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			if err := fn(ev); err != nil {
+				return err
+			}
+
+			last = ev.Seq
+			if ev.Seq > watermark {
+				replayed[ev.Seq] = struct{}{}
+			}
+		}
+
+		if len(events) < fetchLimit {
+			break
+		}
+	}
+
+	match := matcher(tenant, stockIDs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.events:
+			if !ok {
+				return b.dropped()
+			}
+
+			if _, ok := replayed[ev.Seq]; ok || !match(ev) {
+				continue
+			}
+
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Fetch publishes the events committed since the last fetch, those committed late below an earlier one included.
+// Run fetches on every notification, and every poll interval.
+func (b *Broker) Fetch(ctx context.Context) {
+	cursor := b.lastSeq
+
+	for {
+		events, err := b.store.EventsSince(ctx, cursor, "", nil, fetchLimit)
+		if err != nil {
+			b.logger.Errorf("failed to fetch stock events: %s", err)
+			break
+		}
+
+		for _, ev := range events {
+			b.miss(cursor, ev.Seq)
+
+			if _, ok := b.published[ev.Seq]; !ok {
+				b.publish(ev)
+				b.published[ev.Seq] = struct{}{}
+				delete(b.gaps, ev.Seq)
+			}
+
+			cursor = ev.Seq
+		}
+
+		if len(events) < fetchLimit {
+			break
+		}
+	}
+
+	b.advance()
+}
+
+// miss records the seqs missing between two consecutive events as gaps, unless they already are.
+func (b *Broker) miss(from int64, to int64) {
+	now := time.Now()
+
+	for seq := from + 1; seq < to; seq++ {
+		if _, ok := b.published[seq]; ok {
+			continue
+		}
+
+		if _, ok := b.gaps[seq]; !ok {
+			b.gaps[seq] = now
+		}
+	}
+}
+
+// advance moves lastSeq past the published seqs, and the gaps waited for long enough, so they're no longer fetched.
+func (b *Broker) advance() {
+	lastSeq := b.lastSeq
+
+	for {
+		next := lastSeq + 1
+
+		if _, ok := b.published[next]; ok {
+			delete(b.published, next)
+		} else if missed, ok := b.gaps[next]; ok && time.Since(missed) > gapTimeout {
+			delete(b.gaps, next)
+			b.logger.Debugf("stock event %d never committed, skipping it", next)
+		} else {
+			break
+		}
+
+		lastSeq = next
+	}
+
+	b.setLastSeq(lastSeq)
+}
+
+func (b *Broker) setLastSeq(seq int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastSeq = seq
+}
+
+// publish hands an event to every subscriber, dropping the ones whose buffer is full.
+func (b *Broker) publish(ev *entities.StockEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		select {
+		case sub.events <- ev:
+		default:
+			delete(b.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe registers a subscriber, along with the broker's lastSeq: the events it's passed are all above it.
+func (b *Broker) subscribe() (*subscription, int64) {
+	sub := &subscription{events: make(chan *entities.StockEvent, subscriptionBuffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.events)
+		return sub, b.lastSeq
+	}
+
+	b.subs[sub] = struct{}{}
+
+	return sub, b.lastSeq
+}
+
+func (b *Broker) unsubscribe(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+// close drops every subscriber for good, the later ones included.
+func (b *Broker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+// dropped the error of a subscriber whose events were cut off.
+func (b *Broker) dropped() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	return ErrLagging
+}
+
+func matcher(tenant string, stockIDs []uuid.UUID) func(ev *entities.StockEvent) bool {
+	if len(stockIDs) == 0 {
+		return func(ev *entities.StockEvent) bool { return ev.TenantID == tenant }
+	}
+
+	ids := make(map[uuid.UUID]struct{}, len(stockIDs))
+	for _, id := range stockIDs {
+		ids[id] = struct{}{}
+	}
+
+	return func(ev *entities.StockEvent) bool {
+		_, ok := ids[ev.StockID]
+		return ok && ev.TenantID == tenant
+	}
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	pb "stocks-api/genprotos"
+	"stocks-api/support/certs"
+	"stocks-api/support/tracing"
+)
+
+// NewGateway a constructor for the REST reverse proxy generated from stocks.proto, forwarding to the gRPC endpoint.
+// The endpoint is dialed over TLS with the given config, in plaintext if it's nil.
+func NewGateway(ctx context.Context, l *logrus.Logger, endpoint string, tlsConfig *tls.Config) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithErrorHandler(problemHandler(l)),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
+
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(unaryPropagator),
+		grpc.WithStreamInterceptor(streamPropagator),
+	}
+
+	if err := pb.RegisterStockServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
+
+// headerMatcher forwards the API key, the tenant, the idempotency key and the client certificate headers to the gRPC
+// server, along with the default ones.
+func headerMatcher(key string) (string, bool) {
+	switch http.CanonicalHeaderKey(key) {
+	case "X-Api-Key":
+		return "x-api-key", true
+	case "X-Tenant-Id":
+		return "x-tenant-id", true
+	case "Idempotency-Key":
+		return "idempotency-key", true
+	case certs.ForwardedHeader:
+		return certs.ForwardedMetadataKey, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaders the header metadata returned as plain HTTP headers, the other metadata is prefixed.
+var outgoingHeaders = map[string]string{
+	"idempotent-replayed": "Idempotent-Replayed",
+	"ratelimit-limit":     "RateLimit-Limit",
+	"ratelimit-remaining": "RateLimit-Remaining",
+	"ratelimit-reset":     "RateLimit-Reset",
+	"retry-after":         "Retry-After",
+}
+
+// outgoingHeaderMatcher names the HTTP headers of the header metadata.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if header, ok := outgoingHeaders[key]; ok {
+		return header, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// unaryPropagator continues the trace of the HTTP request in the gRPC call it's proxied to.
+func unaryPropagator(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(propagate(ctx), method, req, reply, cc, opts...)
+}
+
+// streamPropagator the streaming counterpart of the unaryPropagator.
+func streamPropagator(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(propagate(ctx), desc, cc, method, opts...)
+}
+
+// propagate writes the trace context to the outgoing metadata.
+func propagate(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+
+	tracing.Inject(ctx, tracing.MetadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// ServeOpenAPI serves the OpenAPI document of the gateway routes.
+func ServeOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pb.OpenAPI)
+}
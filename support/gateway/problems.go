@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// problem an RFC 7807 problem details body, the same the hand-written REST routes return.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []invalidParam `json:"invalid_params,omitempty"`
+}
+
+type invalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// problemType the type URI and HTTP status of a gRPC code.
+type problemType struct {
+	uri    string
+	status int
+}
+
+// problemTypes mirrors the status codes of the hand-written REST routes, the other codes use the gateway defaults.
+var problemTypes = map[codes.Code]problemType{
+	codes.Internal:          {"/problems/internal", http.StatusInternalServerError},
+	codes.NotFound:          {"/problems/not-found", http.StatusNotFound},
+	codes.AlreadyExists:     {"/problems/conflict", http.StatusConflict},
+	codes.InvalidArgument:   {"/problems/malformed-request", http.StatusBadRequest},
+	codes.Aborted:           {"/problems/aborted", http.StatusConflict},
+	codes.Unauthenticated:   {"/problems/unauthenticated", http.StatusUnauthorized},
+	codes.PermissionDenied:  {"/problems/forbidden", http.StatusForbidden},
+	codes.ResourceExhausted: {"/problems/rate-limited", http.StatusTooManyRequests},
+}
+
+// validationProblem the type of the invalid arguments with field violations, which failed validation rather than
+// being malformed.
+var validationProblem = problemType{"/problems/validation", http.StatusUnprocessableEntity}
+
+// problemHandler writes the gRPC errors as problem+json, with the field violations of their details.
+func problemHandler(l *logrus.Logger) runtime.ErrorHandlerFunc {
+	return func(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		st := status.Convert(err)
+
+		pt, ok := problemTypes[st.Code()]
+		if !ok {
+			pt = problemType{"about:blank", runtime.HTTPStatusFromCode(st.Code())}
+		}
+
+		if st.Code() == codes.InvalidArgument && hasFieldViolations(st) {
+			pt = validationProblem
+		}
+
+		if pt.status >= http.StatusInternalServerError {
+			l.Errorf("gateway call failed: %s", err)
+		}
+
+		p := problem{
+			Type:     pt.uri,
+			Title:    http.StatusText(pt.status),
+			Status:   pt.status,
+			Detail:   st.Message(),
+			Instance: r.URL.Path,
+		}
+
+		for _, detail := range st.Details() {
+			if d, ok := detail.(*errdetails.BadRequest); ok {
+				for _, v := range d.GetFieldViolations() {
+					p.InvalidParams = append(p.InvalidParams, invalidParam{Name: v.GetField(), Reason: v.GetDescription()})
+				}
+			}
+		}
+
+		// The rate limit headers of the refused calls.
+		if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+			for key, header := range outgoingHeaders {
+				if values := md.HeaderMD.Get(key); len(values) > 0 {
+					w.Header().Set(header, values[0])
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(pt.status)
+		json.NewEncoder(w).Encode(p)
+	}
+}
+
+// hasFieldViolations reports whether the status carries bad request field violations.
+func hasFieldViolations(st *status.Status) bool {
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok && len(d.GetFieldViolations()) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
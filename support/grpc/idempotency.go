@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"stocks-api/support/idempotency"
+)
+
+// readOnlyMethods the calls without side effects, which need no idempotency key.
+var readOnlyMethods = map[string]bool{
+	"/stocks.StockService/GetStock":   true,
+	"/stocks.StockService/ListStocks": true,
+}
+
+// UnaryIdempotencyInterceptor serves the calls carrying an idempotency-key metadata once per key and tenant,
+// answering their retries with the stored response. Failed calls release their key, so that they can be retried.
+// A nil guard lets every call through.
+func UnaryIdempotencyInterceptor(l *logrus.Logger, g *idempotency.Guard) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		key := first(md, idempotency.MetadataKey)
+		msg, ok := req.(proto.Message)
+		if g == nil || key == "" || !ok || readOnlyMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, statusError(l, err, "Failed to check the idempotency key")
+		}
+
+		record, err := g.Begin(ctx, key, idempotency.Fingerprint([]byte(info.FullMethod), payload))
+		if err != nil {
+			return nil, statusError(l, err, "Failed to check the idempotency key")
+		}
+
+		if record != nil {
+			return replay(ctx, l, record.Response)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			g.Release(ctx, key)
+			return nil, err
+		}
+
+		response, err := encode(resp)
+		if err != nil {
+			l.Errorf("failed to encode the response of idempotency key %s: %s", key, err)
+			g.Release(ctx, key)
+
+			return resp, nil
+		}
+
+		g.Complete(ctx, key, 0, nil, response)
+
+		return resp, nil
+	}
+}
+
+// encode marshals a response along with its type, to decode it on replay.
+func encode(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", resp)
+	}
+
+	stored, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(stored)
+}
+
+// replay decodes the stored response of a call, flagging it in the header metadata.
+func replay(ctx context.Context, l *logrus.Logger, response []byte) (interface{}, error) {
+	stored := &anypb.Any{}
+	if err := proto.Unmarshal(response, stored); err != nil {
+		return nil, statusError(l, err, "Failed to check the idempotency key")
+	}
+
+	resp, err := stored.UnmarshalNew()
+	if err != nil {
+		return nil, statusError(l, err, "Failed to check the idempotency key")
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(idempotency.ReplayedMetadataKey, "true"))
+
+	return resp, nil
+}
@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/auth"
+	"stocks-api/support/certs"
+)
+
+// publicService the service served to anonymous clients, the health probes.
+const publicService = "/grpc.health.v1.Health/"
+
+// UnaryAuthInterceptor rejects the calls without valid credentials, and adds the principal and the tenant to the context of the others.
+// A nil authenticator lets every call through anonymously.
+// The client certificates are verified by the reloader, nil if TLS is disabled.
+func UnaryAuthInterceptor(l *logrus.Logger, a *auth.Authenticator, r *certs.Reloader) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, publicService) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, l, a, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor the streaming counterpart of the UnaryAuthInterceptor.
+func StreamAuthInterceptor(l *logrus.Logger, a *auth.Authenticator, r *certs.Reloader) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, publicService) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), l, a, r)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// authenticate reads the credentials of the authorization or the x-api-key metadata, or else the client certificate,
+// and the x-tenant-id metadata. The calls of the REST gateway carry the client certificate it verified.
+func authenticate(ctx context.Context, l *logrus.Logger, a *auth.Authenticator, r *certs.Reloader) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var principal *entities.Principal
+
+	if a != nil {
+		token, err := auth.Credentials(first(md, "authorization"), first(md, "x-api-key"))
+
+		var cert *x509.Certificate
+		if err == nil && token == "" {
+			cert, err = clientCertificate(ctx, md, r)
+		}
+
+		if err == nil {
+			if cert != nil {
+				principal, err = a.AuthenticateCertificate(cert)
+			} else {
+				principal, err = a.Authenticate(ctx, token)
+			}
+		}
+
+		if err != nil {
+			return nil, statusError(l, err, "Failed to authenticate")
+		}
+	}
+
+	tenant, err := auth.Tenant(principal, first(md, "x-tenant-id"))
+	if err != nil {
+		return nil, statusError(l, err, "Failed to authenticate")
+	}
+
+	ctx = entities.WithTenant(ctx, tenant)
+	if principal != nil {
+		ctx = entities.WithPrincipal(ctx, principal)
+	}
+
+	return ctx, nil
+}
+
+// statusError converts the failures of the interceptors to a gRPC status, internal ones to the fallback message.
+func statusError(l *logrus.Logger, err error, fallback string) error {
+	switch errs.KindOf(err) {
+	case errs.Unauthenticated:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errs.PermissionDenied:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errs.InvalidArgument:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errs.Aborted:
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	l.Error(err)
+
+	return status.Error(codes.Internal, fallback)
+}
+
+// clientCertificate the client certificate of a call, or the one the REST gateway forwarded. Nil over plaintext.
+func clientCertificate(ctx context.Context, md metadata.MD, r *certs.Reloader) (*x509.Certificate, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, nil
+	}
+
+	if cert := r.Client(&info.State); cert != nil {
+		return cert, nil
+	}
+
+	return r.Forwarded(&info.State, first(md, certs.ForwardedMetadataKey))
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// contextStream a server stream carrying a context of its own, e.g. with the principal and the tenant.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (p *contextStream) Context() context.Context {
+	return p.ctx
+}
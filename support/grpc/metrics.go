@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"stocks-api/support/metrics"
+)
+
+// UnaryMetricsInterceptor records every call served, refused ones included, by its full method and status code.
+// A nil metrics records nothing.
+func UnaryMetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if m == nil {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		m.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+		return res, err
+	}
+}
+
+// StreamMetricsInterceptor the streaming counterpart of the UnaryMetricsInterceptor, timing the whole stream.
+func StreamMetricsInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if m == nil {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		err := handler(srv, ss)
+
+		m.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+		return err
+	}
+}
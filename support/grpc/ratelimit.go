@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"stocks-api/module/entities"
+	"stocks-api/support/ratelimit"
+)
+
+// UnaryRateLimitInterceptor refuses the calls of the clients over the limit of their method, with ResourceExhausted.
+// Every limited call carries the ratelimit-* header metadata of its bucket. A nil limiter lets every call through.
+func UnaryRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, err := limit(ctx, limiter, info.FullMethod)
+		if md != nil {
+			grpc.SetHeader(ctx, md)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor the streaming counterpart of the UnaryRateLimitInterceptor.
+func StreamRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, err := limit(ss.Context(), limiter, info.FullMethod)
+		if md != nil {
+			ss.SetHeader(md)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// limit takes a token for the call, returning its header metadata and the error of a refused call.
+func limit(ctx context.Context, limiter *ratelimit.Limiter, method string) (metadata.MD, error) {
+	if limiter == nil || strings.HasPrefix(method, publicService) {
+		return nil, nil
+	}
+
+	decision := limiter.Allow(ctx, method, ratelimit.Client(entities.PrincipalFrom(ctx), peerIP(ctx)))
+	if decision == nil {
+		return nil, nil
+	}
+
+	md := metadata.MD{}
+	for header, value := range decision.Headers() {
+		md.Set(header, value)
+	}
+
+	if decision.Allowed {
+		return md, nil
+	}
+
+	st := status.New(codes.ResourceExhausted, decision.Err().Error())
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(decision.RetryAfter)}); err == nil {
+		st = detailed
+	}
+
+	return md, st.Err()
+}
+
+// peerIP the IP of the peer of a call. The calls of the REST gateway, over loopback, are credited to the
+// client the gateway appended last to their x-forwarded-for metadata, the entries before it being the client's own.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			forwarded := values[len(values)-1]
+			if i := strings.LastIndex(forwarded, ","); i >= 0 {
+				forwarded = forwarded[i+1:]
+			}
+
+			if client := strings.TrimSpace(forwarded); client != "" {
+				return client
+			}
+		}
+	}
+
+	return host
+}
@@ -1,58 +1,98 @@
 package grpc
 
 import (
-	"flag"
+	"context"
 	"fmt"
-	"log"
 	"net"
-	"sync"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	pb "stocks-api/genprotos"
 	"stocks-api/module/handlers"
+	"stocks-api/support/certs"
+	"stocks-api/support/config"
+	"stocks-api/support/health"
 )
 
 // Serve is the gRPC serve wrapper.
 type Serve struct {
-	port    int64
-	logger  *logrus.Logger
-	opts    *[]grpc.ServerOption
-	handler *handlers.StockHandler
-	wg      *sync.WaitGroup
+	config   *config.Config
+	logger   *logrus.Logger
+	opts     *[]grpc.ServerOption
+	handler  *handlers.StockHandler
+	checker  *health.Checker
+	certs    *certs.Reloader
+	server   *grpc.Server
+	listener net.Listener
 }
 
 // NewServe is a wrapper constructor.
 func NewServe(
-	p int64,
+	cfg *config.Config,
 	l *logrus.Logger,
 	opts *[]grpc.ServerOption,
 	handler *handlers.StockHandler,
-	wg *sync.WaitGroup,
+	checker *health.Checker,
+	certs *certs.Reloader,
 ) *Serve {
 	return &Serve{
-		port:    p,
+		config:  cfg,
 		logger:  l,
 		opts:    opts,
 		handler: handler,
-		wg:      wg,
+		checker: checker,
+		certs:   certs,
 	}
 }
 
-// Serve inits the gRPC server.
-func (s *Serve) Serve() {
-	flag.Parse()
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", s.port))
+// Listen registers the services and binds the server to its address, over TLS if a certificate is configured.
+func (s *Serve) Listen() error {
+	opts := append([]grpc.ServerOption{}, *s.opts...)
+	if s.certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.certs.Server())))
+	}
+
+	lis, err := net.Listen("tcp", s.config.GRPC.Addr())
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	grpcServer := grpc.NewServer(*s.opts...)
+	s.listener = lis
+	s.server = grpc.NewServer(opts...)
+
+	pb.RegisterStockServiceServer(s.server, s.handler)
 
-	pb.RegisterStockServiceServer(grpcServer, s.handler)
+	if s.checker != nil {
+		healthpb.RegisterHealthServer(s.server, s.checker.GRPC())
+	}
+
+	return nil
+}
 
-	s.logger.Info(fmt.Sprintf("Serving gRPC on: %d", s.port))
-	grpcServer.Serve(lis)
+// Serve accepts new calls until the server shuts down.
+func (s *Serve) Serve() error {
+	s.logger.Info(fmt.Sprintf("Serving gRPC on: %s", s.listener.Addr()))
 
-	defer s.wg.Done()
+	return s.server.Serve(s.listener)
+}
+
+// Shutdown stops accepting new calls and waits for the ones in progress, cancelling them all once the context is done.
+// The WatchStocks streams end as the stock event broker stops.
+func (s *Serve) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"stocks-api/support/tracing"
+)
+
+// serverFault the status codes of the calls failed on the server's side, the others are the caller's.
+var serverFault = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.DeadlineExceeded: true,
+	codes.Unimplemented:    true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+}
+
+// UnaryTracingInterceptor serves every call within a span named by its method, continuing the trace of its caller if any.
+// The trace ids are added to the log entry of the call.
+func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startCall(ctx, info.FullMethod)
+		defer span.End()
+
+		res, err := handler(ctx, req)
+		endCall(span, err)
+
+		return res, err
+	}
+}
+
+// StreamTracingInterceptor the streaming counterpart of the UnaryTracingInterceptor, the span lasting the whole stream.
+func StreamTracingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startCall(ss.Context(), info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+		endCall(span, err)
+
+		return err
+	}
+}
+
+// startCall starts the span of a call, e.g. "stocks.StockService/GetStock".
+func startCall(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = tracing.Extract(ctx, tracing.MetadataCarrier(md))
+
+	name := strings.TrimPrefix(method, "/")
+	service, rpc, _ := strings.Cut(name, "/")
+
+	ctx, span := tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(service),
+			semconv.RPCMethod(rpc),
+		),
+	)
+
+	ctxlogrus.AddFields(ctx, tracing.Fields(ctx))
+
+	return ctx, span
+}
+
+// endCall records the status code of a call, the ones on the server's side failing its span.
+func endCall(span trace.Span, err error) {
+	st := status.Convert(err)
+
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(st.Code())))
+
+	if serverFault[st.Code()] {
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"stocks-api/support/db"
+)
+
+const (
+	checkTimeout  = 2 * time.Second
+	checkInterval = 10 * time.Second
+	// service the gRPC service reported along with the overall status.
+	service = "stocks.StockService"
+)
+
+// Check a named dependency the servers need to serve the calls.
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// Result the outcome of a check, "ok" or "failed", the reason it failed being logged rather than served.
+type Result struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker reports the liveness and the readiness of the servers, over HTTP and the standard gRPC health service.
+// The servers are ready once started, until they shut down, while every check passes.
+type Checker struct {
+	logger *logrus.Logger
+	checks []Check
+	ready  atomic.Bool
+	server *grpchealth.Server
+}
+
+// NewChecker a constructor for the Checker, not ready until SetReady is called.
+func NewChecker(l *logrus.Logger, checks ...Check) *Checker {
+	c := &Checker{
+		logger: l,
+		checks: checks,
+		server: grpchealth.NewServer(),
+	}
+
+	c.serving(false)
+
+	return c
+}
+
+// FromDB returns the checker of the database: it must be reachable and its migrations up-to-date.
+func FromDB(l *logrus.Logger, instance *db.Instance) (*Checker, error) {
+	migrator, err := db.FromInstance(l, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewChecker(l,
+		Check{Name: "db", Fn: instance.Health},
+		Check{Name: "migrations", Fn: func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+
+			if pending > 0 {
+				return errors.New(fmt.Sprintf("%d pending migrations", pending))
+			}
+
+			return nil
+		}},
+	), nil
+}
+
+// SetReady flips the readiness of the servers, at the end of their startup.
+func (c *Checker) SetReady(ready bool) {
+	c.ready.Store(ready)
+	c.serving(ready && c.Ready(context.Background()).Status == "ok")
+}
+
+// Shutdown reports the servers as not ready for good, so that no new calls are routed to them.
+func (c *Checker) Shutdown() {
+	c.ready.Store(false)
+	c.server.Shutdown()
+}
+
+// Ready runs the checks, the servers are ready if they all pass.
+func (c *Checker) Ready(ctx context.Context) Result {
+	if !c.ready.Load() {
+		return Result{Status: "unavailable"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result := Result{Status: "ok", Checks: map[string]string{}}
+
+	for _, check := range c.checks {
+		if err := check.Fn(ctx); err != nil {
+			c.logger.Warningf("health check %s failed: %s", check.Name, err)
+
+			result.Status = "unavailable"
+			result.Checks[check.Name] = "failed"
+			continue
+		}
+
+		result.Checks[check.Name] = "ok"
+	}
+
+	return result
+}
+
+// GRPC the standard grpc.health.v1.Health service.
+func (c *Checker) GRPC() healthpb.HealthServer {
+	return c.server
+}
+
+// Run refreshes the status of the gRPC health service until the context is done.
+func (c *Checker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if c.ready.Load() {
+				c.serving(c.Ready(ctx).Status == "ok")
+			}
+		}
+	}
+}
+
+// ServeLive answers the liveness probes, the process is alive as long as it answers.
+func (c *Checker) ServeLive(w http.ResponseWriter, _ *http.Request) {
+	writeResult(w, http.StatusOK, Result{Status: "ok"})
+}
+
+// ServeReady answers the readiness probes, with a 503 if the servers aren't ready.
+func (c *Checker) ServeReady(w http.ResponseWriter, r *http.Request) {
+	result := c.Ready(r.Context())
+	if result.Status != "ok" {
+		writeResult(w, http.StatusServiceUnavailable, result)
+		return
+	}
+
+	writeResult(w, http.StatusOK, result)
+}
+
+func (c *Checker) serving(ok bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ok {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	c.server.SetServingStatus("", status)
+	c.server.SetServingStatus(service, status)
+}
+
+func writeResult(w http.ResponseWriter, status int, result Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"stocks-api/module/controllers"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/auth"
+	"stocks-api/support/certs"
+)
+
+// TenantHeader names the tenant of a call, for the anonymous ones, or repeats the tenant of the principal.
+const TenantHeader = "X-Tenant-ID"
+
+// publicPaths the routes served to anonymous clients.
+var publicPaths = map[string]bool{
+	"/openapi.json": true,
+	"/healthz":      true,
+	"/readyz":       true,
+	"/metrics":      true,
+}
+
+// gatewayPrefix the routes proxied to the gRPC server, which authenticates them on its own.
+const gatewayPrefix = "/v1/"
+
+// authMiddleware rejects the requests without valid credentials, and adds the principal and the tenant to the context of the others.
+// The credentials are a bearer JWT or API key, or an API key in the X-API-Key header, or else the client certificate.
+func (s *Serve) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, gatewayPrefix) {
+			// Only the client certificates verified here are forwarded, never the ones of the request headers.
+			r.Header.Del(certs.ForwardedHeader)
+			if cert := s.certs.Client(r.TLS); cert != nil {
+				r.Header.Set(certs.ForwardedHeader, certs.Forward(cert))
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var principal *entities.Principal
+
+		if s.authenticator != nil {
+			var err error
+
+			if principal, err = s.authenticate(r); err != nil {
+				if errs.KindOf(err) == errs.Unauthenticated {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="stocks-api"`)
+				}
+
+				controllers.WriteProblem(s.logger, w, r, err)
+				return
+			}
+		}
+
+		tenant, err := auth.Tenant(principal, r.Header.Get(TenantHeader))
+		if err != nil {
+			controllers.WriteProblem(s.logger, w, r, err)
+			return
+		}
+
+		ctx := entities.WithTenant(r.Context(), tenant)
+		if principal != nil {
+			ctx = entities.WithPrincipal(ctx, principal)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (s *Serve) authenticate(r *http.Request) (*entities.Principal, error) {
+	token, err := auth.Credentials(r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+	if err != nil {
+		return nil, err
+	}
+
+	if cert := s.certs.Client(r.TLS); token == "" && cert != nil {
+		return s.authenticator.AuthenticateCertificate(cert)
+	}
+
+	return s.authenticator.Authenticate(r.Context(), token)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	// legacyDeprecatedAt when the unversioned routes were superseded by /api/v1.
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	// legacySunsetAt when the unversioned routes are due to be removed.
+	legacySunsetAt = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecated marks the responses of a legacy route as deprecated (RFC 9745) and links to its successor.
+// The {id} placeholder of the successor is filled in from the route vars.
+func deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		link := strings.ReplaceAll(successor, "{id}", mux.Vars(r)["id"])
+
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+		w.Header().Set("Sunset", legacySunsetAt.Format(http.TimeFormat))
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", link))
+
+		next(w, r)
+	}
+}
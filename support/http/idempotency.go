@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"stocks-api/module/controllers"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/idempotency"
+)
+
+// safeMethods the methods without side effects, which need no idempotency key.
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
+// idempotencyMiddleware serves the unsafe requests carrying an Idempotency-Key header once per key and tenant,
+// answering their retries with the stored response. Failed requests release their key, so that they can be retried.
+func (s *Serve) idempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotency.Header)
+		if s.idempotency == nil || key == "" || safeMethods[r.Method] || strings.HasPrefix(r.URL.Path, gatewayPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			controllers.WriteProblem(s.logger, w, r, errs.Wrap(errs.InvalidArgument, err))
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := idempotency.Fingerprint([]byte(r.Method), []byte(r.URL.RequestURI()), body)
+
+		record, err := s.idempotency.Begin(r.Context(), key, fingerprint)
+		if err != nil {
+			controllers.WriteProblem(s.logger, w, r, err)
+			return
+		}
+
+		if record != nil {
+			replay(w, record)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusBadRequest {
+			s.idempotency.Release(r.Context(), key)
+			return
+		}
+
+		s.idempotency.Complete(r.Context(), key, rec.status, rec.Header().Clone(), rec.body.Bytes())
+	})
+}
+
+// replay writes the stored response of a request again.
+func replay(w http.ResponseWriter, record *entities.IdempotencyRecord) {
+	for name, values := range record.Headers {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set(idempotency.ReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Response)
+}
+
+// responseRecorder copies the status and the body written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
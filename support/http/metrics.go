@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// metricsMiddleware records every request served, refused ones included, by its method and path template.
+func (s *Serve) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.metrics == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		s.metrics.ObserveHTTP(r.Method, templateOf(r), sw.status, time.Since(start))
+	})
+}
+
+// templateOf the path template of the route of a request, so that the paths of every stock share their metrics.
+func templateOf(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return "unmatched"
+}
+
+// statusWriter remembers the status code of a response, passing the rest through to the streams and sockets.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+// WriteHeader records the status code of the response.
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wrote {
+		w.status, w.wrote = status, true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implicitly writes a 200 status code, if none was.
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered data of the event streams.
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over to the websockets, which switch the protocol.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer doesn't support hijacking")
+	}
+
+	w.status, w.wrote = http.StatusSwitchingProtocols, true
+
+	return hijacker.Hijack()
+}
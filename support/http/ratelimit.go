@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"stocks-api/module/controllers"
+	"stocks-api/module/entities"
+	"stocks-api/support/ratelimit"
+)
+
+// unlimitedPaths the health probes and the metrics scrapes, never limited.
+var unlimitedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// rateLimitMiddleware refuses the requests of the clients over the limit of their route, with a 429.
+// Every limited response carries the RateLimit-* headers of its bucket.
+func (s *Serve) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter == nil || unlimitedPaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, gatewayPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := ratelimit.Client(entities.PrincipalFrom(r.Context()), clientIP(r))
+
+		decision := s.limiter.Allow(r.Context(), routeOf(r), client)
+		if decision == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		for header, value := range decision.Headers() {
+			w.Header().Set(header, value)
+		}
+
+		if !decision.Allowed {
+			controllers.WriteProblem(s.logger, w, r, decision.Err())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeOf names the route of a request by its method and path template, e.g. "GET /api/v1/stocks/{id}".
+func routeOf(r *http.Request) string {
+	path := r.URL.Path
+
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			path = template
+		}
+	}
+
+	return r.Method + " " + path
+}
+
+// clientIP the IP of the peer of a request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -1,60 +1,199 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
-	"os"
-	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 	"stocks-api/module/controllers"
+	"stocks-api/support/auth"
+	"stocks-api/support/certs"
+	"stocks-api/support/config"
+	"stocks-api/support/gateway"
+	"stocks-api/support/health"
+	"stocks-api/support/idempotency"
+	"stocks-api/support/metrics"
+	"stocks-api/support/ratelimit"
 )
 
 // Serve a server instance.
 type Serve struct {
-	Server          *mux.Router
-	logger          *logrus.Logger
-	stockController *controllers.StockController
-	wg              *sync.WaitGroup
+	Server            *mux.Router
+	logger            *logrus.Logger
+	stockController   *controllers.StockController
+	feedController    *controllers.FeedController
+	webhookController *controllers.WebhookController
+	graphqlController *controllers.GraphQLController
+	gateway           http.Handler
+	authenticator     *auth.Authenticator
+	idempotency       *idempotency.Guard
+	limiter           *ratelimit.Limiter
+	checker           *health.Checker
+	metrics           *metrics.Metrics
+	certs             *certs.Reloader
+	config            *config.Config
+	http              *http.Server
+	listener          net.Listener
+}
+
+// Options the controllers of the server, all required, and the dependencies of its middlewares and extra routes,
+// which are turned off when left nil.
+type Options struct {
+	Stocks        *controllers.StockController
+	Feed          *controllers.FeedController
+	Webhooks      *controllers.WebhookController
+	GraphQL       *controllers.GraphQLController
+	Gateway       http.Handler
+	Authenticator *auth.Authenticator
+	Idempotency   *idempotency.Guard
+	Limiter       *ratelimit.Limiter
+	Checker       *health.Checker
+	Metrics       *metrics.Metrics
+	Certs         *certs.Reloader
 }
 
 // NewServe a constructor for Serve.
-func NewServe(stockController *controllers.StockController, l *logrus.Logger, wg *sync.WaitGroup) *Serve {
+func NewServe(cfg *config.Config, l *logrus.Logger, opts Options) *Serve {
 	return &Serve{
-		Server:          mux.NewRouter(),
-		logger:          l,
-		stockController: stockController,
-		wg:              wg,
+		Server:            mux.NewRouter(),
+		logger:            l,
+		stockController:   opts.Stocks,
+		feedController:    opts.Feed,
+		webhookController: opts.Webhooks,
+		graphqlController: opts.GraphQL,
+		gateway:           opts.Gateway,
+		authenticator:     opts.Authenticator,
+		idempotency:       opts.Idempotency,
+		limiter:           opts.Limiter,
+		checker:           opts.Checker,
+		metrics:           opts.Metrics,
+		certs:             opts.Certs,
+		config:            cfg,
 	}
 }
 
 // RegisterHandlers registers the routes available for our API.
 func (s *Serve) RegisterHandlers() {
-	s.Server.HandleFunc("/", s.stockController.GetAll).Methods("GET")
-	s.Server.HandleFunc("/", s.stockController.InsertOne).Methods("POST")
-	s.Server.HandleFunc("/{id}", s.stockController.GetOne).Methods("GET")
-	s.Server.HandleFunc("/{id}", s.stockController.UpdateOne).Methods("POST")
-	s.Server.HandleFunc("/{id}", s.stockController.DeleteOne).Methods("PUT")
-}
+	s.Server.Use(s.tracingMiddleware)
+	s.Server.Use(s.metricsMiddleware)
+	s.Server.Use(s.authMiddleware)
+	s.Server.Use(s.rateLimitMiddleware)
+	s.Server.Use(s.idempotencyMiddleware)
+
+	v1 := s.Server.PathPrefix("/api/v1").Subrouter()
+
+	v1.HandleFunc("/stocks", s.stockController.List).Methods("GET")
+	v1.HandleFunc("/stocks", s.stockController.InsertOne).Methods("POST")
+	v1.HandleFunc("/stocks/export", s.stockController.Export).Methods("GET")
+	v1.HandleFunc("/stocks/events", s.feedController.Stream).Methods("GET")
+	v1.HandleFunc("/stocks/events/ws", s.feedController.Socket).Methods("GET")
+	v1.HandleFunc("/stocks/batch/create", s.stockController.InsertMany).Methods("POST")
+	v1.HandleFunc("/stocks/batch/edit", s.stockController.UpdateMany).Methods("POST")
+	v1.HandleFunc("/stocks/batch/delete", s.stockController.DeleteMany).Methods("POST")
+	v1.HandleFunc("/stocks/{id}", s.stockController.GetOne).Methods("GET")
+	v1.HandleFunc("/stocks/{id}", s.stockController.ReplaceOne).Methods("PUT")
+	v1.HandleFunc("/stocks/{id}", s.stockController.PatchOne).Methods("PATCH")
+	v1.HandleFunc("/stocks/{id}", s.stockController.DeleteOne).Methods("DELETE")
+	v1.HandleFunc("/webhooks", s.webhookController.GetAll).Methods("GET")
+	v1.HandleFunc("/webhooks", s.webhookController.InsertOne).Methods("POST")
+	v1.HandleFunc("/webhooks/dead-letters", s.webhookController.DeadLetters).Methods("GET")
+	v1.HandleFunc("/webhooks/{id}", s.webhookController.GetOne).Methods("GET")
+	v1.HandleFunc("/webhooks/{id}", s.webhookController.UpdateOne).Methods("PUT")
+	v1.HandleFunc("/webhooks/{id}", s.webhookController.DeleteOne).Methods("DELETE")
 
-// Serve starts the server and accepts new calls.
-func (s *Serve) Serve() {
-	port := os.Getenv("SERVER_PORT")
-	address := os.Getenv("SERVER_ADDRESS")
-	if port == "" || address == "" {
-		s.logger.Fatal(errors.New("failed to load env param"))
+	s.Server.HandleFunc("/graphql", s.graphqlController.Serve).Methods("POST")
+
+	if s.checker != nil {
+		s.Server.HandleFunc("/healthz", s.checker.ServeLive).Methods("GET")
+		s.Server.HandleFunc("/readyz", s.checker.ServeReady).Methods("GET")
+	}
+
+	if s.metrics != nil {
+		s.Server.Handle("/metrics", s.metrics.Handler()).Methods("GET")
 	}
 
+	// The routes generated from stocks.proto, proxied to the gRPC server.
+	s.Server.HandleFunc("/openapi.json", gateway.ServeOpenAPI).Methods("GET")
+
+	if s.gateway != nil {
+		s.Server.PathPrefix("/v1/").Handler(s.gateway)
+	}
+
+	if s.config.Features.LegacyRoutes {
+		s.registerLegacyHandlers()
+	}
+}
+
+// registerLegacyHandlers registers the unversioned routes, kept for the existing clients until their sunset.
+func (s *Serve) registerLegacyHandlers() {
+	s.Server.HandleFunc("/", deprecated("/api/v1/stocks", s.stockController.GetAll)).Methods("GET")
+	s.Server.HandleFunc("/", deprecated("/api/v1/stocks", s.stockController.InsertOne)).Methods("POST")
+	s.Server.HandleFunc("/{id}", deprecated("/api/v1/stocks/{id}", s.stockController.GetOne)).Methods("GET")
+	s.Server.HandleFunc("/{id}", deprecated("/api/v1/stocks/{id}", s.stockController.UpdateOne)).Methods("POST")
+	s.Server.HandleFunc("/{id}", deprecated("/api/v1/stocks/{id}", s.stockController.DeleteOne)).Methods("PUT")
+}
+
+// Listen registers the routes and binds the server to its address, over TLS if a certificate is configured.
+func (s *Serve) Listen() error {
 	s.RegisterHandlers()
 	s.Server.Use(s.loggingMiddleware)
 
-	s.logger.Info(fmt.Sprintf("Serving HTTP on: %s:%s", address, port))
-	log.Fatal(http.ListenAndServe(fmt.Sprintf("%s:%s", address, port), s.Server))
+	lis, err := net.Listen("tcp", s.config.Server.Addr())
+	if err != nil {
+		return err
+	}
+
+	s.listener = lis
+	s.http = &http.Server{
+		Handler:           s.Server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if s.certs != nil {
+		s.http.TLSConfig = s.certs.Server()
+	}
+
+	return nil
+}
+
+// Serve accepts new calls until the server shuts down.
+func (s *Serve) Serve() error {
+	var err error
+
+	if s.http.TLSConfig != nil {
+		s.logger.Info(fmt.Sprintf("Serving HTTPS on: %s", s.listener.Addr()))
+		err = s.http.ServeTLS(s.listener, "", "")
+	} else {
+		s.logger.Info(fmt.Sprintf("Serving HTTP on: %s", s.listener.Addr()))
+		err = s.http.Serve(s.listener)
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown stops accepting new calls and waits for the ones in progress, closing them all once the context is done.
+// The event streams end as the stock event broker stops.
+func (s *Serve) Shutdown(ctx context.Context) error {
+	if err := s.http.Shutdown(ctx); err != nil {
+		s.http.Close()
+		return err
+	}
+
+	return nil
+}
 
-	defer s.wg.Done()
+// Addr the address the server listens on.
+func (s *Serve) Addr() net.Addr {
+	return s.listener.Addr()
 }
 
 func (s *Serve) loggingMiddleware(next http.Handler) http.Handler {
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"stocks-api/support/tracing"
+)
+
+// tracingMiddleware serves every request within a span named by its route, continuing the trace of its caller if any.
+func (s *Serve) tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Start(ctx, routeOf(r),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(r.Method),
+				semconv.HTTPRoute(templateOf(r)),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPStatusCode(sw.status))
+
+		// The client errors are the caller's, only the server ones fail the span.
+		if sw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d %s", sw.status, http.StatusText(sw.status)))
+		}
+	})
+}
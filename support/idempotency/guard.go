@@ -0,0 +1,170 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/repos"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+const (
+	// Header the HTTP header carrying the idempotency key of a request.
+	Header = "Idempotency-Key"
+	// MetadataKey the gRPC metadata key carrying the idempotency key of a call.
+	MetadataKey = "idempotency-key"
+	// ReplayedHeader flags the responses replayed from a stored record.
+	ReplayedHeader = "Idempotent-Replayed"
+	// ReplayedMetadataKey flags the gRPC responses replayed from a stored record.
+	ReplayedMetadataKey = "idempotent-replayed"
+
+	// lock how long a request in progress holds its key, before a retry may take it over.
+	lock          = time.Minute
+	purgeInterval = time.Hour
+	maxKeyLength  = 255
+)
+
+// RecordStore a contract to the Idempotency Repo.
+type RecordStore interface {
+	Claim(ctx context.Context, record *entities.IdempotencyRecord, lock time.Duration) (bool, error)
+	Find(ctx context.Context, key string) (*entities.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *entities.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Guard makes the requests carrying an idempotency key safe to retry: the first one is served and its outcome stored,
+// the retries of the same request are answered with it until the record expires.
+type Guard struct {
+	logger *logrus.Logger
+	store  RecordStore
+	ttl    time.Duration
+}
+
+// NewGuard a constructor for the Guard.
+func NewGuard(l *logrus.Logger, store RecordStore, ttl time.Duration) *Guard {
+	return &Guard{
+		logger: l,
+		store:  store,
+		ttl:    ttl,
+	}
+}
+
+// FromConfig returns the guard keeping the records for the ttl of the idempotency config.
+func FromConfig(l *logrus.Logger, db *db.Instance, c config.Idempotency) *Guard {
+	return NewGuard(l, repos.NewIdempotencyRepo(l, db), c.TTL)
+}
+
+// Fingerprint hashes the parts identifying a request, its route and its payload.
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin claims the key for a request of the given fingerprint, within the tenant of the context.
+// It returns nil if the request is to be served, or the completed record of its previous attempt to replay.
+// A key in use by a request in progress or by a different request is rejected.
+func (g *Guard) Begin(ctx context.Context, key string, fingerprint string) (*entities.IdempotencyRecord, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+
+	claimed, err := g.store.Claim(ctx, &entities.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(g.ttl),
+	}, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed {
+		return nil, nil
+	}
+
+	record, err := g.store.Find(ctx, key)
+	if errs.KindOf(err) == errs.NotFound {
+		return nil, errs.New(errs.Aborted, "the request holding the idempotency key was released, retry it")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, errs.New(errs.InvalidArgument, "the idempotency key was used by a different request").On("idempotency_key")
+	}
+
+	if !record.Completed() {
+		return nil, errs.New(errs.Aborted, "a request with the same idempotency key is in progress")
+	}
+
+	return record, nil
+}
+
+// Complete stores the outcome of the request holding the key, to replay it.
+// The request being over, its cancellation doesn't apply.
+func (g *Guard) Complete(ctx context.Context, key string, status int, headers map[string][]string, response []byte) {
+	err := g.store.Complete(context.WithoutCancel(ctx), &entities.IdempotencyRecord{
+		Key:      key,
+		Status:   status,
+		Headers:  headers,
+		Response: response,
+	})
+	if err != nil {
+		g.logger.Errorf("failed to store the outcome of idempotency key %s: %s", key, err)
+	}
+}
+
+// Release frees the key of a failed request, its retries are served again.
+func (g *Guard) Release(ctx context.Context, key string) {
+	if err := g.store.Release(context.WithoutCancel(ctx), key); err != nil {
+		g.logger.Errorf("failed to release idempotency key %s: %s", key, err)
+	}
+}
+
+// Run purges the expired records until the context is done.
+func (g *Guard) Run(ctx context.Context) error {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if purged, err := g.store.Purge(ctx, time.Now()); err != nil {
+				g.logger.Errorf("failed to purge the idempotency records: %s", err)
+			} else if purged > 0 {
+				g.logger.Infof("purged %d expired idempotency records", purged)
+			}
+		}
+	}
+}
+
+// validKey accepts the keys of up to 255 visible ASCII characters.
+func validKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return errs.New(errs.InvalidArgument, "the idempotency key must have 1 to 255 characters").On("idempotency_key")
+	}
+
+	for _, c := range key {
+		if c < 0x21 || c > 0x7e {
+			return errs.New(errs.InvalidArgument, "the idempotency key must be printable ASCII").On("idempotency_key")
+		}
+	}
+
+	return nil
+}
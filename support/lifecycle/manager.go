@@ -0,0 +1,183 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"stocks-api/support/config"
+)
+
+// Server a server run by the Manager.
+type Server interface {
+	// Listen binds the server to its address, before any server starts serving.
+	Listen() error
+	// Serve accepts the calls until the server shuts down, returning nil once it has.
+	Serve() error
+	// Shutdown stops accepting new calls and waits for the ones in progress, forcing them closed once the context is done.
+	Shutdown(ctx context.Context) error
+}
+
+type server struct {
+	name   string
+	server Server
+}
+
+type worker struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type closer struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs the servers along with their background workers, until SIGINT or SIGTERM or a server failing.
+// It then drains the servers, stops the workers and releases the resources, within the shutdown timeout.
+type Manager struct {
+	logger     *logrus.Logger
+	timeout    time.Duration
+	servers    []server
+	workers    []worker
+	closers    []closer
+	onReady    []func()
+	onShutdown []func()
+}
+
+// NewManager a constructor for the Manager.
+func NewManager(l *logrus.Logger, timeout time.Duration) *Manager {
+	return &Manager{
+		logger:  l,
+		timeout: timeout,
+	}
+}
+
+// FromConfig returns the manager draining the servers for the shutdown timeout.
+func FromConfig(l *logrus.Logger, c config.Shutdown) *Manager {
+	return NewManager(l, c.Timeout)
+}
+
+// Serve registers a server, its failure shuts the others down.
+func (m *Manager) Serve(name string, s Server) {
+	m.servers = append(m.servers, server{name: name, server: s})
+}
+
+// Go registers a background worker, run until the shutdown starts.
+// A failing worker is logged without taking the servers down.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	m.workers = append(m.workers, worker{name: name, fn: fn})
+}
+
+// Close registers a resource to release once the servers and the workers have stopped, in the reverse order.
+func (m *Manager) Close(name string, fn func(ctx context.Context) error) {
+	m.closers = append(m.closers, closer{name: name, fn: fn})
+}
+
+// OnReady registers fn to call once every server listens.
+func (m *Manager) OnReady(fn func()) {
+	m.onReady = append(m.onReady, fn)
+}
+
+// OnShutdown registers fn to call as the shutdown starts, before the servers drain.
+func (m *Manager) OnShutdown(fn func()) {
+	m.onShutdown = append(m.onShutdown, fn)
+}
+
+// Run runs the servers and the workers until the context is done, a signal is received or a server fails.
+// It returns the error of the failed server, or of the servers which couldn't drain in time.
+func (m *Manager) Run(ctx context.Context) error {
+	defer m.close()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, s := range m.servers {
+		if err := s.server.Listen(); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	for _, w := range m.workers {
+		w := w
+
+		g.Go(func() error {
+			if err := w.fn(gctx); err != nil {
+				m.logger.Errorf("%s stopped: %s", w.name, err)
+			}
+
+			return nil
+		})
+	}
+
+	for _, s := range m.servers {
+		s := s
+
+		g.Go(func() error {
+			if err := s.server.Serve(); err != nil {
+				return fmt.Errorf("%s: %w", s.name, err)
+			}
+
+			return nil
+		})
+	}
+
+	for _, fn := range m.onReady {
+		fn()
+	}
+
+	g.Go(func() error {
+		<-gctx.Done()
+
+		return m.shutdown()
+	})
+
+	return g.Wait()
+}
+
+// shutdown drains the servers together, within the timeout.
+func (m *Manager) shutdown() error {
+	m.logger.Info("shutting down")
+
+	for _, fn := range m.onShutdown {
+		fn()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var g errgroup.Group
+
+	for _, s := range m.servers {
+		s := s
+
+		g.Go(func() error {
+			if err := s.server.Shutdown(ctx); err != nil {
+				return fmt.Errorf("%s failed to drain: %w", s.name, err)
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// close releases the resources in the reverse order of their registration, within the timeout.
+func (m *Manager) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		if err := m.closers[i].fn(ctx); err != nil {
+			m.logger.Errorf("failed to close the %s: %s", m.closers[i].name, err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// queryHook times the database queries, alongside the bundebug one.
+type queryHook struct {
+	metrics *Metrics
+}
+
+// QueryHook the bun hook recording the duration and the failures of the queries.
+func (m *Metrics) QueryHook() bun.QueryHook {
+	return &queryHook{metrics: m}
+}
+
+// BeforeQuery nothing to do, the event carries its start time.
+func (h *queryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery records the query, a missing row isn't a failure.
+func (h *queryHook) AfterQuery(_ context.Context, event *bun.QueryEvent) {
+	operation := event.Operation()
+
+	h.metrics.dbDuration.WithLabelValues(operation).Observe(time.Since(event.StartTime).Seconds())
+
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		h.metrics.dbErrors.WithLabelValues(operation).Inc()
+	}
+}
@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/repos"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+const (
+	namespace         = "stocks"
+	inventoryInterval = 30 * time.Second
+)
+
+// InventoryStore a contract to the Stock Repo.
+type InventoryStore interface {
+	Inventory(ctx context.Context, threshold int64) ([]*entities.Inventory, error)
+}
+
+// Metrics the Prometheus metrics of the servers, the database and the inventory.
+type Metrics struct {
+	logger    *logrus.Logger
+	store     InventoryStore
+	threshold int64
+	registry  *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	dbDuration   *prometheus.HistogramVec
+	dbErrors     *prometheus.CounterVec
+	skus         *prometheus.GaugeVec
+	units        *prometheus.GaugeVec
+	lowStock     *prometheus.GaugeVec
+}
+
+// NewMetrics a constructor for the Metrics. The pool of conn and the inventory of the store are only
+// reported when given.
+func NewMetrics(l *logrus.Logger, store InventoryStore, conn *sql.DB, threshold int64) *Metrics {
+	m := &Metrics{
+		logger:    l,
+		store:     store,
+		threshold: threshold,
+		registry:  prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "The HTTP requests served, by route and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "How long the HTTP requests took to serve, by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "The gRPC calls served, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "How long the gRPC calls took to serve, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "How long the database queries took, by operation.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "The database queries which failed, by operation.",
+		}, []string{"operation"}),
+		skus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "inventory_skus",
+			Help:      "The stock items of a tenant.",
+		}, []string{"tenant"}),
+		units: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "inventory_units",
+			Help:      "The units in stock of a tenant, across all its items.",
+		}, []string{"tenant"}),
+		lowStock: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "inventory_low_stock_items",
+			Help:      "The stock items of a tenant at or below the low stock threshold.",
+		}, []string{"tenant"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.dbDuration,
+		m.dbErrors,
+		m.skus,
+		m.units,
+		m.lowStock,
+	)
+
+	if conn != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(conn, namespace))
+	}
+
+	return m
+}
+
+// FromConfig returns the metrics of the database, nil if the metrics are disabled. Its queries are timed from then on.
+// The items at or below the low stock threshold count as low on stock.
+func FromConfig(l *logrus.Logger, db *db.Instance, c config.Metrics) *Metrics {
+	if c.Disabled {
+		return nil
+	}
+
+	m := NewMetrics(l, repos.NewStockRepo(l, db), db.Base.DB, c.LowStockThreshold)
+	db.Base.AddQueryHook(m.QueryHook())
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTP records a served HTTP request, named by its method and path template.
+func (m *Metrics) ObserveHTTP(method string, route string, code int, elapsed time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveGRPC records a served gRPC call, named by its full method.
+func (m *Metrics) ObserveGRPC(method string, code string, elapsed time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
+
+// Run refreshes the inventory gauges until the context is done.
+func (m *Metrics) Run(ctx context.Context) error {
+	if m.store == nil {
+		return nil
+	}
+
+	ticker := time.NewTicker(inventoryInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Refresh(ctx); err != nil {
+			m.logger.Errorf("failed to refresh the inventory metrics: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh sets the inventory gauges to the current stock of every tenant.
+func (m *Metrics) Refresh(ctx context.Context) error {
+	inventories, err := m.store.Inventory(ctx, m.threshold)
+	if err != nil {
+		return err
+	}
+
+	// The tenants left without stock are dropped, rather than reported at their last values.
+	m.skus.Reset()
+	m.units.Reset()
+	m.lowStock.Reset()
+
+	for _, inventory := range inventories {
+		m.skus.WithLabelValues(inventory.TenantID).Set(float64(inventory.Skus))
+		m.units.WithLabelValues(inventory.TenantID).Set(float64(inventory.Units))
+		m.lowStock.WithLabelValues(inventory.TenantID).Set(float64(inventory.LowStock))
+	}
+
+	return nil
+}
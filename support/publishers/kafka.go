@@ -0,0 +1,65 @@
+package publishers
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"stocks-api/module/entities"
+)
+
+// KafkaWriter the subset of *kafka.Writer the publisher relies on.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaPublisher publishes the events to a Kafka topic, keyed by stock ID so that the events of an item stay ordered.
+type KafkaPublisher struct {
+	writer KafkaWriter
+}
+
+// NewKafkaPublisher a constructor for the KafkaPublisher.
+func NewKafkaPublisher(writer KafkaWriter) *KafkaPublisher {
+	return &KafkaPublisher{writer: writer}
+}
+
+// DialKafka returns the publisher of the given topic, acknowledged by all in-sync replicas.
+func DialKafka(brokers []string, topic string) *KafkaPublisher {
+	return NewKafkaPublisher(&kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	})
+}
+
+// Publish writes the events as a single batch.
+func (k *KafkaPublisher) Publish(ctx context.Context, events ...*entities.OutboxEvent) error {
+	msgs := make([]kafka.Message, 0, len(events))
+
+	for _, ev := range events {
+		data, err := Encode(ev)
+		if err != nil {
+			return err
+		}
+
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(ev.StockID.String()),
+			Value: data,
+			Headers: []kafka.Header{
+				{Key: "event-id", Value: []byte(ev.ID.String())},
+				{Key: "event-type", Value: []byte(ev.Type)},
+				{Key: "tenant-id", Value: []byte(ev.TenantID)},
+				{Key: "content-type", Value: []byte(ContentType)},
+				{Key: "schema", Value: []byte(Schema)},
+			},
+		})
+	}
+
+	return k.writer.WriteMessages(ctx, msgs...)
+}
+
+// Close flushes and closes the writer.
+func (k *KafkaPublisher) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,62 @@
+package publishers
+
+import (
+	"context"
+	"sync"
+
+	"stocks-api/module/entities"
+)
+
+// MemoryPublisher keeps the published events in memory, meant for tests and local runs.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages [][]byte
+	err      error
+}
+
+// NewMemoryPublisher a constructor for the MemoryPublisher.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish encodes and stores the events, or fails with the error set via FailWith.
+func (m *MemoryPublisher) Publish(_ context.Context, events ...*entities.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	for _, ev := range events {
+		data, err := Encode(ev)
+		if err != nil {
+			return err
+		}
+
+		m.messages = append(m.messages, data)
+	}
+
+	return nil
+}
+
+// Messages returns the encoded events published so far.
+func (m *MemoryPublisher) Messages() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([][]byte{}, m.messages...)
+}
+
+// FailWith makes the following publishes fail with the given error, nil restores them.
+func (m *MemoryPublisher) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = err
+}
+
+// Close is a no-op.
+func (m *MemoryPublisher) Close() error {
+	return nil
+}
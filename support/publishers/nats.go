@@ -0,0 +1,92 @@
+package publishers
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"stocks-api/module/entities"
+)
+
+// DefaultNatsSubjectPrefix the events are published on "<prefix>.stock.<type>", e.g. "stocks.events.v1.stock.created".
+const DefaultNatsSubjectPrefix = "stocks.events.v1"
+
+// NatsConn the subset of the JetStream context the publisher relies on, along with closing its connection.
+type NatsConn interface {
+	PublishMsg(msg *nats.Msg, opts ...nats.PubOpt) (*nats.PubAck, error)
+	Close()
+}
+
+// jetStreamConn the JetStream context of a NATS connection.
+type jetStreamConn struct {
+	nats.JetStreamContext
+	conn *nats.Conn
+}
+
+func (j *jetStreamConn) Close() {
+	j.conn.Close()
+}
+
+// NatsPublisher publishes the events to a JetStream stream, deduplicated via the Nats-Msg-Id header.
+type NatsPublisher struct {
+	conn          NatsConn
+	subjectPrefix string
+}
+
+// NewNatsPublisher a constructor for the NatsPublisher.
+func NewNatsPublisher(conn NatsConn, subjectPrefix string) *NatsPublisher {
+	if subjectPrefix == "" {
+		subjectPrefix = DefaultNatsSubjectPrefix
+	}
+
+	return &NatsPublisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+// ConnectNats connects to the NATS server at url and returns its publisher.
+// A JetStream stream must capture the subjects, the publishes failing otherwise.
+func ConnectNats(url string, subjectPrefix string) (*NatsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("stocks-api"))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return NewNatsPublisher(&jetStreamConn{JetStreamContext: js, conn: conn}, subjectPrefix), nil
+}
+
+// Publish sends the events one at a time, in order, each waiting for the stream to acknowledge it.
+func (n *NatsPublisher) Publish(ctx context.Context, events ...*entities.OutboxEvent) error {
+	for _, ev := range events {
+		data, err := Encode(ev)
+		if err != nil {
+			return err
+		}
+
+		msg := nats.NewMsg(n.subjectPrefix + "." + routingKey(ev.Type))
+		msg.Data = data
+		msg.Header.Set(nats.MsgIdHdr, ev.ID.String())
+		msg.Header.Set("Content-Type", ContentType)
+		msg.Header.Set("Schema", Schema)
+		msg.Header.Set("Tenant-Id", ev.TenantID)
+
+		if _, err := n.conn.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the connection.
+func (n *NatsPublisher) Close() error {
+	n.conn.Close()
+
+	return nil
+}
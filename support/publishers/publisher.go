@@ -0,0 +1,65 @@
+package publishers
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	eventsv1 "stocks-api/genprotos/events/v1"
+	"stocks-api/module/entities"
+)
+
+const (
+	// ContentType of the published payloads.
+	ContentType = "application/x-protobuf"
+	// Schema the fully qualified name of the published message, versioned by its package.
+	Schema = "stocks.events.v1.StockEvent"
+)
+
+// EventPublisher publishes the outbox events to a message broker.
+// Publish must only return once the broker acknowledged every event, the events are retried otherwise.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...*entities.OutboxEvent) error
+	Close() error
+}
+
+// Encode serialises an outbox event into the versioned protobuf schema.
+func Encode(event *entities.OutboxEvent) ([]byte, error) {
+	return proto.Marshal(ToEventPb(event))
+}
+
+// ToEventPb converts an outbox event into the versioned protobuf schema.
+func ToEventPb(event *entities.OutboxEvent) *eventsv1.StockEvent {
+	return &eventsv1.StockEvent{
+		Id:         event.ID.String(),
+		Type:       toEventTypePb(event.Type),
+		OccurredAt: timestamppb.New(event.CreatedAt),
+		Stock: &eventsv1.Stock{
+			Id:        event.Stock.ID.String(),
+			Name:      event.Stock.Name,
+			Quantity:  event.Stock.Quantity,
+			CreatedAt: timestamppb.New(event.Stock.CreatedAt),
+			UpdatedAt: timestamppb.New(event.Stock.UpdatedAt),
+		},
+		TenantId: event.TenantID,
+	}
+}
+
+// routingKey the per event type suffix of the NATS subjects, e.g. "stock.updated".
+func routingKey(t entities.EventType) string {
+	return "stock." + strings.ToLower(string(t))
+}
+
+func toEventTypePb(t entities.EventType) eventsv1.StockEventType {
+	switch t {
+	case entities.Created:
+		return eventsv1.StockEventType_STOCK_EVENT_TYPE_CREATED
+	case entities.Updated:
+		return eventsv1.StockEventType_STOCK_EVENT_TYPE_UPDATED
+	case entities.Deleted:
+		return eventsv1.StockEventType_STOCK_EVENT_TYPE_DELETED
+	}
+
+	return eventsv1.StockEventType_STOCK_EVENT_TYPE_UNSPECIFIED
+}
@@ -0,0 +1,92 @@
+package publishers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/repos"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+const (
+	relayInterval  = time.Second
+	relayBatchSize = 100
+)
+
+// OutboxStore a contract to the Outbox Repo.
+type OutboxStore interface {
+	PublishPending(ctx context.Context, limit int, fn func(ctx context.Context, events ...*entities.OutboxEvent) error) (int, error)
+}
+
+// Relay feeds the outbox events to an EventPublisher, at least once and in order.
+type Relay struct {
+	logger    *logrus.Logger
+	store     OutboxStore
+	publisher EventPublisher
+}
+
+// NewRelay a constructor for the Relay.
+func NewRelay(l *logrus.Logger, db *db.Instance, publisher EventPublisher) *Relay {
+	return &Relay{
+		logger:    l,
+		store:     repos.NewOutboxRepo(l, db),
+		publisher: publisher,
+	}
+}
+
+// Run publishes the pending events until the context is done, then closes the publisher.
+func (r *Relay) Run(ctx context.Context) error {
+	defer r.publisher.Close()
+
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.publishPending(ctx)
+		}
+	}
+}
+
+func (r *Relay) publishPending(ctx context.Context) {
+	for {
+		n, err := r.store.PublishPending(ctx, relayBatchSize, r.publisher.Publish)
+		if err != nil {
+			r.logger.Errorf("failed to publish the outbox events: %s", err)
+			return
+		}
+
+		if n < relayBatchSize {
+			return
+		}
+	}
+}
+
+// FromConfig builds the publisher of the publisher config (nats, kafka or memory).
+// Returns nil when no publisher is configured.
+func FromConfig(c config.Publisher) (EventPublisher, error) {
+	switch c.Kind {
+	case "":
+		return nil, nil
+	case "memory":
+		return NewMemoryPublisher(), nil
+	case "nats":
+		return ConnectNats(c.NatsURL, c.NatsSubjectPrefix)
+	case "kafka":
+		if len(c.KafkaBrokers) == 0 || c.KafkaTopic == "" {
+			return nil, errors.New("failed to load the kafka_brokers/kafka_topic settings")
+		}
+
+		return DialKafka(c.KafkaBrokers, c.KafkaTopic), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown event publisher: '%s'", c.Kind))
+	}
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limit a token bucket: Requests per Per on average, in bursts of up to Burst requests.
+// Burst defaults to Requests, a zero Requests lifts the limit.
+type Limit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Config the limits of the clients, by route. The routes are named "<METHOD> <path template>" over HTTP,
+// e.g. "POST /api/v1/stocks", and by their full method over gRPC, e.g. "/stocks.StockService/CreateStock".
+// Every route without a limit of its own shares the Default one.
+type Config struct {
+	Default Limit            `yaml:"default"`
+	Routes  map[string]Limit `yaml:"routes"`
+}
+
+// Default the limits used when no config file is configured.
+func Default() *Config {
+	return &Config{
+		Default: Limit{Requests: 20, Per: time.Second, Burst: 40},
+		Routes: map[string]Limit{
+			"GET /api/v1/stocks/export":         {Requests: 10, Per: time.Minute},
+			"/stocks.StockService/ExportStocks": {Requests: 10, Per: time.Minute},
+		},
+	}
+}
+
+// Load reads a YAML config file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, errors.New(fmt.Sprintf("malformed rate limit file %s: %s", path, err))
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid rate limit file %s: %s", path, err))
+	}
+
+	return c, nil
+}
+
+// limitOf returns the limit of a route, and the name of the bucket it is counted in.
+func (c *Config) limitOf(route string) (Limit, string) {
+	if limit, ok := c.Routes[route]; ok {
+		return limit, route
+	}
+
+	return c.Default, "default"
+}
+
+// idle how long a bucket takes to refill from empty, the longest of all limits.
+func (c *Config) idle() time.Duration {
+	longest := c.Default.refill()
+
+	for _, limit := range c.Routes {
+		if refill := limit.refill(); refill > longest {
+			longest = refill
+		}
+	}
+
+	return longest
+}
+
+func (c *Config) validate() error {
+	if err := c.Default.validate(); err != nil {
+		return errors.New(fmt.Sprintf("default: %s", err))
+	}
+
+	for route, limit := range c.Routes {
+		if err := limit.validate(); err != nil {
+			return errors.New(fmt.Sprintf("route '%s': %s", route, err))
+		}
+	}
+
+	return nil
+}
+
+// Unlimited returns true if the limit lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+// capacity the size of the bucket.
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+
+	return float64(l.Requests)
+}
+
+// rate the tokens added to the bucket per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// refill how long the bucket takes to refill from empty.
+func (l Limit) refill() time.Duration {
+	if l.Unlimited() {
+		return 0
+	}
+
+	return time.Duration(l.capacity() / l.rate() * float64(time.Second))
+}
+
+func (l Limit) validate() error {
+	if l.Requests < 0 || l.Burst < 0 {
+		return errors.New("requests and burst can't be negative")
+	}
+
+	if !l.Unlimited() && l.Per <= 0 {
+		return errors.New("per must be a positive duration")
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/repos"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+const purgeInterval = 10 * time.Minute
+
+// BucketStore a contract to the Rate Limit Repo, or to the MemoryStore of a single replica.
+type BucketStore interface {
+	Take(ctx context.Context, key string, fn func(bucket *entities.RateLimitBucket)) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Decision the outcome of a request against the limit of its route.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset how long the bucket takes to refill.
+	Reset time.Duration
+	// RetryAfter how long until the next request is allowed, for the refused ones.
+	RetryAfter time.Duration
+}
+
+// Limiter enforces the rate limits of the clients, a token bucket per client and route.
+type Limiter struct {
+	logger *logrus.Logger
+	store  BucketStore
+	config *Config
+}
+
+// NewLimiter a constructor for the Limiter.
+func NewLimiter(l *logrus.Logger, store BucketStore, config *Config) *Limiter {
+	return &Limiter{
+		logger: l,
+		store:  store,
+		config: config,
+	}
+}
+
+// FromConfig returns the limiter of the rate limit config, nil if the rate limiting is disabled.
+// The buckets are kept in memory, or in Postgres with the postgres store so that the replicas share them.
+func FromConfig(l *logrus.Logger, db *db.Instance, c config.RateLimit) (*Limiter, error) {
+	if c.Disabled {
+		return nil, nil
+	}
+
+	limits := Default()
+
+	if c.File != "" {
+		var err error
+
+		if limits, err = Load(c.File); err != nil {
+			return nil, err
+		}
+	}
+
+	switch c.Store {
+	case "", "memory":
+		return NewLimiter(l, NewMemoryStore(), limits), nil
+	case "postgres":
+		return NewLimiter(l, repos.NewRateLimitRepo(l, db), limits), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown rate limit store: %s", c.Store))
+	}
+}
+
+// Client identifies the client of a request by its principal, or by its IP for the anonymous ones.
+func Client(principal *entities.Principal, ip string) string {
+	if principal != nil {
+		return fmt.Sprintf("%s:%s", principal.Kind, principal.Subject)
+	}
+
+	return fmt.Sprintf("ip:%s", ip)
+}
+
+// Allow takes a token from the bucket of the client on the route, nil if the route is unlimited.
+// The requests are let through when the buckets can't be reached, rather than failing them all.
+func (l *Limiter) Allow(ctx context.Context, route string, client string) *Decision {
+	limit, bucket := l.config.limitOf(route)
+	if limit.Unlimited() {
+		return nil
+	}
+
+	var decision Decision
+
+	err := l.store.Take(ctx, fmt.Sprintf("%s|%s", bucket, client), func(b *entities.RateLimitBucket) {
+		decision = take(b, limit, time.Now())
+	})
+	if err != nil {
+		l.logger.Errorf("rate limit of %s unavailable: %s", client, err)
+		return nil
+	}
+
+	return &decision
+}
+
+// Run purges the buckets which have refilled until the context is done.
+func (l *Limiter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := l.store.Purge(ctx, time.Now().Add(-l.config.idle())); err != nil {
+				l.logger.Errorf("failed to purge the rate limit buckets: %s", err)
+			}
+		}
+	}
+}
+
+// Headers the RateLimit-* headers describing the decision, and the Retry-After one of the refused requests.
+func (d *Decision) Headers() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(d.Limit),
+		"RateLimit-Remaining": strconv.Itoa(d.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(seconds(d.Reset)),
+	}
+
+	if !d.Allowed {
+		headers["Retry-After"] = strconv.Itoa(seconds(d.RetryAfter))
+	}
+
+	return headers
+}
+
+// Err the error of a refused request.
+func (d *Decision) Err() error {
+	return errs.New(errs.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry in %ds", seconds(d.RetryAfter)))
+}
+
+// seconds rounds a duration up to whole seconds, as the headers expect.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// take refills the bucket for the time elapsed since its last update, then takes a token from it if there is one.
+func take(b *entities.RateLimitBucket, limit Limit, now time.Time) Decision {
+	capacity, rate := limit.capacity(), limit.rate()
+
+	tokens := capacity
+	if !b.UpdatedAt.IsZero() {
+		tokens = math.Min(capacity, b.Tokens+now.Sub(b.UpdatedAt).Seconds()*rate)
+	}
+
+	d := Decision{Limit: int(capacity)}
+
+	if tokens >= 1 {
+		tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = duration((1 - tokens) / rate)
+	}
+
+	b.Tokens, b.UpdatedAt = tokens, now
+
+	d.Remaining = int(tokens)
+	d.Reset = duration((capacity - tokens) / rate)
+
+	return d
+}
+
+func duration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"stocks-api/module/entities"
+)
+
+// MemoryStore keeps the buckets in memory, each replica enforcing its own limits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]entities.RateLimitBucket
+}
+
+// NewMemoryStore a constructor for the MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]entities.RateLimitBucket{},
+	}
+}
+
+// Take updates the bucket of the key with fn.
+func (m *MemoryStore) Take(_ context.Context, key string, fn func(bucket *entities.RateLimitBucket)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket := m.buckets[key]
+	bucket.Key = key
+
+	fn(&bucket)
+	m.buckets[key] = bucket
+
+	return nil
+}
+
+// Purge deletes the buckets left untouched since the given time, returning their number.
+func (m *MemoryStore) Purge(_ context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+
+	for key, bucket := range m.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(m.buckets, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
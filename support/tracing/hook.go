@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLength the longest statement recorded on a span, the batches can run long.
+const maxStatementLength = 2048
+
+// queryHook traces the database queries, alongside the bundebug one.
+type queryHook struct{}
+
+// QueryHook the bun hook tracing every query as a child span of the call running it.
+// The queries of the background loops, outside any trace, aren't traced.
+func QueryHook() bun.QueryHook {
+	return &queryHook{}
+}
+
+// BeforeQuery starts the span of the query.
+func (h *queryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	operation := event.Operation()
+
+	statement := event.Query
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+
+	ctx, _ = Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(statement),
+		),
+	)
+
+	return ctx
+}
+
+// AfterQuery ends the span of the query, a missing row isn't a failure.
+func (h *queryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		Fail(span, event.Err)
+	}
+
+	span.End()
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHook adds the trace_id and span_id fields to the entries logged with the context of a traced call.
+type LogHook struct{}
+
+// Levels every level is hooked.
+func (h LogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the ids of the span of the context of the entry, if any.
+func (h LogHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	for key, value := range Fields(entry.Context) {
+		entry.Data[key] = value
+	}
+
+	return nil
+}
+
+// Fields the trace_id and span_id log fields of the span of the context, none if it isn't traced.
+func Fields(ctx context.Context) logrus.Fields {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return logrus.Fields{}
+	}
+
+	return logrus.Fields{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataCarrier carries the trace context in the gRPC metadata.
+type MetadataCarrier metadata.MD
+
+// Get the first value of the key.
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Set replaces the values of the key.
+func (c MetadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys the keys of the metadata.
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"stocks-api/support/config"
+)
+
+const (
+	// instrumentation the name of the tracer of the API spans.
+	instrumentation = "stocks-api"
+	serviceName     = "stocks-api"
+)
+
+// propagator reads and writes the W3C trace context and baggage of the requests.
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Provider exports the spans of the servers, until it shuts down.
+type Provider struct {
+	logger *logrus.Logger
+	sdk    *sdktrace.TracerProvider
+}
+
+// NewProvider a constructor for the Provider, sampling ratio of the traces started by the servers.
+// The traces of the callers are sampled as they decided.
+func NewProvider(l *logrus.Logger, exporter sdktrace.SpanExporter, ratio float64) (*Provider, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES env params take precedence.
+	res, err = resource.Merge(res, resource.Environment())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		logger: l,
+		sdk: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		),
+	}, nil
+}
+
+// FromConfig returns the provider of the tracing exporter, nil if unset:
+// "otlp" exports over OTLP/HTTP to the endpoint, "stdout" prints the spans.
+// The sample ratio is the ratio of the traces started by the servers to sample.
+// The provider is registered globally, along with the W3C trace context propagation.
+func FromConfig(ctx context.Context, l *logrus.Logger, c config.Tracing) (*Provider, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch c.Exporter {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx, endpointOptions(c.Endpoint)...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown tracing exporter: %s", c.Exporter))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := NewProvider(l, exporter, c.SampleRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(p.sdk)
+
+	return p, nil
+}
+
+// endpointOptions the options of the OTLP/HTTP exporter sending to the collector URL, the default collector if unset.
+func endpointOptions(endpoint string) []otlptracehttp.Option {
+	u, err := url.Parse(endpoint)
+	if endpoint == "" || err != nil {
+		return nil
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + "/v1/traces"),
+	}
+
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return opts
+}
+
+// Shutdown exports the spans left, then stops the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.sdk.Shutdown(ctx)
+}
+
+// Start starts a span as a child of the one of the context, if any.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, opts...)
+}
+
+// Fail marks the span as failed with err.
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Extract returns the context continuing the trace read from the carrier.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
+
+// Inject writes the trace of the context to the carrier, for the next hop to continue it.
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/repos"
+	"stocks-api/support/db"
+)
+
+const (
+	pollInterval = 2 * time.Second
+	batchSize    = 100
+	// maxAttempts the number of failed attempts after which a delivery is dead-lettered.
+	maxAttempts = 10
+	// lease how long a claimed delivery is hidden from the other dispatchers.
+	lease       = time.Minute
+	sendTimeout = 10 * time.Second
+	// concurrency the number of deliveries sent at once.
+	concurrency = 10
+	// claimSize the deliveries claimed at once, all sent within half the lease even if every send times out,
+	// so that no other dispatcher claims them again meanwhile.
+	claimSize      = concurrency * int(lease/sendTimeout) / 2
+	backoffBase    = 5 * time.Second
+	backoffCeiling = time.Hour
+	// retention how long the dispatched outbox events are kept, once delivered to every subscription and published.
+	retention     = 7 * 24 * time.Hour
+	purgeInterval = time.Hour
+)
+
+// DeliveryStore a contract to the Webhook Repo.
+type DeliveryStore interface {
+	FanOut(ctx context.Context, limit int) (int, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*entities.WebhookDelivery, error)
+	Delivered(ctx context.Context, delivery *entities.WebhookDelivery) error
+	Reschedule(ctx context.Context, delivery *entities.WebhookDelivery) error
+	DeadLetter(ctx context.Context, delivery *entities.WebhookDelivery) error
+	Purge(ctx context.Context, before time.Time, relayed bool) (int64, error)
+}
+
+// Dispatcher relays the outbox events to the webhook subscriptions, retrying failed deliveries with an exponential backoff.
+type Dispatcher struct {
+	logger *logrus.Logger
+	store  DeliveryStore
+	sender *Sender
+	// relayed whether the broker relay consumes the outbox too, its events then being kept until published.
+	relayed bool
+}
+
+// NewDispatcher a constructor for the Dispatcher.
+func NewDispatcher(l *logrus.Logger, store DeliveryStore, sender *Sender, relayed bool) *Dispatcher {
+	return &Dispatcher{
+		logger:  l,
+		store:   store,
+		sender:  sender,
+		relayed: relayed,
+	}
+}
+
+// FromDB the dispatcher of the outbox events of the db.
+func FromDB(l *logrus.Logger, db *db.Instance, relayed bool) *Dispatcher {
+	return NewDispatcher(l, repos.NewWebhookRepo(l, db), NewSender(&http.Client{Timeout: sendTimeout}), relayed)
+}
+
+// Run dispatches the pending events, and purges the ones past the retention, until the context is done.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	purgeTicker := time.NewTicker(purgeInterval)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.fanOut(ctx)
+			d.Deliver(ctx)
+		case <-purgeTicker.C:
+			if purged, err := d.store.Purge(ctx, time.Now().Add(-retention), d.relayed); err != nil {
+				d.logger.Errorf("failed to purge the dispatched outbox events: %s", err)
+			} else if purged > 0 {
+				d.logger.Infof("purged %d dispatched outbox events", purged)
+			}
+		}
+	}
+}
+
+// Backoff returns the delay before the next attempt, doubling after every failed one.
+func Backoff(attempts int) time.Duration {
+	delay := backoffBase
+
+	for i := 1; i < attempts && delay < backoffCeiling; i++ {
+		delay *= 2
+	}
+
+	if delay > backoffCeiling {
+		return backoffCeiling
+	}
+
+	return delay
+}
+
+func (d *Dispatcher) fanOut(ctx context.Context) {
+	for {
+		n, err := d.store.FanOut(ctx, batchSize)
+		if err != nil {
+			d.logger.Errorf("failed to fan out the outbox events: %s", err)
+			return
+		}
+
+		if n < batchSize {
+			return
+		}
+	}
+}
+
+// Deliver claims the due deliveries and sends them, a few at once, before their lease runs out.
+func (d *Dispatcher) Deliver(ctx context.Context) {
+	deliveries, err := d.store.ClaimDue(ctx, claimSize, lease)
+	if err != nil {
+		d.logger.Errorf("failed to claim the due webhook deliveries: %s", err)
+		return
+	}
+
+	slots := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for _, delivery := range deliveries {
+		delivery := delivery
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := d.attempt(ctx, delivery); err != nil {
+				d.logger.Errorf("failed to record the webhook delivery %s: %s", delivery.ID, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	errSend := d.sender.Send(ctx, delivery.Subscription, delivery.Event)
+	if errSend == nil {
+		return d.store.Delivered(ctx, delivery)
+	}
+
+	delivery.Attempts++
+	delivery.LastError = errSend.Error()
+
+	if delivery.Attempts >= maxAttempts {
+		d.logger.Warnf("webhook delivery %s to %s dead-lettered: %s", delivery.ID, delivery.Subscription.URL, errSend)
+		return d.store.DeadLetter(ctx, delivery)
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(Backoff(delivery.Attempts))
+
+	return d.store.Reschedule(ctx, delivery)
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"stocks-api/module/entities"
+)
+
+// Sender POSTs the outbox events to the subscribed URLs.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender a constructor for the Sender.
+func NewSender(client *http.Client) *Sender {
+	return &Sender{client: client}
+}
+
+// Send delivers a single event, any non 2xx response is an error.
+func (s *Sender) Send(ctx context.Context, sub *entities.WebhookSubscription, event *entities.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, event.ID.String())
+	req.Header.Set(HeaderEventType, string(event.Type))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Drain a bounded part of the body so that the connection can be reused.
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.New(fmt.Sprintf("receiver responded with status %d", res.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Headers set on every webhook delivery.
+const (
+	HeaderEventID   = "X-Stock-Event-Id"
+	HeaderEventType = "X-Stock-Event-Type"
+	HeaderTimestamp = "X-Stock-Timestamp"
+	HeaderSignature = "X-Stock-Signature"
+)
+
+// Sign computes the signature of a delivery: the hex encoded HMAC-SHA256, keyed by the subscription secret,
+// of the timestamp header value and the body, joined by a dot.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery signature in constant time, meant for receivers.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
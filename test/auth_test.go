@@ -0,0 +1,262 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/services"
+	"stocks-api/support/auth"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+)
+
+const (
+	testSecret = "test-secret"
+	testKid    = "test-key"
+	testApiKey = "sk_0a1b2c3d_secret"
+)
+
+// fakeApiKeys accepts a single API key.
+type fakeApiKeys struct{}
+
+func (f *fakeApiKeys) Authenticate(_ context.Context, plaintext string) (*entities.Principal, error) {
+	if plaintext != testApiKey {
+		return nil, errs.New(errs.Unauthenticated, "unknown api key")
+	}
+
+	return &entities.Principal{Subject: "key", Kind: entities.ApiKeyPrincipal, Roles: []string{"viewer"}}, nil
+}
+
+// authFixture an authenticator accepting HS256 tokens, and RS256 ones of the returned key.
+func authFixture(t *testing.T) (*auth.Authenticator, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		Secret:   []byte(testSecret),
+		JWKSFile: path,
+		Issuer:   "stocks-test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return auth.NewAuthenticator(logrus.New(), verifier, &fakeApiKeys{}), key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = testKid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "stocks-test",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"clerk"},
+	}
+}
+
+// TestJWTAccepted asserts that both the HS256 and the RS256 tokens resolve to their principal.
+func TestJWTAccepted(t *testing.T) {
+	a, key := authFixture(t)
+
+	tokens := map[string]string{
+		"HS256": signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims()),
+		"RS256": signToken(t, jwt.SigningMethodRS256, key, validClaims()),
+	}
+
+	for alg, token := range tokens {
+		p, err := a.Authenticate(context.Background(), token)
+		if err != nil {
+			t.Fatalf("Expected the %s token to be accepted, received %s", alg, err)
+		}
+
+		if p.Subject != "alice" || p.Kind != entities.JWTPrincipal || len(p.Roles) != 1 || p.Roles[0] != "clerk" {
+			t.Fatalf("Unexpected %s principal %+v", alg, p)
+		}
+	}
+}
+
+// TestJWTRejected asserts that the forged, expired and misissued tokens are unauthenticated.
+func TestJWTRejected(t *testing.T) {
+	a, key := authFixture(t)
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	noExpiry := validClaims()
+	delete(noExpiry, "exp")
+
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "elsewhere"
+
+	tokens := map[string]string{
+		"bad secret":   signToken(t, jwt.SigningMethodHS256, []byte("other"), validClaims()),
+		"foreign key":  signToken(t, jwt.SigningMethodRS256, other, validClaims()),
+		"expired":      signToken(t, jwt.SigningMethodRS256, key, expired),
+		"no expiry":    signToken(t, jwt.SigningMethodHS256, []byte(testSecret), noExpiry),
+		"wrong issuer": signToken(t, jwt.SigningMethodHS256, []byte(testSecret), wrongIssuer),
+		"HS512":        signToken(t, jwt.SigningMethodHS512, []byte(testSecret), validClaims()),
+		"garbage":      "not.a.token",
+		"missing":      "",
+	}
+
+	for name, token := range tokens {
+		if _, err := a.Authenticate(context.Background(), token); errs.KindOf(err) != errs.Unauthenticated {
+			t.Fatalf("Expected the %s token to be unauthenticated, received %v", name, err)
+		}
+	}
+}
+
+// TestApiKeyFormat asserts that the keys split into their prefix and secret, and that only the hash is compared.
+func TestApiKeyFormat(t *testing.T) {
+	prefix, secret, err := services.ParseApiKey(testApiKey)
+	if err != nil || prefix != "0a1b2c3d" || secret != "secret" {
+		t.Fatalf("Unexpected parse of %s: %s %s %v", testApiKey, prefix, secret, err)
+	}
+
+	for _, malformed := range []string{"sk_", "sk_prefix", "sk__secret", "pk_prefix_secret"} {
+		if _, _, err := services.ParseApiKey(malformed); errs.KindOf(err) != errs.Unauthenticated {
+			t.Fatalf("Expected %s to be malformed, received %v", malformed, err)
+		}
+	}
+
+	if hash := services.HashApiKeySecret(secret); len(hash) != 64 || hash == services.HashApiKeySecret("other") {
+		t.Fatalf("Unexpected hash %s", hash)
+	}
+}
+
+// TestApiKeyUsable asserts that the revoked and expired keys are unusable.
+func TestApiKeyUsable(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	cases := []struct {
+		name   string
+		key    entities.ApiKey
+		usable bool
+	}{
+		{"no expiry", entities.ApiKey{}, true},
+		{"not expired", entities.ApiKey{ExpiresAt: &future}, true},
+		{"expired", entities.ApiKey{ExpiresAt: &past}, false},
+		{"revoked", entities.ApiKey{RevokedAt: &past}, false},
+	}
+
+	for _, c := range cases {
+		if c.key.Usable(now) != c.usable {
+			t.Fatalf("Expected the %s key usable to be %v", c.name, c.usable)
+		}
+	}
+}
+
+// TestAuthMiddleware asserts that the routes require a bearer token or an API key, except for the public ones.
+func TestAuthMiddleware(t *testing.T) {
+	a, _ := authFixture(t)
+	s := routerFixture(server.Options{Authenticator: a})
+
+	rec := serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id")
+	decodeProblem(t, rec, http.StatusUnauthorized)
+
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatal("Expected a WWW-Authenticate challenge")
+	}
+
+	if rec = serveRoute(s, http.MethodGet, "/openapi.json"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected the OpenAPI document to be public, received %d", rec.Code)
+	}
+
+	credentials := map[string]string{
+		"Authorization": "Bearer " + signToken(t, jwt.SigningMethodHS256, []byte(testSecret), validClaims()),
+		"X-API-Key":     testApiKey,
+	}
+
+	for header, value := range credentials {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil)
+		req.Header.Set(header, value)
+
+		rec = httptest.NewRecorder()
+		s.Server.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected the %s credentials to reach the controller, received %d", header, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil)
+	req.Header.Set("Authorization", "Basic dXNyOnBhc3M=")
+
+	rec = httptest.NewRecorder()
+	s.Server.ServeHTTP(rec, req)
+	decodeProblem(t, rec, http.StatusUnauthorized)
+}
+
+// TestGrpcAuthInterceptor asserts that the calls without credentials are unauthenticated, and the others carry their principal.
+func TestGrpcAuthInterceptor(t *testing.T) {
+	a, _ := authFixture(t)
+	interceptor := rpc.UnaryAuthInterceptor(logrus.New(), a, nil)
+
+	var principal *entities.Principal
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal = entities.PrincipalFrom(ctx)
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("Expected Unauthenticated, received %s", code)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", testApiKey))
+	if _, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if principal == nil || principal.Kind != entities.ApiKeyPrincipal {
+		t.Fatalf("Expected the API key principal in the context, received %+v", principal)
+	}
+}
@@ -0,0 +1,227 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	val "github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "stocks-api/genprotos"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/services"
+	"stocks-api/module/validators"
+	server "stocks-api/support/http"
+)
+
+// TestInsertStockQuantity asserts that a stock item may be created out of stock, but not with a negative quantity.
+func TestInsertStockQuantity(t *testing.T) {
+	if err := val.New().Struct(validators.InsertStock{Name: "widget", Quantity: 0}); err != nil {
+		t.Fatalf("Expected a zero quantity to be valid, received %s", err)
+	}
+
+	if err := val.New().Struct(validators.InsertStock{Name: "widget", Quantity: -1}); err == nil {
+		t.Fatal("Expected a negative quantity to be rejected")
+	}
+}
+
+// TestInsertStockID asserts that a supplied id must be a UUIDv4, as the ids of the other requests, while the id may
+// still be left out.
+func TestInsertStockID(t *testing.T) {
+	v1 := uuid.Must(uuid.NewUUID())
+
+	ids := map[uuid.UUID]bool{uuid.Nil: true, uuid.New(): true, v1: false}
+
+	for id, valid := range ids {
+		err := val.New().Struct(validators.NewInsertStock(&entities.Stock{ID: id, Name: "widget"}))
+		if (err == nil) != valid {
+			t.Fatalf("Expected id %s to be valid: %t, received %v", id, valid, err)
+		}
+
+		if valid {
+			continue
+		}
+
+		if err := val.New().Struct(validators.GetStock{ID: id.String()}); err == nil {
+			t.Fatalf("Expected id %s to be rejected on get as well", id)
+		}
+
+		rec := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"id":"%s","name":"widget","quantity":1}`, id)
+		routerFixture(server.Options{}).Server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/stocks", strings.NewReader(body)))
+
+		if problem := decodeProblem(t, rec, http.StatusUnprocessableEntity); len(problem.InvalidParams) != 1 || problem.InvalidParams[0].Name != "id" {
+			t.Fatalf("Expected the id to be the invalid param, received %+v", problem)
+		}
+
+		_, err = handlerFixture().CreateStock(context.Background(), &pb.CreateStockRequest{
+			Stock: &pb.NewStock{Id: id.String(), Name: "widget", Quantity: 1},
+		})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument over gRPC, received %s", code)
+		}
+	}
+}
+
+// TestBatchSize asserts that the batches hold at least one item, and at most 1000.
+func TestBatchSize(t *testing.T) {
+	s := routerFixture(server.Options{})
+
+	sizes := map[string]int{"empty": 0, "oversized": 1001}
+
+	for name, size := range sizes {
+		ids := make([]string, 0, size)
+		for i := 0; i < size; i++ {
+			ids = append(ids, fmt.Sprintf("%q", uuid.NewString()))
+		}
+
+		body := fmt.Sprintf(`{"ids": [%s]}`, strings.Join(ids, ","))
+
+		rec := httptest.NewRecorder()
+		s.Server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/stocks/batch/delete", strings.NewReader(body)))
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected the %s batch to be rejected with 422, received %d", name, rec.Code)
+		}
+	}
+}
+
+// batchItems wraps stock items as the valid items of a batch.
+func batchItems(stocks ...*entities.Stock) []*entities.BatchItem {
+	items := make([]*entities.BatchItem, 0, len(stocks))
+
+	for _, stock := range stocks {
+		items = append(items, &entities.BatchItem{Stock: stock})
+	}
+
+	return items
+}
+
+// assertStocks asserts which of the stock items are stored, and their quantities.
+func assertStocks(t *testing.T, ctx context.Context, service *services.StockService, expected map[uuid.UUID]int64) {
+	t.Helper()
+
+	for id, quantity := range expected {
+		stock, err := service.GetOne(ctx, id.String())
+
+		switch {
+		case quantity < 0 && errs.KindOf(err) != errs.NotFound:
+			t.Fatalf("Expected %s not to be stored, received %v", id, err)
+		case quantity >= 0 && err != nil:
+			t.Fatalf("Expected %s to be stored, received %s", id, err)
+		case quantity >= 0 && stock.Quantity != quantity:
+			t.Fatalf("Expected %s to hold %d, received %d", id, quantity, stock.Quantity)
+		}
+	}
+}
+
+// TestBatchAllOrNothing asserts that a batch failing on a single item leaves every item as it was.
+func TestBatchAllOrNothing(t *testing.T) {
+	service := services.NewStockService(logrus.New(), dbFixture(t), context.Background())
+	ctx := tenantFixture()
+
+	existing := &entities.Stock{Name: "existing", Quantity: 5}
+	if err := service.InsertOne(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	created := &entities.Stock{ID: uuid.New(), Name: "created", Quantity: 1}
+	conflicting := &entities.Stock{ID: existing.ID, Name: "conflicting", Quantity: 1}
+
+	if _, err := service.InsertMany(ctx, batchItems(created, conflicting), false); err == nil {
+		t.Fatal("Expected the conflicting item to fail the batch")
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{created.ID: -1, existing.ID: 5})
+
+	edited := &entities.Stock{ID: existing.ID, Name: "existing", Quantity: 7}
+	missing := &entities.Stock{ID: uuid.New(), Name: "missing", Quantity: 1}
+
+	if _, err := service.UpdateMany(ctx, batchItems(edited, missing), false); errs.KindOf(err) != errs.NotFound {
+		t.Fatalf("Expected the missing item to fail the batch, received %v", err)
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{existing.ID: 5})
+
+	if _, err := service.DeleteMany(ctx, batchItems(&entities.Stock{ID: existing.ID}, missing), false); err == nil {
+		t.Fatal("Expected the missing item to fail the batch")
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{existing.ID: 5})
+
+	invalid := &entities.BatchItem{Stock: &entities.Stock{ID: uuid.New()}, Err: errs.New(errs.InvalidArgument, "invalid")}
+
+	if _, err := service.InsertMany(ctx, append(batchItems(created), invalid), false); errs.KindOf(err) != errs.InvalidArgument {
+		t.Fatalf("Expected the invalid item to fail the batch, received %v", err)
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{created.ID: -1})
+}
+
+// TestBatchBestEffort asserts that the items of a best effort batch are processed independently.
+func TestBatchBestEffort(t *testing.T) {
+	service := services.NewStockService(logrus.New(), dbFixture(t), context.Background())
+	ctx := tenantFixture()
+
+	existing := &entities.Stock{Name: "existing", Quantity: 5}
+	if err := service.InsertOne(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	created := &entities.Stock{ID: uuid.New(), Name: "created", Quantity: 1}
+	conflicting := &entities.Stock{ID: existing.ID, Name: "conflicting", Quantity: 1}
+
+	results, err := service.InsertMany(ctx, batchItems(created, conflicting), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertResults(t, results, 2, map[int]errs.Kind{1: errs.Conflict})
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{created.ID: 1, existing.ID: 5})
+
+	edited := &entities.Stock{ID: existing.ID, Name: "existing", Quantity: 7}
+	missing := &entities.Stock{ID: uuid.New(), Name: "missing", Quantity: 1}
+
+	results, err = service.UpdateMany(ctx, batchItems(missing, edited), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertResults(t, results, 2, map[int]errs.Kind{0: errs.NotFound})
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{existing.ID: 7})
+
+	results, err = service.DeleteMany(ctx, batchItems(&entities.Stock{ID: created.ID}, missing), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertResults(t, results, 2, map[int]errs.Kind{1: errs.NotFound})
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{created.ID: -1, existing.ID: 7})
+}
+
+// assertResults asserts the outcome of each item of a batch, the failed ones mapped to their error kind.
+func assertResults(t *testing.T, results entities.BatchResults, size int, failed map[int]errs.Kind) {
+	t.Helper()
+
+	if len(results) != size {
+		t.Fatalf("Expected %d results, received %d", size, len(results))
+	}
+
+	for i, result := range results {
+		kind, fails := failed[i]
+
+		switch {
+		case !fails && result.Err != nil:
+			t.Fatalf("Expected item %d to succeed, received %s", i, result.Err)
+		case fails && (result.Err == nil || errs.KindOf(result.Err) != kind):
+			t.Fatalf("Expected item %d to fail with kind %d, received %v", i, kind, result.Err)
+		}
+	}
+}
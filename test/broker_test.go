@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/support/events"
+)
+
+// fakeEventStore holds the committed stock events, signaling the replays of the subscribers.
+type fakeEventStore struct {
+	mu       sync.Mutex
+	events   []*entities.StockEvent
+	replayed chan struct{}
+}
+
+func (f *fakeEventStore) EventsSince(_ context.Context, seq int64, tenant string, _ []uuid.UUID, limit int) ([]*entities.StockEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if tenant != "" {
+		f.replayed <- struct{}{}
+	}
+
+	x := make([]*entities.StockEvent, 0)
+
+	for _, ev := range f.events {
+		if ev.Seq > seq && (tenant == "" || ev.TenantID == tenant) && len(x) < limit {
+			x = append(x, ev)
+		}
+	}
+
+	return x, nil
+}
+
+func (f *fakeEventStore) LatestSeq(context.Context) (int64, error) {
+	return 0, nil
+}
+
+// commit makes the event of the seq visible, the events being kept in seq order as the db returns them.
+func (f *fakeEventStore) commit(seq int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, &entities.StockEvent{Seq: seq, Type: entities.Created, StockID: uuid.New(), TenantID: entities.DefaultTenant})
+	sort.Slice(f.events, func(i, j int) bool { return f.events[i].Seq < f.events[j].Seq })
+}
+
+// TestBrokerOutOfOrderCommits asserts that an event committed after one of a higher seq is still delivered, once.
+func TestBrokerOutOfOrderCommits(t *testing.T) {
+	store := &fakeEventStore{replayed: make(chan struct{}, 1)}
+	broker := events.NewBroker(logrus.New(), store, nil)
+
+	store.commit(1)
+	broker.Fetch(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan int64, 10)
+
+	go broker.Watch(ctx, 1, nil, func(ev *entities.StockEvent) error {
+		received <- ev.Seq
+		return nil
+	})
+
+	<-store.replayed
+
+	// 2 and 3 are in flight, 4 commits first and 3 is rolled back.
+	store.commit(4)
+	broker.Fetch(context.Background())
+
+	store.commit(2)
+	broker.Fetch(context.Background())
+
+	store.commit(5)
+	broker.Fetch(context.Background())
+	broker.Fetch(context.Background())
+
+	expected := []int64{4, 2, 5}
+
+	for _, seq := range expected {
+		select {
+		case ev := <-received:
+			if ev != seq {
+				t.Fatalf("Expected event %d, received %d", seq, ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected event %d to be delivered", seq)
+		}
+	}
+
+	select {
+	case ev := <-received:
+		t.Fatalf("Expected every event to be delivered once, received %d again", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
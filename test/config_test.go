@@ -0,0 +1,219 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"stocks-api/support/config"
+)
+
+const testConfigYAML = `
+server:
+  port: 1000
+db:
+  host: db.internal
+  user: usr
+  password: pass
+  name: stock
+  conn_max_lifetime: 1h
+log:
+  level: debug
+features:
+  legacy_routes: false
+`
+
+const testConfigTOML = `
+[server]
+port = 1000
+
+[db]
+host = "db.internal"
+user = "usr"
+password = "pass"
+name = "stock"
+conn_max_lifetime = "1h"
+
+[log]
+level = "debug"
+
+[features]
+legacy_routes = false
+`
+
+// configFile writes a config file, named for its format.
+func configFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// configFlags the config flags, parsed from the args.
+func configFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	config.RegisterFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return flags
+}
+
+// TestConfigFile asserts that the YAML and the TOML files override the defaults alike.
+func TestConfigFile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": testConfigYAML,
+		"config.toml": testConfigTOML,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := config.Load(configFlags(t, "--config", configFile(t, name, content)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Server.Port != 1000 || cfg.DB.Host != "db.internal" || cfg.Log.Level != "debug" {
+				t.Fatalf("Expected the settings of the file, received %+v", cfg)
+			}
+
+			if cfg.DB.ConnMaxLifetime != time.Hour || cfg.Features.LegacyRoutes {
+				t.Fatalf("Expected the durations and the toggles of the file, received %+v", cfg)
+			}
+
+			if cfg.Server.Address != "localhost" || cfg.Idempotency.TTL != 24*time.Hour {
+				t.Fatalf("Expected the defaults of the settings left unset, received %+v", cfg)
+			}
+		})
+	}
+}
+
+// TestConfigPrecedence asserts that the env params override the file, and the flags override them both.
+func TestConfigPrecedence(t *testing.T) {
+	t.Setenv("CONFIG_FILE", configFile(t, "config.yaml", testConfigYAML))
+	t.Setenv("SERVER_PORT", "2000")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("KAFKA_BROKERS", "kafka-1:9092, kafka-2:9092")
+
+	cfg, err := config.Load(configFlags(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Port != 2000 {
+		t.Fatalf("Expected the port of the env param, received %d", cfg.Server.Port)
+	}
+
+	if cfg.Log.Level != "debug" {
+		t.Fatalf("Expected the empty env param to be ignored, received '%s'", cfg.Log.Level)
+	}
+
+	if len(cfg.Publisher.KafkaBrokers) != 2 || cfg.Publisher.KafkaBrokers[1] != "kafka-2:9092" {
+		t.Fatalf("Expected the brokers to be split, received %v", cfg.Publisher.KafkaBrokers)
+	}
+
+	cfg, err = config.Load(configFlags(t, "--server.port", "3000", "--features.legacy_routes", "--db.max_open_conns=5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Port != 3000 || !cfg.Features.LegacyRoutes || cfg.DB.MaxOpenConns != 5 {
+		t.Fatalf("Expected the settings of the flags, received %+v", cfg)
+	}
+}
+
+// TestConfigValidation asserts that every invalid setting is reported at once.
+func TestConfigValidation(t *testing.T) {
+	t.Setenv("CONFIG_FILE", configFile(t, "config.yaml", testConfigYAML))
+	t.Setenv("GRPC_PORT", "70000")
+	t.Setenv("DB_SSL_MODE", "sometimes")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+
+	_, err := config.Load(nil)
+	if err == nil {
+		t.Fatal("Expected the config to be invalid")
+	}
+
+	for _, path := range []string{"grpc.port", "db.ssl_mode", "tracing.sample_ratio"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Fatalf("Expected %s to be reported, received %s", path, err)
+		}
+	}
+
+	t.Setenv("GRPC_PORT", "not-a-port")
+
+	if _, err := config.Load(nil); err == nil || !strings.Contains(err.Error(), "GRPC_PORT") {
+		t.Fatalf("Expected the malformed env param to be reported, received %v", err)
+	}
+}
+
+// TestConfigUnknownSetting asserts that the misspelled settings of the file are rejected, rather than ignored.
+func TestConfigUnknownSetting(t *testing.T) {
+	path := configFile(t, "config.yaml", testConfigYAML+"\nserver:\n  prot: 1000\n")
+
+	if _, err := config.Load(configFlags(t, "--config", path)); err == nil {
+		t.Fatal("Expected the unknown setting to be rejected")
+	}
+}
+
+// TestConfigPrint asserts that the secrets are redacted from the printed config, which loads back as is.
+func TestConfigPrint(t *testing.T) {
+	t.Setenv("JWT_HS256_SECRET", "top-secret")
+
+	cfg, err := config.Load(configFlags(t, "--config", configFile(t, "config.yaml", testConfigYAML)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := config.Print(&out, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	printed := out.String()
+
+	if strings.Contains(printed, "top-secret") || strings.Contains(printed, "password: pass") {
+		t.Fatalf("Expected the secrets to be redacted, received:\n%s", printed)
+	}
+
+	if !strings.Contains(printed, "jwt_secret: '[REDACTED]'") || !strings.Contains(printed, "dsn: \"\"") {
+		t.Fatalf("Expected the secrets set to be redacted only, received:\n%s", printed)
+	}
+
+	t.Setenv("JWT_HS256_SECRET", "")
+
+	reloaded, err := config.Load(configFlags(t, "--config", configFile(t, "printed.yaml", printed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reloaded.Idempotency.TTL != cfg.Idempotency.TTL || reloaded.DB.Host != cfg.DB.Host {
+		t.Fatalf("Expected the printed config to load back, received %+v", reloaded)
+	}
+}
+
+// TestGrpcTarget asserts that the REST gateway dials the loopback address of a gRPC server bound to every interface.
+func TestGrpcTarget(t *testing.T) {
+	tests := map[string]string{
+		"":          "localhost:9999",
+		"0.0.0.0":   "localhost:9999",
+		"::":        "localhost:9999",
+		"localhost": "localhost:9999",
+		"10.0.0.5":  "10.0.0.5:9999",
+	}
+
+	for address, target := range tests {
+		if received := (config.GRPC{Address: address, Port: 9999}).Target(); received != target {
+			t.Fatalf("Expected target %s of '%s', received %s", target, address, received)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+)
+
+var (
+	testDB     *db.Instance
+	testDBErr  error
+	testDBOnce sync.Once
+)
+
+// dbFixture the migrated test database of the db_test container, as set in .env.test, the test being skipped
+// when it isn't up.
+func dbFixture(t *testing.T) *db.Instance {
+	testDBOnce.Do(func() {
+		testDB, testDBErr = connectTestDB()
+	})
+
+	if testDBErr != nil {
+		t.Skipf("test database unavailable, run `make start`: %s", testDBErr)
+	}
+
+	return testDB
+}
+
+func connectTestDB() (*db.Instance, error) {
+	env, err := godotenv.Read("../.env.test")
+	if err != nil {
+		if env, err = godotenv.Read("../.env.test.dist"); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := config.Default()
+	cfg.DB.Host = env["DB_HOST"]
+	cfg.DB.User = env["DB_USER"]
+	cfg.DB.Password = env["DB_PASS"]
+	cfg.DB.Name = env["DB_NAME"]
+
+	if cfg.DB.Port, err = strconv.Atoi(env["DB_PORT"]); err != nil {
+		return nil, err
+	}
+
+	conn, err := db.NewConnection(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	l := logrus.New()
+	instance := db.NewInstance(conn, l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := instance.Health(ctx); err != nil {
+		return nil, err
+	}
+
+	migrator, err := db.FromInstance(l, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrator.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := migrator.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// tenantFixture the context of a tenant of its own, isolating the records of a test from the others.
+func tenantFixture() context.Context {
+	tenant := fmt.Sprintf("test-%s", strings.ReplaceAll(uuid.NewString(), "-", "")[:12])
+
+	return entities.WithTenant(context.Background(), tenant)
+}
+
+// settingTx runs fn in a transaction with a setting of the row-level security policies set, app.tenant_id to see the rows
+// of a tenant, or app.all_tenants to see all of them as the background jobs do.
+func settingTx(ctx context.Context, instance *db.Instance, setting string, value string, fn func(tx bun.Tx) error) error {
+	return instance.Base.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config(?, ?, true)", setting, value); err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
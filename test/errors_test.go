@@ -0,0 +1,115 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "stocks-api/genprotos"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/handlers"
+	"stocks-api/module/services"
+)
+
+func handlerFixture() *handlers.StockHandler {
+	return handlers.NewStockHandler(logrus.New(), nil, context.Background(), &fakeWatcher{}, policyFixture())
+}
+
+// TestGrpcInvalidArgument asserts that the validation failures carry the violated fields.
+func TestGrpcInvalidArgument(t *testing.T) {
+	_, err := handlerFixture().GetStock(context.Background(), &pb.GetStockRequest{Id: "not-an-id"})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s", st.Code())
+	}
+
+	if len(st.Details()) != 1 {
+		t.Fatalf("Expected 1 detail, received %d", len(st.Details()))
+	}
+
+	detail, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(detail.GetFieldViolations()) != 1 || detail.GetFieldViolations()[0].GetField() != "id" {
+		t.Fatalf("Expected a violation of the id field, received %v", st.Details()[0])
+	}
+}
+
+// TestGrpcNegativeQuantity asserts that a negative quantity is rejected as an invalid argument before reaching the db.
+func TestGrpcNegativeQuantity(t *testing.T) {
+	_, err := handlerFixture().CreateStock(context.Background(), &pb.CreateStockRequest{
+		Stock: &pb.NewStock{Name: "widget", Quantity: -1},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s: %s", st.Code(), st.Message())
+	}
+
+	detail, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || detail.GetFieldViolations()[0].GetField() != "quantity" {
+		t.Fatalf("Expected a violation of the quantity field, received %v", st.Details())
+	}
+}
+
+// TestCheckNegativeQuantity asserts that the service rejects a negative quantity as an invalid argument.
+func TestCheckNegativeQuantity(t *testing.T) {
+	err := services.NewStockService(logrus.New(), nil, context.Background()).Check(&entities.Stock{Name: "widget", Quantity: -1})
+
+	if errs.KindOf(err) != errs.InvalidArgument || errs.FieldOf(err) != "quantity" {
+		t.Fatalf("Expected an invalid quantity, received %v", err)
+	}
+}
+
+// TestGrpcMissingId asserts that a delete without an id is an invalid argument.
+func TestGrpcMissingId(t *testing.T) {
+	_, err := handlerFixture().DeleteStock(context.Background(), &pb.DeleteStockRequest{})
+
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s", code)
+	}
+}
+
+// TestGrpcInvalidClientId asserts that an id supplied on create must be a UUID.
+func TestGrpcInvalidClientId(t *testing.T) {
+	_, err := handlerFixture().CreateStock(context.Background(), &pb.CreateStockRequest{
+		Stock: &pb.NewStock{Id: "not-an-id", Name: "widget", Quantity: 1},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s: %s", st.Code(), st.Message())
+	}
+
+	detail, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || detail.GetFieldViolations()[0].GetField() != "id" {
+		t.Fatalf("Expected a violation of the id field, received %v", st.Details())
+	}
+}
+
+// TestGrpcInvalidPagination asserts that a page out of the bounds of the pagination is an invalid argument.
+func TestGrpcInvalidPagination(t *testing.T) {
+	cases := map[string]*pb.Pagination{
+		"page 0":                {Page: 0, ItemsPerPage: 10},
+		"0 items per page":      {Page: 1, ItemsPerPage: 0},
+		"over 100 items a page": {Page: 1, ItemsPerPage: 101},
+	}
+
+	for name, pagination := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := handlerFixture().ListStocks(context.Background(), &pb.ListStocksRequest{Pagination: pagination})
+
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, received %s: %s", st.Code(), st.Message())
+			}
+
+			if _, ok := st.Details()[0].(*errdetails.BadRequest); !ok {
+				t.Fatalf("Expected the violated fields, received %v", st.Details())
+			}
+		})
+	}
+}
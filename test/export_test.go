@@ -0,0 +1,88 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"stocks-api/module/entities"
+	"stocks-api/module/exporters"
+)
+
+func exportFixtures() []*entities.Stock {
+	createdAt := time.Date(2022, 9, 7, 15, 21, 50, 0, time.UTC)
+
+	return []*entities.Stock{
+		{ID: uuid.New(), Name: "widget", Quantity: 10, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: uuid.New(), Name: "gadget", Quantity: 3, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+}
+
+func encodeAll(t *testing.T, format exporters.Format, stocks []*entities.Stock) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+
+	encoder, err := exporters.NewEncoder(format, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range stocks {
+		if err := encoder.Encode(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+// TestExportLineFormats asserts that the CSV and NDJSON encoders write one line per item.
+func TestExportLineFormats(t *testing.T) {
+	stocks := exportFixtures()
+
+	csvLines := strings.Split(strings.TrimSpace(encodeAll(t, exporters.CSV, stocks).String()), "\n")
+	if len(csvLines) != 3 || csvLines[0] != "id,name,quantity,created_at,updated_at" {
+		t.Fatalf("Expected a header and 2 rows, received: %v", csvLines)
+	}
+
+	if !strings.HasPrefix(csvLines[1], stocks[0].ID.String()+",widget,10,2022-09-07T15:21:50Z") {
+		t.Fatalf("Unexpected CSV row: %s", csvLines[1])
+	}
+
+	jsonLines := strings.Split(strings.TrimSpace(encodeAll(t, exporters.NDJSON, stocks).String()), "\n")
+	if len(jsonLines) != 2 || !strings.Contains(jsonLines[1], `"name":"gadget"`) {
+		t.Fatalf("Expected 2 JSON lines, received: %v", jsonLines)
+	}
+}
+
+// TestExportParquet asserts that the parquet output can be read back.
+func TestExportParquet(t *testing.T) {
+	stocks := exportFixtures()
+	buf := encodeAll(t, exporters.Parquet, stocks)
+
+	type row struct {
+		ID        string    `parquet:"id"`
+		Name      string    `parquet:"name"`
+		Quantity  int64     `parquet:"quantity"`
+		CreatedAt time.Time `parquet:"created_at"`
+	}
+
+	rows, err := parquet.Read[row](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 2 || rows[1].Name != "gadget" || rows[1].Quantity != 3 {
+		t.Fatalf("Unexpected parquet rows: %v", rows)
+	}
+
+	if !rows[0].CreatedAt.Equal(stocks[0].CreatedAt) {
+		t.Fatalf("Expected timestamp %s, received %s", stocks[0].CreatedAt, rows[0].CreatedAt)
+	}
+}
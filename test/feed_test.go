@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/controllers"
+	"stocks-api/module/entities"
+)
+
+// fakeWatcher replays fixed events, recording the requested resume point and filters.
+type fakeWatcher struct {
+	fromSeq  int64
+	stockIDs []uuid.UUID
+	events   []*entities.StockEvent
+	err      error
+}
+
+func (f *fakeWatcher) Watch(_ context.Context, fromSeq int64, ids []uuid.UUID, fn func(*entities.StockEvent) error) error {
+	f.fromSeq = fromSeq
+	f.stockIDs = ids
+
+	for _, ev := range f.events {
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+
+	return f.err
+}
+
+func feedFixture() (*fakeWatcher, uuid.UUID) {
+	id := uuid.New()
+
+	return &fakeWatcher{events: []*entities.StockEvent{
+		{Seq: 6, Type: entities.Updated, StockID: id, Stock: &entities.Stock{ID: id, Name: "widget", Quantity: 4}},
+		{Seq: 7, Type: entities.Deleted, StockID: id, Stock: &entities.Stock{ID: id, Name: "widget"}},
+	}}, id
+}
+
+// TestFeedSSE asserts that the SSE feed resumes from Last-Event-ID and writes one event per change.
+func TestFeedSSE(t *testing.T) {
+	watcher, id := feedFixture()
+	feed := controllers.NewFeedController(logrus.New(), watcher, policyFixture())
+
+	req := httptest.NewRequest("GET", "/events?stock_id="+id.String(), nil)
+	req.Header.Set("Last-Event-ID", "5")
+	rec := httptest.NewRecorder()
+
+	feed.Stream(rec, req)
+
+	if watcher.fromSeq != 5 || len(watcher.stockIDs) != 1 || watcher.stockIDs[0] != id {
+		t.Fatalf("Expected resume from 5 filtered by %s, received %d %v", id, watcher.fromSeq, watcher.stockIDs)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Unexpected content type: %s", ct)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "id: 6\nevent: UPDATED\ndata: {") || !strings.Contains(body, "id: 7\nevent: DELETED\n") {
+		t.Fatalf("Unexpected SSE body: %s", body)
+	}
+}
+
+// TestFeedSSEInternalError asserts that the SSE feed ends with an error event withholding the internal details.
+func TestFeedSSEInternalError(t *testing.T) {
+	watcher, _ := feedFixture()
+	watcher.err = errors.New("pq: password authentication failed for user \"stocks\"")
+	feed := controllers.NewFeedController(logrus.New(), watcher, policyFixture())
+
+	rec := httptest.NewRecorder()
+	feed.Stream(rec, httptest.NewRequest("GET", "/events", nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: error\ndata: ") || strings.Contains(body, "pq:") {
+		t.Fatalf("Expected an error event without the internal details, received %s", body)
+	}
+}
+
+// TestFeedSSEInvalidFilter asserts that malformed filters are rejected before streaming.
+func TestFeedSSEInvalidFilter(t *testing.T) {
+	feed := controllers.NewFeedController(logrus.New(), &fakeWatcher{}, policyFixture())
+
+	rec := httptest.NewRecorder()
+	feed.Stream(rec, httptest.NewRequest("GET", "/events?stock_id=nope", nil))
+
+	if rec.Code != 400 {
+		t.Fatalf("Expected 400, received %d", rec.Code)
+	}
+}
+
+// TestFeedWebSocket asserts that the WebSocket feed sends one JSON message per change.
+func TestFeedWebSocket(t *testing.T) {
+	watcher, _ := feedFixture()
+	feed := controllers.NewFeedController(logrus.New(), watcher, policyFixture())
+
+	srv := httptest.NewServer(http.HandlerFunc(feed.Socket))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?from_sequence=5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, expected := range watcher.events {
+		received := entities.StockEvent{}
+		if err := conn.ReadJSON(&received); err != nil {
+			t.Fatal(err)
+		}
+
+		if received.Seq != expected.Seq || received.Type != expected.Type {
+			t.Fatalf("Expected event %d %s, received %d %s", expected.Seq, expected.Type, received.Seq, received.Type)
+		}
+	}
+
+	if watcher.fromSeq != 5 {
+		t.Fatalf("Expected resume from 5, received %d", watcher.fromSeq)
+	}
+}
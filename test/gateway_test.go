@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	pb "stocks-api/genprotos"
+	"stocks-api/support/gateway"
+	server "stocks-api/support/http"
+)
+
+// gatewayFixture serves the stock handler over gRPC, on a random port, behind the REST gateway.
+func gatewayFixture(t *testing.T) http.Handler {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterStockServiceServer(server, handlerFixture())
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	gw, err := gateway.NewGateway(ctx, logrus.New(), lis.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gw
+}
+
+// TestGatewayProblems asserts that the gateway maps the gRPC errors to the REST problem responses.
+func TestGatewayProblems(t *testing.T) {
+	gw := gatewayFixture(t)
+
+	rec := httptest.NewRecorder()
+	gw.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/stocks/not-an-id", nil))
+
+	body := decodeProblem(t, rec, http.StatusUnprocessableEntity)
+	if body.Type != "/problems/validation" || len(body.InvalidParams) != 1 || body.InvalidParams[0].Name != "id" {
+		t.Fatalf("Expected id as invalid param, received %v", body.InvalidParams)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/stocks", strings.NewReader(`{"name":"widget","quantity":-1}`)))
+
+	body = decodeProblem(t, rec, http.StatusUnprocessableEntity)
+	if body.Type != "/problems/validation" || len(body.InvalidParams) != 1 || body.InvalidParams[0].Name != "quantity" {
+		t.Fatalf("Expected quantity as invalid param, received %v", body.InvalidParams)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/stocks", strings.NewReader(`{"name":`)))
+
+	body = decodeProblem(t, rec, http.StatusBadRequest)
+	if body.Type != "/problems/malformed-request" {
+		t.Fatalf("Expected a malformed request, received %s", body.Type)
+	}
+}
+
+// TestOpenAPIDocument asserts that the generated document is served and lists the gateway routes.
+func TestOpenAPIDocument(t *testing.T) {
+	rec := serveRoute(routerFixture(server.Options{}), http.MethodGet, "/openapi.json")
+
+	doc := struct {
+		Paths map[string]interface{} `json:"paths"`
+	}{}
+
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := doc.Paths["/v1/stocks/{id}"]; !ok {
+		t.Fatalf("Expected the stock routes, received %v", doc.Paths)
+	}
+}
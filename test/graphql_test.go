@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/controllers"
+)
+
+func graphqlRequest(t *testing.T, controller *controllers.GraphQLController, query string, accept string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Accept", accept)
+
+	rec := httptest.NewRecorder()
+	controller.Serve(rec, req)
+
+	return rec
+}
+
+// TestGraphqlErrorCodes asserts that the domain errors are classified in the error extensions.
+func TestGraphqlErrorCodes(t *testing.T) {
+	controller := controllers.NewGraphQLController(logrus.New(), nil, context.Background(), &fakeWatcher{}, policyFixture())
+
+	rec := graphqlRequest(t, controller, `{ stocks(page: 0) { totalCount } }`, "application/json")
+
+	res := struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}{}
+
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != "INVALID_ARGUMENT" {
+		t.Fatalf("Expected an INVALID_ARGUMENT error, received %v", res.Errors)
+	}
+}
+
+// TestGraphqlSubscription asserts that the subscriptions are streamed from the change feed over SSE.
+func TestGraphqlSubscription(t *testing.T) {
+	watcher, id := feedFixture()
+	controller := controllers.NewGraphQLController(logrus.New(), nil, context.Background(), watcher, policyFixture())
+
+	query := fmt.Sprintf(`subscription { stockChanged(fromSequence: 5, stockIds: ["%s"]) { sequence type stock { name } } }`, id)
+	rec := graphqlRequest(t, controller, query, "text/event-stream")
+
+	body := rec.Body.String()
+
+	if strings.Count(body, "event: next\n") != 2 || !strings.Contains(body, "event: complete\n") {
+		t.Fatalf("Expected 2 events and completion, received: %s", body)
+	}
+
+	if !strings.Contains(body, `"stockChanged":{"sequence":6,"type":"UPDATED","stock":{"name":"widget"}}`) {
+		t.Fatalf("Unexpected first event: %s", body)
+	}
+
+	if watcher.fromSeq != 5 || len(watcher.stockIDs) != 1 || watcher.stockIDs[0] != id {
+		t.Fatalf("Expected the subscription arguments to reach the watcher, received %d %v", watcher.fromSeq, watcher.stockIDs)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpc "stocks-api/support/grpc"
+	"stocks-api/support/health"
+	server "stocks-api/support/http"
+)
+
+// checkerFixture a checker of a single check, failing with the given error.
+func checkerFixture(err *error) *health.Checker {
+	return health.NewChecker(logrus.New(), health.Check{
+		Name: "db",
+		Fn: func(ctx context.Context) error {
+			return *err
+		},
+	})
+}
+
+func decodeResult(t *testing.T, rec *httptest.ResponseRecorder, status int) health.Result {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("Expected status %d, received %d: %s", status, rec.Code, rec.Body.String())
+	}
+
+	var result health.Result
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func servingStatus(t *testing.T, checker *health.Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	res, err := checker.GRPC().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.Status
+}
+
+// TestReadiness asserts that the servers are ready once started, while their checks pass, until they shut down.
+func TestReadiness(t *testing.T) {
+	var failure error
+	checker := checkerFixture(&failure)
+	a, _ := authFixture(t)
+	s := routerFixture(server.Options{Authenticator: a, Checker: checker})
+
+	decodeResult(t, serveRoute(s, http.MethodGet, "/readyz"), http.StatusServiceUnavailable)
+	if status := servingStatus(t, checker, ""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Expected NOT_SERVING before startup, received %s", status)
+	}
+
+	checker.SetReady(true)
+
+	result := decodeResult(t, serveRoute(s, http.MethodGet, "/readyz"), http.StatusOK)
+	if result.Status != "ok" || result.Checks["db"] != "ok" {
+		t.Fatalf("Expected the checks to pass, received %+v", result)
+	}
+
+	for _, service := range []string{"", "stocks.StockService"} {
+		if status := servingStatus(t, checker, service); status != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("Expected '%s' SERVING, received %s", service, status)
+		}
+	}
+
+	failure = errors.New("connection refused")
+
+	result = decodeResult(t, serveRoute(s, http.MethodGet, "/readyz"), http.StatusServiceUnavailable)
+	if result.Checks["db"] != "failed" {
+		t.Fatalf("Expected the check to fail without its reason, received %+v", result)
+	}
+
+	decodeResult(t, serveRoute(s, http.MethodGet, "/healthz"), http.StatusOK)
+
+	failure = nil
+	checker.Shutdown()
+
+	decodeResult(t, serveRoute(s, http.MethodGet, "/readyz"), http.StatusServiceUnavailable)
+	if status := servingStatus(t, checker, ""); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Expected NOT_SERVING after shutdown, received %s", status)
+	}
+}
+
+// TestGrpcHealthIsPublic asserts that the gRPC health service doesn't require credentials.
+func TestGrpcHealthIsPublic(t *testing.T) {
+	a, _ := authFixture(t)
+	interceptor := rpc.UnaryAuthInterceptor(logrus.New(), a, nil)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/GetStock"}
+	if _, err := interceptor(context.Background(), nil, info, handler); err == nil {
+		t.Fatal("Expected the stock service to require credentials")
+	}
+}
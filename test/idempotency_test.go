@@ -0,0 +1,251 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	pb "stocks-api/genprotos"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+	"stocks-api/support/idempotency"
+)
+
+// fakeRecordStore keeps the idempotency records in memory, by tenant and key.
+type fakeRecordStore struct {
+	mu      sync.Mutex
+	records map[string]*entities.IdempotencyRecord
+}
+
+func newFakeRecordStore() *fakeRecordStore {
+	return &fakeRecordStore{records: map[string]*entities.IdempotencyRecord{}}
+}
+
+func (f *fakeRecordStore) id(ctx context.Context, key string) string {
+	return entities.TenantFrom(ctx) + "/" + key
+}
+
+func (f *fakeRecordStore) Claim(ctx context.Context, record *entities.IdempotencyRecord, lock time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if held, ok := f.records[f.id(ctx, record.Key)]; ok {
+		stale := !held.Completed() && held.CreatedAt.Before(now.Add(-lock))
+		if !held.ExpiresAt.Before(now) && !stale {
+			return false, nil
+		}
+	}
+
+	record.TenantID = entities.TenantFrom(ctx)
+	record.CreatedAt = now
+	f.records[f.id(ctx, record.Key)] = record
+
+	return true, nil
+}
+
+func (f *fakeRecordStore) Find(ctx context.Context, key string) (*entities.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	record, ok := f.records[f.id(ctx, key)]
+	if !ok {
+		return nil, errs.New(errs.NotFound, "no record")
+	}
+
+	return record, nil
+}
+
+func (f *fakeRecordStore) Complete(ctx context.Context, record *entities.IdempotencyRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	held, ok := f.records[f.id(ctx, record.Key)]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	held.Status, held.Headers, held.Response, held.CompletedAt = record.Status, record.Headers, record.Response, &now
+
+	return nil
+}
+
+func (f *fakeRecordStore) Release(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if held, ok := f.records[f.id(ctx, key)]; ok && !held.Completed() {
+		delete(f.records, f.id(ctx, key))
+	}
+
+	return nil
+}
+
+func (f *fakeRecordStore) Purge(_ context.Context, before time.Time) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var purged int64
+	for id, record := range f.records {
+		if record.ExpiresAt.Before(before) {
+			delete(f.records, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func guardFixture(store *fakeRecordStore) *idempotency.Guard {
+	return idempotency.NewGuard(logrus.New(), store, time.Hour)
+}
+
+// TestIdempotencyGuard asserts that a key is served once, replayed to the same request and refused to the others.
+func TestIdempotencyGuard(t *testing.T) {
+	g := guardFixture(newFakeRecordStore())
+	ctx := context.Background()
+
+	record, err := g.Begin(ctx, "key-1", "request-a")
+	if err != nil || record != nil {
+		t.Fatalf("Expected the first request to be served, received %v, %v", record, err)
+	}
+
+	if _, err = g.Begin(ctx, "key-1", "request-a"); errs.KindOf(err) != errs.Aborted {
+		t.Fatalf("Expected the retry of a request in progress to be aborted, received %v", err)
+	}
+
+	g.Complete(ctx, "key-1", http.StatusCreated, nil, []byte("created"))
+
+	record, err = g.Begin(ctx, "key-1", "request-a")
+	if err != nil || record == nil || record.Status != http.StatusCreated || string(record.Response) != "created" {
+		t.Fatalf("Expected the stored outcome to be replayed, received %+v, %v", record, err)
+	}
+
+	_, err = g.Begin(ctx, "key-1", "request-b")
+	if errs.KindOf(err) != errs.InvalidArgument || errs.FieldOf(err) != "idempotency_key" {
+		t.Fatalf("Expected a different request to be rejected, received %v", err)
+	}
+
+	record, err = g.Begin(entities.WithTenant(ctx, "acme"), "key-1", "request-b")
+	if err != nil || record != nil {
+		t.Fatalf("Expected the keys to be scoped to their tenant, received %v, %v", record, err)
+	}
+
+	for _, key := range []string{"", "with space", strings.Repeat("k", 256)} {
+		if _, err = g.Begin(ctx, key, "request-a"); errs.KindOf(err) != errs.InvalidArgument {
+			t.Fatalf("Expected key '%s' to be rejected, received %v", key, err)
+		}
+	}
+}
+
+// TestIdempotencyRelease asserts that a failed request frees its key for the retries.
+func TestIdempotencyRelease(t *testing.T) {
+	g := guardFixture(newFakeRecordStore())
+	ctx := context.Background()
+
+	if _, err := g.Begin(ctx, "key-1", "request-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	g.Release(ctx, "key-1")
+
+	if record, err := g.Begin(ctx, "key-1", "request-b"); err != nil || record != nil {
+		t.Fatalf("Expected the released key to be served again, received %v, %v", record, err)
+	}
+}
+
+func serveIdempotent(s *server.Serve, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/stocks", strings.NewReader(body))
+	req.Header.Set(idempotency.Header, key)
+
+	rec := httptest.NewRecorder()
+	s.Server.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// TestIdempotencyMiddleware asserts that the stored responses are replayed, and the failed requests not stored.
+func TestIdempotencyMiddleware(t *testing.T) {
+	store := newFakeRecordStore()
+	s := routerFixture(server.Options{Idempotency: guardFixture(store)})
+
+	// A malformed body fails before reaching the db, releasing the key.
+	decodeProblem(t, serveIdempotent(s, "key-1", "{"), http.StatusBadRequest)
+
+	if len(store.records) != 0 {
+		t.Fatalf("Expected the failed request to release its key, %d records stored", len(store.records))
+	}
+
+	body := `{"name":"Widget","quantity":5}`
+	ctx := context.Background()
+	g := guardFixture(store)
+
+	fingerprint := idempotency.Fingerprint([]byte(http.MethodPost), []byte("/api/v1/stocks"), []byte(body))
+	if _, err := g.Begin(ctx, "key-2", fingerprint); err != nil {
+		t.Fatal(err)
+	}
+
+	g.Complete(ctx, "key-2", http.StatusCreated, map[string][]string{"Content-Type": {"application/json"}}, []byte(`{"id":"1"}`))
+
+	rec := serveIdempotent(s, "key-2", body)
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"id":"1"}` {
+		t.Fatalf("Expected the stored response, received %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec.Header().Get(idempotency.ReplayedHeader) != "true" || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected the stored headers and the replayed flag, received %v", rec.Header())
+	}
+
+	decodeProblem(t, serveIdempotent(s, "key-2", `{"name":"Gadget","quantity":5}`), http.StatusBadRequest)
+}
+
+// TestGrpcIdempotencyInterceptor asserts that the retried calls are answered with the stored response.
+func TestGrpcIdempotencyInterceptor(t *testing.T) {
+	interceptor := rpc.UnaryIdempotencyInterceptor(logrus.New(), guardFixture(newFakeRecordStore()))
+	info := &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/BatchCreateStocks"}
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &pb.BatchCreateStocksResponse{Results: []*pb.BatchItemResult{{Index: 0, Id: "1"}}}, nil
+	}
+
+	req := &pb.BatchCreateStocksRequest{Stocks: []*pb.NewStock{{Name: "Widget", Quantity: 5}}}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotency.MetadataKey, "key-1"))
+
+	first, err := interceptor(ctx, req, info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := interceptor(ctx, req, info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected a single call to be served, served %d", calls)
+	}
+
+	if !proto.Equal(first.(proto.Message), replayed.(proto.Message)) {
+		t.Fatalf("Expected the stored response, received %v", replayed)
+	}
+
+	other := &pb.BatchCreateStocksRequest{Stocks: []*pb.NewStock{{Name: "Gadget", Quantity: 5}}}
+	if _, err = interceptor(ctx, other, info, handler); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s", status.Code(err))
+	}
+}
@@ -0,0 +1,170 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/entities"
+	"stocks-api/module/importers"
+	"stocks-api/module/services"
+)
+
+// TestParseMapping asserts that custom columns override the default field names.
+func TestParseMapping(t *testing.T) {
+	mapping, err := importers.ParseMapping("name=Product, quantity = Qty")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mapping[importers.FieldName] != "Product" || mapping[importers.FieldQuantity] != "Qty" {
+		t.Fatalf("Expected custom columns, received: %v", mapping)
+	}
+
+	if mapping[importers.FieldID] != importers.FieldID {
+		t.Fatalf("Expected default id column, received: %s", mapping[importers.FieldID])
+	}
+
+	if _, err := importers.ParseMapping("sku=Code"); err == nil {
+		t.Fatal("Expected unknown field to be rejected")
+	}
+}
+
+// TestReadCSVAndRejects asserts that a CSV is parsed and rejects are written with their reason.
+func TestReadCSVAndRejects(t *testing.T) {
+	sheet, err := importers.ReadCSV(strings.NewReader("Product,Qty\nwidget,10\ngadget,-1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sheet.Header) != 2 || len(sheet.Records) != 2 {
+		t.Fatalf("Expected 1 header and 2 records, received: %v %v", sheet.Header, sheet.Records)
+	}
+
+	report := &importers.Report{
+		Header: sheet.Header,
+		Rows: []*importers.PlannedRow{
+			{Line: 2, Values: sheet.Records[0], Action: importers.Create, Stock: &entities.Stock{Name: "widget"}},
+			{Line: 3, Values: sheet.Records[1], Action: importers.Reject, Err: errors.New("Input quantity is less than 0")},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := report.WriteRejects(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Product,Qty,error\ngadget,-1,Input quantity is less than 0\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected rejects %q, received %q", expected, buf.String())
+	}
+}
+
+// TestImportMatchesByID asserts that the rows carrying the id of a stored stock item update it, renamed or not.
+func TestImportMatchesByID(t *testing.T) {
+	instance := dbFixture(t)
+	ctx := tenantFixture()
+
+	service := services.NewStockService(logrus.New(), instance, context.Background())
+
+	existing := &entities.Stock{Name: "widget", Quantity: 5}
+	other := &entities.Stock{Name: "gadget", Quantity: 1}
+
+	for _, stock := range []*entities.Stock{existing, other} {
+		if err := service.InsertOne(ctx, stock); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sheet, err := importers.ReadCSV(strings.NewReader(fmt.Sprintf(
+		"id,name,quantity\n%s,sprocket,5\n%s,gadget,2\n,gadget,3\n",
+		existing.ID, uuid.New(),
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, _ := importers.ParseMapping("")
+	importer := importers.NewStockImporter(logrus.New(), instance, context.Background())
+
+	report, err := importer.Plan(ctx, sheet, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := []importers.Action{importers.Update, importers.Reject, importers.Update}
+	for i, action := range actions {
+		if report.Rows[i].Action != action {
+			t.Fatalf("Expected line %d to be planned for %s, received %s (%v)", i+2, action, report.Rows[i].Action, report.Rows[i].Err)
+		}
+	}
+
+	if err := importer.Apply(ctx, report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Count(importers.Reject) != 1 {
+		t.Fatalf("Expected the rename to be applied, received %d rejects", report.Count(importers.Reject))
+	}
+
+	renamed, err := service.GetOne(ctx, existing.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if renamed.Name != "sprocket" {
+		t.Fatalf("Expected the stock item to be renamed, received '%s'", renamed.Name)
+	}
+}
+
+// TestImportWithoutQuantity asserts that the rows without a quantity keep the stored quantity of the stock item they
+// update, and create theirs at 0.
+func TestImportWithoutQuantity(t *testing.T) {
+	instance := dbFixture(t)
+	ctx := tenantFixture()
+
+	service := services.NewStockService(logrus.New(), instance, context.Background())
+
+	existing := &entities.Stock{Name: "widget", Quantity: 5}
+	if err := service.InsertOne(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := importers.ReadCSV(strings.NewReader(fmt.Sprintf("id,name\n%s,sprocket\n,gadget\n", existing.ID)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, _ := importers.ParseMapping("")
+	importer := importers.NewStockImporter(logrus.New(), instance, context.Background())
+
+	report, err := importer.Plan(ctx, sheet, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := importer.Apply(ctx, report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Count(importers.Update) != 1 || report.Count(importers.Create) != 1 {
+		t.Fatalf("Expected 1 update and 1 create, received %d rejects", report.Count(importers.Reject))
+	}
+
+	renamed, err := service.GetOne(ctx, existing.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if renamed.Name != "sprocket" || renamed.Quantity != 5 {
+		t.Fatalf("Expected sprocket with its quantity of 5, received '%s' with %d", renamed.Name, renamed.Quantity)
+	}
+
+	created := report.Rows[1].Stock
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{created.ID: 0})
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/support/config"
+	server "stocks-api/support/http"
+	"stocks-api/support/lifecycle"
+)
+
+// fakeServer a Server serving until shut down, recording its calls.
+type fakeServer struct {
+	name      string
+	listenErr error
+	serveErr  error
+	// stuck ignores the shutdown until its context is done.
+	stuck   bool
+	calls   *[]string
+	mu      *sync.Mutex
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func newFakeServer(name string, calls *[]string, mu *sync.Mutex) *fakeServer {
+	return &fakeServer{name: name, calls: calls, mu: mu, stopped: make(chan struct{})}
+}
+
+func (f *fakeServer) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	*f.calls = append(*f.calls, fmt.Sprintf("%s %s", call, f.name))
+}
+
+func (f *fakeServer) Listen() error {
+	f.record("listen")
+	return f.listenErr
+}
+
+func (f *fakeServer) Serve() error {
+	f.record("serve")
+
+	if f.serveErr != nil {
+		return f.serveErr
+	}
+
+	<-f.stopped
+
+	return nil
+}
+
+func (f *fakeServer) Shutdown(ctx context.Context) error {
+	f.record("shutdown")
+	defer f.once.Do(func() { close(f.stopped) })
+
+	if f.stuck {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (f *fakeServer) called(t *testing.T, call string) bool {
+	t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range *f.calls {
+		if c == fmt.Sprintf("%s %s", call, f.name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TestLifecycleShutdown asserts that the servers drain, the workers stop and the resources are released in the reverse order.
+func TestLifecycleShutdown(t *testing.T) {
+	var calls []string
+	mu := &sync.Mutex{}
+
+	m := lifecycle.NewManager(logrus.New(), time.Second)
+	httpServer, grpcServer := newFakeServer("http", &calls, mu), newFakeServer("grpc", &calls, mu)
+	m.Serve("http", httpServer)
+	m.Serve("grpc", grpcServer)
+
+	workerStopped := make(chan struct{})
+	m.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(workerStopped)
+		return nil
+	})
+
+	var closed []string
+	m.Close("db", func(ctx context.Context) error {
+		closed = append(closed, "db")
+		return nil
+	})
+	m.Close("tracing", func(ctx context.Context) error {
+		closed = append(closed, "tracing")
+		return errors.New("exporter unreachable")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ready, draining := false, false
+	m.OnReady(func() {
+		ready = true
+		cancel()
+	})
+	m.OnShutdown(func() {
+		draining = true
+	})
+
+	if err := m.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ready || !draining {
+		t.Fatalf("Expected the ready and shutdown hooks to run, received ready %t, draining %t", ready, draining)
+	}
+
+	for _, s := range []*fakeServer{httpServer, grpcServer} {
+		if !s.called(t, "listen") || !s.called(t, "shutdown") {
+			t.Fatalf("Expected the %s server to listen and shut down, received %v", s.name, calls)
+		}
+	}
+
+	select {
+	case <-workerStopped:
+	default:
+		t.Fatal("Expected the worker to stop")
+	}
+
+	if len(closed) != 2 || closed[0] != "tracing" || closed[1] != "db" {
+		t.Fatalf("Expected the resources released in the reverse order, received %v", closed)
+	}
+}
+
+// TestLifecycleServerFailure asserts that a failing server shuts the others down, and its error is returned.
+func TestLifecycleServerFailure(t *testing.T) {
+	var calls []string
+	mu := &sync.Mutex{}
+
+	m := lifecycle.NewManager(logrus.New(), time.Second)
+	failing, healthy := newFakeServer("http", &calls, mu), newFakeServer("grpc", &calls, mu)
+	failing.serveErr = errors.New("address already in use")
+	m.Serve("http", failing)
+	m.Serve("grpc", healthy)
+
+	closed := false
+	m.Close("db", func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	err := m.Run(context.Background())
+	if !errors.Is(err, failing.serveErr) {
+		t.Fatalf("Expected the error of the failing server, received %v", err)
+	}
+
+	if !healthy.called(t, "shutdown") {
+		t.Fatalf("Expected the healthy server to shut down, received %v", calls)
+	}
+
+	if !closed {
+		t.Fatal("Expected the resources to be released")
+	}
+}
+
+// TestLifecycleListenFailure asserts that no server serves unless they all listen.
+func TestLifecycleListenFailure(t *testing.T) {
+	var calls []string
+	mu := &sync.Mutex{}
+
+	m := lifecycle.NewManager(logrus.New(), time.Second)
+	listening, failing := newFakeServer("http", &calls, mu), newFakeServer("grpc", &calls, mu)
+	failing.listenErr = errors.New("permission denied")
+	m.Serve("http", listening)
+	m.Serve("grpc", failing)
+
+	ready := false
+	m.OnReady(func() { ready = true })
+
+	if err := m.Run(context.Background()); !errors.Is(err, failing.listenErr) {
+		t.Fatalf("Expected the error of the failing server, received %v", err)
+	}
+
+	if ready || listening.called(t, "serve") {
+		t.Fatalf("Expected no server to serve, received %v", calls)
+	}
+}
+
+// TestLifecycleShutdownTimeout asserts that the servers still draining past the timeout fail the shutdown.
+func TestLifecycleShutdownTimeout(t *testing.T) {
+	var calls []string
+	mu := &sync.Mutex{}
+
+	m := lifecycle.NewManager(logrus.New(), 50*time.Millisecond)
+	stuck := newFakeServer("http", &calls, mu)
+	stuck.stuck = true
+	m.Serve("http", stuck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.OnReady(cancel)
+
+	if err := m.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the shutdown to time out, received %v", err)
+	}
+}
+
+// TestHttpServerShutdown asserts that the HTTP server serves until it shuts down.
+func TestHttpServerShutdown(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Address = "127.0.0.1"
+	cfg.Server.Port = 0
+
+	s := serverFixture(cfg, server.Options{})
+
+	if err := s.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- s.Serve() }()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/openapi.json", s.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, received %d", http.StatusOK, res.StatusCode)
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-served; err != nil {
+		t.Fatalf("Expected the server to stop cleanly, received %v", err)
+	}
+}
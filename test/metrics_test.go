@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+	"stocks-api/support/metrics"
+)
+
+// fakeInventoryStore an InventoryStore serving a fixed inventory.
+type fakeInventoryStore struct {
+	inventories []*entities.Inventory
+}
+
+func (f *fakeInventoryStore) Inventory(ctx context.Context, threshold int64) ([]*entities.Inventory, error) {
+	return f.inventories, nil
+}
+
+// scrape the metrics in the Prometheus exposition format.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func expectMetric(t *testing.T, scraped string, metric string) {
+	t.Helper()
+
+	if !strings.Contains(scraped, metric) {
+		t.Fatalf("Expected the metric %s, scraped:\n%s", metric, scraped)
+	}
+}
+
+// TestHttpMetrics asserts that the requests are recorded by their path template, and the metrics served without auth.
+func TestHttpMetrics(t *testing.T) {
+	m := metrics.NewMetrics(logrus.New(), nil, nil, 10)
+	a, _ := authFixture(t)
+	s := routerFixture(server.Options{Authenticator: a, Metrics: m})
+
+	decodeProblem(t, serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id"), http.StatusUnauthorized)
+
+	rec := serveRoute(s, http.MethodGet, "/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, received %d", http.StatusOK, rec.Code)
+	}
+
+	scraped := scrape(t, m)
+	expectMetric(t, scraped, `stocks_http_requests_total{code="401",method="GET",route="/api/v1/stocks/{id}"} 1`)
+	expectMetric(t, scraped, `stocks_http_request_duration_seconds_count{method="GET",route="/api/v1/stocks/{id}"} 1`)
+}
+
+// TestGrpcMetrics asserts that the calls are recorded by their full method and status code.
+func TestGrpcMetrics(t *testing.T) {
+	m := metrics.NewMetrics(logrus.New(), nil, nil, 10)
+	interceptor := rpc.UnaryMetricsInterceptor(m)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/GetStock"}
+	if _, err := interceptor(context.Background(), nil, info, handler); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected the error of the handler, received %v", err)
+	}
+
+	scraped := scrape(t, m)
+	expectMetric(t, scraped, `stocks_grpc_requests_total{code="NotFound",method="/stocks.StockService/GetStock"} 1`)
+	expectMetric(t, scraped, `stocks_grpc_request_duration_seconds_count{method="/stocks.StockService/GetStock"} 1`)
+}
+
+// TestQueryMetrics asserts that the queries are timed by operation, and their failures counted.
+func TestQueryMetrics(t *testing.T) {
+	m := metrics.NewMetrics(logrus.New(), nil, nil, 10)
+	hook := m.QueryHook()
+
+	hook.AfterQuery(context.Background(), &bun.QueryEvent{Query: "SELECT 1", StartTime: time.Now()})
+	hook.AfterQuery(context.Background(), &bun.QueryEvent{Query: "UPDATE stock", StartTime: time.Now(), Err: errors.New("deadlock")})
+
+	scraped := scrape(t, m)
+	expectMetric(t, scraped, `stocks_db_query_duration_seconds_count{operation="SELECT"} 1`)
+	expectMetric(t, scraped, `stocks_db_query_errors_total{operation="UPDATE"} 1`)
+
+	if strings.Contains(scraped, `stocks_db_query_errors_total{operation="SELECT"}`) {
+		t.Fatal("Expected the successful queries not to count as failures")
+	}
+}
+
+// TestInventoryMetrics asserts that the inventory gauges follow the stock of every tenant.
+func TestInventoryMetrics(t *testing.T) {
+	store := &fakeInventoryStore{inventories: []*entities.Inventory{
+		{TenantID: "acme", Skus: 3, Units: 120, LowStock: 1},
+		{TenantID: "globex", Skus: 1, Units: 5, LowStock: 1},
+	}}
+	m := metrics.NewMetrics(logrus.New(), store, nil, 10)
+
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	scraped := scrape(t, m)
+	expectMetric(t, scraped, `stocks_inventory_skus{tenant="acme"} 3`)
+	expectMetric(t, scraped, `stocks_inventory_units{tenant="acme"} 120`)
+	expectMetric(t, scraped, `stocks_inventory_low_stock_items{tenant="globex"} 1`)
+
+	store.inventories = store.inventories[:1]
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(scrape(t, m), `tenant="globex"`) {
+		t.Fatal("Expected the tenant without stock to be dropped")
+	}
+}
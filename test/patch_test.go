@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	pb "stocks-api/genprotos"
+	"stocks-api/module/entities"
+	"stocks-api/module/services"
+	server "stocks-api/support/http"
+)
+
+// TestMergePatchRejections asserts that the merge patches are checked before reaching the db.
+func TestMergePatchRejections(t *testing.T) {
+	s := routerFixture(server.Options{})
+	target := "/api/v1/stocks/" + uuid.NewString()
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"removed field", `{"name":null}`, http.StatusBadRequest},
+		{"read-only field", `{"id":"8dd6a556-dde0-4bc9-b61a-b1cfd6065dbc"}`, http.StatusBadRequest},
+		{"mistyped field", `{"quantity":"ten"}`, http.StatusBadRequest},
+		{"empty patch", `{}`, http.StatusBadRequest},
+		{"negative quantity", `{"quantity":-1}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+
+			rec := httptest.NewRecorder()
+			s.Server.ServeHTTP(rec, req)
+
+			decodeProblem(t, rec, tc.status)
+		})
+	}
+}
+
+// TestEditStockUpdateMask asserts that the update mask only accepts the updatable fields.
+func TestEditStockUpdateMask(t *testing.T) {
+	_, err := handlerFixture().EditStock(context.Background(), &pb.EditStockRequest{
+		Stock:      &pb.EditableStock{Id: uuid.NewString(), Name: "widget"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "created_at"}},
+	})
+
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s", code)
+	}
+}
+
+// TestZeroQuantityUpdate asserts that setting the quantity to 0 writes a 0, rather than a NULL refused by the db.
+func TestZeroQuantityUpdate(t *testing.T) {
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector()), pgdialect.New())
+	stock := &entities.Stock{ID: uuid.New(), Name: "widget", Quantity: 0}
+
+	query := db.NewUpdate().Model(stock).Column("quantity").WherePK().String()
+	if !strings.Contains(query, `"quantity" = 0`) {
+		t.Fatalf("Expected the quantity to be set to 0, received %s", query)
+	}
+}
+
+// TestPatchQuantityToZero asserts that a stock item can be patched out of stock.
+func TestPatchQuantityToZero(t *testing.T) {
+	service := services.NewStockService(logrus.New(), dbFixture(t), context.Background())
+	ctx := tenantFixture()
+
+	stock := &entities.Stock{Name: "widget", Quantity: 5}
+	if err := service.InsertOne(ctx, stock); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.PatchOne(ctx, &entities.Stock{Quantity: 0}, stock.ID.String(), []string{"quantity"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{stock.ID: 0})
+
+	if err := service.UpdateOne(ctx, &entities.Stock{Name: "gadget", Quantity: 0}, stock.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	assertStocks(t, ctx, service, map[uuid.UUID]int64{stock.ID: 0})
+}
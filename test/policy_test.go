@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "stocks-api/genprotos"
+	"stocks-api/module/controllers"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/policy"
+)
+
+func policyFixture() *policy.Engine {
+	return policy.NewEngine(policy.Default())
+}
+
+func principalOf(roles ...string) *entities.Principal {
+	return &entities.Principal{Subject: "alice", Kind: entities.JWTPrincipal, Roles: roles}
+}
+
+// TestPolicyRoles asserts the actions the default roles are allowed.
+func TestPolicyRoles(t *testing.T) {
+	engine := policyFixture()
+
+	cases := []struct {
+		principal *entities.Principal
+		action    policy.Action
+		allowed   bool
+	}{
+		{principalOf("viewer"), policy.ReadStock, true},
+		{principalOf("viewer"), policy.ExportStock, true},
+		{principalOf("viewer"), policy.WatchStock, true},
+		{principalOf("viewer"), policy.CreateStock, false},
+		{principalOf("clerk"), policy.CreateStock, true},
+		{principalOf("clerk"), policy.UpdateStock, true},
+		{principalOf("clerk"), policy.DeleteStock, false},
+		{principalOf("clerk"), policy.AdjustStock, false},
+		{principalOf("manager"), policy.DeleteStock, true},
+		{principalOf("manager"), policy.AdjustStock, true},
+		{principalOf("manager"), policy.ManageWebhooks, false},
+		{principalOf("admin"), policy.ManageWebhooks, true},
+		{principalOf("viewer", "manager"), policy.DeleteStock, true},
+		{principalOf("unknown"), policy.ReadStock, false},
+		{principalOf(), policy.ReadStock, false},
+		{nil, policy.DeleteStock, true},
+	}
+
+	for _, c := range cases {
+		if allowed := engine.Allowed(c.principal, c.action); allowed != c.allowed {
+			t.Fatalf("Expected %+v allowed %s to be %v", c.principal, c.action, c.allowed)
+		}
+
+		err := engine.Authorize(entities.WithPrincipal(context.Background(), c.principal), c.action)
+		if c.allowed != (err == nil) || (err != nil && errs.KindOf(err) != errs.PermissionDenied) {
+			t.Fatalf("Unexpected authorization of %+v for %s: %v", c.principal, c.action, err)
+		}
+	}
+}
+
+// TestPolicyAdjustThreshold asserts that only the managers may change a quantity beyond the threshold.
+func TestPolicyAdjustThreshold(t *testing.T) {
+	engine := policyFixture()
+
+	cases := []struct {
+		name      string
+		principal *entities.Principal
+		current   int64
+		quantity  int64
+		allowed   bool
+		lookedUp  bool
+	}{
+		{"clerk within", principalOf("clerk"), 50, 150, true, true},
+		{"clerk down within", principalOf("clerk"), 150, 50, true, true},
+		{"clerk beyond", principalOf("clerk"), 50, 151, false, true},
+		{"clerk down beyond", principalOf("clerk"), 151, 50, false, true},
+		{"manager beyond", principalOf("manager"), 0, 10000, true, false},
+		{"admin beyond", principalOf("admin"), 0, 10000, true, false},
+		{"anonymous beyond", nil, 0, 10000, true, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lookedUp := false
+			current := func() (int64, error) {
+				lookedUp = true
+				return c.current, nil
+			}
+
+			ctx := entities.WithPrincipal(context.Background(), c.principal)
+
+			err := engine.AuthorizeQuantity(ctx, c.quantity, current)
+			if c.allowed != (err == nil) {
+				t.Fatalf("Expected allowed to be %v, received %v", c.allowed, err)
+			}
+
+			if err != nil && (errs.KindOf(err) != errs.PermissionDenied || errs.FieldOf(err) != "quantity") {
+				t.Fatalf("Expected a permission denied on the quantity, received %v", err)
+			}
+
+			if lookedUp != c.lookedUp {
+				t.Fatalf("Expected the current quantity lookup to be %v", c.lookedUp)
+			}
+		})
+	}
+
+	lookupErr := errors.New("db down")
+	err := engine.AuthorizeQuantity(entities.WithPrincipal(context.Background(), principalOf("clerk")), 1,
+		func() (int64, error) { return 0, lookupErr })
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("Expected the lookup error, received %v", err)
+	}
+}
+
+// TestPolicyFile asserts that the policy files replace the default roles, and that unknown actions are rejected.
+func TestPolicyFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"valid", "adjust_threshold: 10\nroles:\n  auditor: [stock:read, stock:export]\n", true},
+		{"unknown action", "roles:\n  auditor: [stock:steal]\n", false},
+		{"negative threshold", "adjust_threshold: -1\n", false},
+		{"malformed", "roles: [", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(c.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := policy.Load(path)
+			if c.valid != (err == nil) {
+				t.Fatalf("Expected valid to be %v, received %v", c.valid, err)
+			}
+
+			if !c.valid {
+				return
+			}
+
+			engine := policy.NewEngine(p)
+			if !engine.Allowed(principalOf("auditor"), policy.ExportStock) || engine.Allowed(principalOf("viewer"), policy.ReadStock) {
+				t.Fatal("Expected only the roles of the file to be granted")
+			}
+		})
+	}
+}
+
+// TestPolicyEnforced asserts that both the gRPC handlers and the HTTP controllers deny the actions a role lacks.
+func TestPolicyEnforced(t *testing.T) {
+	ctx := entities.WithPrincipal(context.Background(), principalOf("viewer"))
+
+	_, err := handlerFixture().DeleteStock(ctx, &pb.DeleteStockRequest{Id: "not-an-id"})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("Expected PermissionDenied, received %s", code)
+	}
+
+	controller := controllers.NewStockController(logrus.New(), nil, context.Background(), policyFixture())
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/api/v1/stocks/not-an-id", nil), map[string]string{"id": "not-an-id"})
+	rec := httptest.NewRecorder()
+	controller.DeleteOne(rec, req.WithContext(ctx))
+
+	if body := decodeProblem(t, rec, http.StatusForbidden); body.Type != "/problems/forbidden" {
+		t.Fatalf("Expected a forbidden problem, received %s", body.Type)
+	}
+
+	// Reads are allowed, the request reaches the validation.
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil), map[string]string{"id": "not-an-id"})
+	rec = httptest.NewRecorder()
+	controller.GetOne(rec, req.WithContext(ctx))
+
+	decodeProblem(t, rec, http.StatusBadRequest)
+}
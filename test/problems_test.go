@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/controllers"
+)
+
+type problemBody struct {
+	Type          string `json:"type"`
+	Status        int    `json:"status"`
+	InvalidParams []struct {
+		Name string `json:"name"`
+	} `json:"invalid_params"`
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) problemBody {
+	if rec.Code != status {
+		t.Fatalf("Expected %d, received %d: %s", status, rec.Code, rec.Body.String())
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("Expected a problem+json body, received %s", ct)
+	}
+
+	body := problemBody{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Status != status {
+		t.Fatalf("Expected the body status to be %d, received %d", status, body.Status)
+	}
+
+	return body
+}
+
+// TestProblemStatusCodes asserts the status codes and problem types of the REST failures.
+func TestProblemStatusCodes(t *testing.T) {
+	controller := controllers.NewStockController(logrus.New(), nil, context.Background(), policyFixture())
+
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		vars    map[string]string
+		status  int
+		typ     string
+		field   string
+	}{
+		{"malformed body", controller.InsertOne, `{"name":`, nil, http.StatusBadRequest, "/problems/malformed-request", ""},
+		{"failed validation", controller.InsertOne, `{"quantity":3}`, nil, http.StatusUnprocessableEntity, "/problems/validation", "name"},
+		{"negative quantity", controller.InsertOne, `{"name":"widget","quantity":-1}`, nil, http.StatusUnprocessableEntity, "/problems/validation", "quantity"},
+		{"update without name", controller.UpdateOne, `{"quantity":3}`, map[string]string{"id": uuid.NewString()}, http.StatusUnprocessableEntity, "/problems/validation", "name"},
+		{"update to a negative quantity", controller.UpdateOne, `{"name":"widget","quantity":-1}`, map[string]string{"id": uuid.NewString()}, http.StatusUnprocessableEntity, "/problems/validation", "quantity"},
+		{"invalid id", controller.GetOne, ``, map[string]string{"id": "nope"}, http.StatusBadRequest, "/problems/malformed-request", "id"},
+		{"missing pagination", controller.GetAll, `{}`, nil, http.StatusBadRequest, "/problems/malformed-request", "pagination"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			if tc.vars != nil {
+				req = mux.SetURLVars(req, tc.vars)
+			}
+
+			rec := httptest.NewRecorder()
+			tc.handler(rec, req)
+
+			body := decodeProblem(t, rec, tc.status)
+			if body.Type != tc.typ {
+				t.Fatalf("Expected type %s, received %s", tc.typ, body.Type)
+			}
+
+			if tc.field != "" && (len(body.InvalidParams) == 0 || body.InvalidParams[0].Name != tc.field) {
+				t.Fatalf("Expected %s as invalid param, received %v", tc.field, body.InvalidParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	eventsv1 "stocks-api/genprotos/events/v1"
+	"stocks-api/module/entities"
+	"stocks-api/support/publishers"
+)
+
+// fakeNatsConn stands in for a JetStream stream, acknowledging the messages up to the failing one.
+type fakeNatsConn struct {
+	msgs  []*nats.Msg
+	fails int
+}
+
+func (f *fakeNatsConn) PublishMsg(msg *nats.Msg, _ ...nats.PubOpt) (*nats.PubAck, error) {
+	if f.fails > 0 && len(f.msgs) == f.fails-1 {
+		return nil, nats.ErrNoStreamResponse
+	}
+
+	f.msgs = append(f.msgs, msg)
+
+	return &nats.PubAck{Stream: "STOCKS", Sequence: uint64(len(f.msgs))}, nil
+}
+
+func (f *fakeNatsConn) Close() {}
+
+// fakeKafkaWriter stands in for a Kafka producer.
+type fakeKafkaWriter struct {
+	msgs []kafka.Message
+}
+
+func (f *fakeKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	f.msgs = append(f.msgs, msgs...)
+	return nil
+}
+
+func (f *fakeKafkaWriter) Close() error {
+	return nil
+}
+
+func outboxFixture() *entities.OutboxEvent {
+	event := entities.NewOutboxEvent(entities.Updated, &entities.Stock{ID: uuid.New(), Name: "widget", Quantity: 7})
+	event.ID = uuid.New()
+
+	return event
+}
+
+// TestMemoryPublisher asserts that published events decode into the versioned schema.
+func TestMemoryPublisher(t *testing.T) {
+	event := outboxFixture()
+	publisher := publishers.NewMemoryPublisher()
+
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := publisher.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("Expected 1 message, received %d", len(msgs))
+	}
+
+	decoded := eventsv1.StockEvent{}
+	if err := proto.Unmarshal(msgs[0], &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.GetId() != event.ID.String() ||
+		decoded.GetType() != eventsv1.StockEventType_STOCK_EVENT_TYPE_UPDATED ||
+		decoded.GetStock().GetQuantity() != 7 {
+		t.Fatalf("Unexpected decoded event: %v", &decoded)
+	}
+}
+
+// TestNatsPublisher asserts the subjects and deduplication headers of the NATS messages.
+func TestNatsPublisher(t *testing.T) {
+	event := outboxFixture()
+	conn := &fakeNatsConn{}
+
+	if err := publishers.NewNatsPublisher(conn, "").Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conn.msgs) != 1 {
+		t.Fatalf("Expected 1 acknowledged message, received %d", len(conn.msgs))
+	}
+
+	if conn.msgs[0].Subject != "stocks.events.v1.stock.updated" {
+		t.Fatalf("Unexpected subject: %s", conn.msgs[0].Subject)
+	}
+
+	if conn.msgs[0].Header.Get(nats.MsgIdHdr) != event.ID.String() {
+		t.Fatalf("Expected the event id as message id, received %s", conn.msgs[0].Header.Get(nats.MsgIdHdr))
+	}
+}
+
+// TestNatsPublisherUnacknowledged asserts that the publish fails unless the stream acknowledged every event.
+func TestNatsPublisherUnacknowledged(t *testing.T) {
+	conn := &fakeNatsConn{fails: 2}
+
+	err := publishers.NewNatsPublisher(conn, "").Publish(context.Background(), outboxFixture(), outboxFixture(), outboxFixture())
+	if err == nil {
+		t.Fatal("Expected the unacknowledged event to fail the publish")
+	}
+
+	if len(conn.msgs) != 1 {
+		t.Fatalf("Expected the publish to stop at the unacknowledged event, received %d messages", len(conn.msgs))
+	}
+}
+
+// TestKafkaPublisher asserts that the Kafka messages are keyed by stock id.
+func TestKafkaPublisher(t *testing.T) {
+	event := outboxFixture()
+	writer := &fakeKafkaWriter{}
+
+	if err := publishers.NewKafkaPublisher(writer).Publish(context.Background(), event, outboxFixture()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(writer.msgs) != 2 {
+		t.Fatalf("Expected 2 messages, received %d", len(writer.msgs))
+	}
+
+	if string(writer.msgs[0].Key) != event.StockID.String() {
+		t.Fatalf("Expected the stock id as key, received %s", writer.msgs[0].Key)
+	}
+}
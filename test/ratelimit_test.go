@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+	"stocks-api/support/ratelimit"
+)
+
+// limiterFixture a limiter allowing 2 requests per hour, and 1 on the create route.
+func limiterFixture() *ratelimit.Limiter {
+	return ratelimit.NewLimiter(logrus.New(), ratelimit.NewMemoryStore(), &ratelimit.Config{
+		Default: ratelimit.Limit{Requests: 2, Per: time.Hour},
+		Routes: map[string]ratelimit.Limit{
+			"POST /api/v1/stocks":              {Requests: 1, Per: time.Hour},
+			"/stocks.StockService/CreateStock": {Requests: 1, Per: time.Hour},
+			"GET /api/v1/stocks/events":        {},
+		},
+	})
+}
+
+// TestRateLimitBuckets asserts that the clients and the routes with a limit of their own have their own buckets.
+func TestRateLimitBuckets(t *testing.T) {
+	limiter := limiterFixture()
+	ctx := context.Background()
+
+	alice := ratelimit.Client(&entities.Principal{Kind: entities.JWTPrincipal, Subject: "alice"}, "10.0.0.1")
+	anonymous := ratelimit.Client(nil, "10.0.0.1")
+
+	for i, allowed := range []bool{true, true, false} {
+		d := limiter.Allow(ctx, "GET /api/v1/stocks", alice)
+		if d.Allowed != allowed {
+			t.Fatalf("Expected request %d allowed to be %v", i+1, allowed)
+		}
+	}
+
+	d := limiter.Allow(ctx, "GET /api/v1/stocks/{id}", alice)
+	if d.Allowed || d.RetryAfter <= 0 || d.RetryAfter > 30*time.Minute {
+		t.Fatalf("Expected the default bucket to be shared by the routes, received %+v", d)
+	}
+
+	if d = limiter.Allow(ctx, "POST /api/v1/stocks", alice); !d.Allowed || d.Limit != 1 || d.Remaining != 0 {
+		t.Fatalf("Expected the create route to have its own bucket, received %+v", d)
+	}
+
+	if d = limiter.Allow(ctx, "GET /api/v1/stocks", anonymous); !d.Allowed {
+		t.Fatal("Expected the clients to have their own buckets")
+	}
+
+	if d = limiter.Allow(ctx, "GET /api/v1/stocks/events", alice); d != nil {
+		t.Fatalf("Expected the route without requests to be unlimited, received %+v", d)
+	}
+}
+
+// TestRateLimitConfig asserts that the config files are validated.
+func TestRateLimitConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"valid", "default: {requests: 10, per: 1s, burst: 20}\nroutes:\n  POST /api/v1/stocks: {requests: 100, per: 24h}\n", true},
+		{"missing period", "default: {requests: 10}\n", false},
+		{"negative burst", "default: {requests: 10, per: 1s, burst: -1}\n", false},
+		{"malformed", "routes: [", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ratelimit.yaml")
+			if err := os.WriteFile(path, []byte(c.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := ratelimit.Load(path)
+			if c.valid != (err == nil) {
+				t.Fatalf("Expected valid to be %v, received %v", c.valid, err)
+			}
+
+			if c.valid && config.Routes["POST /api/v1/stocks"].Per != 24*time.Hour {
+				t.Fatalf("Expected the route limit to be read, received %+v", config.Routes)
+			}
+		})
+	}
+}
+
+// TestRateLimitMiddleware asserts that the requests over the limit are refused with a 429 and a Retry-After.
+func TestRateLimitMiddleware(t *testing.T) {
+	s := routerFixture(server.Options{Limiter: limiterFixture()})
+
+	rec := serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id")
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("Expected the limit headers on the allowed request, received %d: %v", rec.Code, rec.Header())
+	}
+
+	serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id")
+
+	rec = serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id")
+	problem := decodeProblem(t, rec, http.StatusTooManyRequests)
+
+	if problem.Type != "/problems/rate-limited" {
+		t.Fatalf("Expected a rate limited problem, received %+v", problem)
+	}
+
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter <= 0 {
+		t.Fatalf("Expected a Retry-After in seconds, received %q", rec.Header().Get("Retry-After"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+
+	rec = httptest.NewRecorder()
+	s.Server.ServeHTTP(rec, req)
+
+	if rec.Code == http.StatusTooManyRequests {
+		t.Fatal("Expected the other clients to keep their own limit")
+	}
+}
+
+// TestGrpcRateLimitInterceptor asserts that the calls over the limit are refused with ResourceExhausted and a retry delay.
+func TestGrpcRateLimitInterceptor(t *testing.T) {
+	interceptor := rpc.UnaryRateLimitInterceptor(limiterFixture())
+	info := &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/CreateStock"}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, received %s", st.Code())
+	}
+
+	if len(st.Details()) != 1 {
+		t.Fatalf("Expected a retry info, received %v", st.Details())
+	}
+
+	if retry, ok := st.Details()[0].(*errdetails.RetryInfo); !ok || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Fatalf("Expected a positive retry delay, received %v", st.Details()[0])
+	}
+}
+
+// TestGrpcRateLimitForwardedFor asserts that the gateway calls are credited to the client the gateway appended to
+// x-forwarded-for, not to the entries sent by the client.
+func TestGrpcRateLimitForwardedFor(t *testing.T) {
+	interceptor := rpc.UnaryRateLimitInterceptor(limiterFixture())
+	info := &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/CreateStock"}
+	loopback := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	call := func(forwarded string) error {
+		ctx := peer.NewContext(context.Background(), loopback)
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", forwarded))
+		_, err := interceptor(ctx, nil, info, handler)
+		return err
+	}
+
+	if err := call("203.0.113.1, 10.0.0.7"); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := status.Code(call("203.0.113.2, 10.0.0.7")); code != codes.ResourceExhausted {
+		t.Fatalf("Expected a spoofed x-forwarded-for not to reset the limit, received %s", code)
+	}
+
+	if err := call("203.0.113.1, 10.0.0.8"); err != nil {
+		t.Fatalf("Expected the other clients to keep their own limit, received %v", err)
+	}
+}
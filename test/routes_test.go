@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"stocks-api/module/controllers"
+	"stocks-api/support/config"
+	server "stocks-api/support/http"
+)
+
+// serverFixture the HTTP server of the controllers backed by no db, along with the given options.
+// Its routes are registered as it listens, or by routerFixture to be served in process.
+func serverFixture(cfg *config.Config, opts server.Options) *server.Serve {
+	l := logrus.New()
+
+	opts.Stocks = controllers.NewStockController(l, nil, context.Background(), policyFixture())
+	opts.Feed = controllers.NewFeedController(l, &fakeWatcher{}, policyFixture())
+	opts.Webhooks = controllers.NewWebhookController(l, nil, policyFixture())
+	opts.GraphQL = controllers.NewGraphQLController(l, nil, context.Background(), &fakeWatcher{}, policyFixture())
+
+	return server.NewServe(cfg, l, opts)
+}
+
+// routerFixture the routes of the server of the default config, along with the given options.
+func routerFixture(opts server.Options) *server.Serve {
+	s := serverFixture(config.Default(), opts)
+	s.RegisterHandlers()
+
+	return s
+}
+
+func serveRoute(s *server.Serve, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Server.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+
+	return rec
+}
+
+// TestVersionedRoutes asserts that the /api/v1 routes are served without deprecation headers.
+func TestVersionedRoutes(t *testing.T) {
+	s := routerFixture(server.Options{})
+
+	rec := serveRoute(s, http.MethodGet, "/api/v1/stocks/not-an-id")
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Deprecation") != "" {
+		t.Fatalf("Expected a non deprecated 400, received %d %v", rec.Code, rec.Header())
+	}
+
+	rec = serveRoute(s, http.MethodGet, "/api/v1/stocks?page=0")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422 for an out of range page, received %d", rec.Code)
+	}
+
+	rec = serveRoute(s, http.MethodPost, "/api/v1/stocks/not-an-id")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected 405 for the legacy update verb, received %d", rec.Code)
+	}
+}
+
+// TestLegacyRoutes asserts that the legacy routes are deprecated, and can be switched off.
+func TestLegacyRoutes(t *testing.T) {
+	rec := serveRoute(routerFixture(server.Options{}), http.MethodGet, "/not-an-id")
+
+	if rec.Header().Get("Deprecation") == "" || rec.Header().Get("Sunset") == "" {
+		t.Fatalf("Expected the deprecation headers, received %v", rec.Header())
+	}
+
+	if link := rec.Header().Get("Link"); link != `</api/v1/stocks/not-an-id>; rel="successor-version"` {
+		t.Fatalf("Unexpected successor link: %s", link)
+	}
+
+	cfg := config.Default()
+	cfg.Features.LegacyRoutes = false
+
+	s := serverFixture(cfg, server.Options{})
+	s.RegisterHandlers()
+
+	rec = serveRoute(s, http.MethodGet, "/not-an-id")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 with the legacy routes off, received %d", rec.Code)
+	}
+}
+
+// TestLegacyRoutesBaselineOnly asserts that the routes added since the versioning aren't served unversioned.
+func TestLegacyRoutesBaselineOnly(t *testing.T) {
+	s := routerFixture(server.Options{})
+
+	routes := map[string]string{
+		"/batch/create":          http.MethodPost,
+		"/batch/delete":          http.MethodPost,
+		"/events/ws":             http.MethodGet,
+		"/webhooks/dead-letters": http.MethodGet,
+	}
+
+	for path, method := range routes {
+		if rec := serveRoute(s, method, path); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected %s %s not to be served, received %d", method, path, rec.Code)
+		}
+	}
+}
@@ -42,3 +42,15 @@ func TestBeforeAppend(t *testing.T) {
 		t.Fatalf("Expected timestamp generation diff: %s <> %s", stock.UpdatedAt.String(), updatedAt.String())
 	}
 }
+
+// TestBeforeAppendKeepsId asserts that an id supplied by the client is kept on insert.
+func TestBeforeAppendKeepsId(t *testing.T) {
+	id := uuid.New()
+	stock := entities.Stock{ID: id, Name: "test_stock", Quantity: 10}
+
+	stock.BeforeAppendModel(context.Background(), bun.NewInsertQuery(new(bun.DB)))
+
+	if stock.ID != id {
+		t.Fatalf("Expected the supplied id %s to be kept, received %s", id, stock.ID)
+	}
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/module/services"
+	"stocks-api/support/auth"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+)
+
+// TestTenantResolution asserts that the principals are bound to their tenant, and the anonymous calls pick theirs.
+func TestTenantResolution(t *testing.T) {
+	acme := &entities.Principal{Subject: "alice", Tenant: "acme"}
+
+	tests := []struct {
+		name      string
+		principal *entities.Principal
+		requested string
+		tenant    string
+		fails     bool
+		kind      errs.Kind
+	}{
+		{name: "anonymous default", tenant: entities.DefaultTenant},
+		{name: "anonymous header", requested: "globex", tenant: "globex"},
+		{name: "principal", principal: acme, tenant: "acme"},
+		{name: "principal repeated", principal: acme, requested: "acme", tenant: "acme"},
+		{name: "principal without tenant", principal: &entities.Principal{Subject: "bob"}, tenant: entities.DefaultTenant},
+		{name: "principal other tenant", principal: acme, requested: "globex", fails: true, kind: errs.PermissionDenied},
+		{name: "invalid header", requested: "Acme Corp", fails: true, kind: errs.InvalidArgument},
+		{name: "invalid header of a principal", principal: acme, requested: "../acme", fails: true, kind: errs.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := auth.Tenant(tt.principal, tt.requested)
+
+			if tt.fails {
+				if err == nil || errs.KindOf(err) != tt.kind {
+					t.Fatalf("Expected an error of kind %d, received %v", tt.kind, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tenant != tt.tenant {
+				t.Fatalf("Expected tenant '%s', received '%s'", tt.tenant, tenant)
+			}
+		})
+	}
+}
+
+// TestTenantClaim asserts that the tenant_id claim is carried by the principal.
+func TestTenantClaim(t *testing.T) {
+	a, _ := authFixture(t)
+
+	claims := validClaims()
+	claims["tenant_id"] = "acme"
+
+	principal, err := a.Authenticate(context.Background(), signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal.Tenant != "acme" {
+		t.Fatalf("Expected tenant 'acme', received '%s'", principal.Tenant)
+	}
+}
+
+// TestTenantMiddleware asserts that the X-Tenant-ID header may only repeat the tenant of the caller.
+func TestTenantMiddleware(t *testing.T) {
+	a, _ := authFixture(t)
+	s := routerFixture(server.Options{Authenticator: a})
+
+	claims := validClaims()
+	claims["tenant_id"] = "acme"
+	token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), claims)
+
+	tests := map[string]int{
+		"":          http.StatusBadRequest,
+		"acme":      http.StatusBadRequest,
+		"globex":    http.StatusForbidden,
+		"Acme Corp": http.StatusBadRequest,
+	}
+
+	for header, code := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if header != "" {
+			req.Header.Set("X-Tenant-ID", header)
+		}
+
+		rec := httptest.NewRecorder()
+		s.Server.ServeHTTP(rec, req)
+
+		problem := decodeProblem(t, rec, code)
+		if header == "Acme Corp" && (len(problem.InvalidParams) != 1 || problem.InvalidParams[0].Name != "tenant") {
+			t.Fatalf("Expected the tenant to be the invalid param, received %+v", problem)
+		}
+	}
+}
+
+// TestGrpcTenantInterceptor asserts that the calls are scoped to the tenant of their x-tenant-id metadata.
+func TestGrpcTenantInterceptor(t *testing.T) {
+	interceptor := rpc.UnaryAuthInterceptor(logrus.New(), nil, nil)
+
+	var tenant string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		tenant = entities.TenantFrom(ctx)
+		return nil, nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if tenant != entities.DefaultTenant {
+		t.Fatalf("Expected the default tenant, received '%s'", tenant)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "globex"))
+	if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if tenant != "globex" {
+		t.Fatalf("Expected tenant 'globex', received '%s'", tenant)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "Globex Inc"))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, received %s", code)
+	}
+}
+
+// TestStockTenantOnInsert asserts that the new stock is assigned the tenant of the call.
+func TestStockTenantOnInsert(t *testing.T) {
+	stock := &entities.Stock{Name: "Widget"}
+	ctx := entities.WithTenant(context.Background(), "acme")
+
+	if err := stock.BeforeAppendModel(ctx, (*bun.InsertQuery)(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if stock.TenantID != "acme" {
+		t.Fatalf("Expected tenant 'acme', received '%s'", stock.TenantID)
+	}
+}
+
+// TestTenantRowLevelSecurity asserts that the db itself hides the rows of a tenant from another, unscoped queries included.
+func TestTenantRowLevelSecurity(t *testing.T) {
+	instance := dbFixture(t)
+	service := services.NewStockService(logrus.New(), instance, context.Background())
+	first, second := tenantFixture(), tenantFixture()
+
+	stock := &entities.Stock{Name: "widget", Quantity: 3}
+	if err := service.InsertOne(first, stock); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.GetOne(second, stock.ID.String()); errs.KindOf(err) != errs.NotFound {
+		t.Fatalf("Expected the stock of another tenant not to be found, received %v", err)
+	}
+
+	tables := map[string]string{"stock": "id", "stock_event": "stock_id", "outbox": "stock_id"}
+	tenants := map[string]bool{entities.TenantFrom(first): true, entities.TenantFrom(second): false}
+
+	for table, column := range tables {
+		for tenant, visible := range tenants {
+			var count int
+
+			err := settingTx(first, instance, "app.tenant_id", tenant, func(tx bun.Tx) (err error) {
+				count, err = tx.NewSelect().TableExpr(table).Where("? = ?", bun.Ident(column), stock.ID).Count(first)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (count > 0) != visible {
+				t.Fatalf("Expected the rows of %s to be visible to tenant '%s': %t, received %d", table, tenant, visible, count)
+			}
+		}
+	}
+
+	err := settingTx(second, instance, "app.tenant_id", entities.TenantFrom(second), func(tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(&entities.Stock{Name: "intruder", TenantID: entities.TenantFrom(first)}).Exec(second)
+		return err
+	})
+	if err == nil {
+		t.Fatal("Expected a row of another tenant to be refused")
+	}
+}
@@ -0,0 +1,497 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"stocks-api/module/entities"
+	"stocks-api/module/errs"
+	"stocks-api/support/certs"
+	"stocks-api/support/config"
+	"stocks-api/support/db"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+)
+
+// testCA a certificate authority issuing the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issued a certificate along with its PEM files.
+type issued struct {
+	cert     *x509.Certificate
+	certFile string
+	keyFile  string
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+// file writes the PEM certificate of the CA.
+func (ca *testCA) file(t *testing.T) string {
+	return writePEM(t, "ca.pem", "CERTIFICATE", ca.cert.Raw)
+}
+
+// pool the CA as the only root.
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	return pool
+}
+
+// issue a certificate for the subject, a server one for the hosts or else a client one.
+func (ca *testCA) issue(t *testing.T, subject pkix.Name, hosts ...string) *issued {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if len(hosts) > 0 {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+		for _, host := range hosts {
+			if ip := net.ParseIP(host); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, host)
+			}
+		}
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &issued{
+		cert:     cert,
+		certFile: writePEM(t, fmt.Sprintf("%s.pem", subject.CommonName), "CERTIFICATE", raw),
+		keyFile:  writePEM(t, fmt.Sprintf("%s-key.pem", subject.CommonName), "EC PRIVATE KEY", der),
+	}
+}
+
+// pair the certificate along with its key, as presented on handshake.
+func (i *issued) pair(t *testing.T) tls.Certificate {
+	pair, err := tls.LoadX509KeyPair(i.certFile, i.keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pair
+}
+
+func writePEM(t *testing.T, name string, kind string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// reloaderFixture the reloader of a server certificate, verifying the client certificates of the CA in the mode.
+func reloaderFixture(t *testing.T, ca *testCA, mode string) (*certs.Reloader, *issued) {
+	serverCert := ca.issue(t, pkix.Name{CommonName: "stocks-api"}, "127.0.0.1", "localhost")
+
+	cfg := config.Default().TLS
+	cfg.CertFile = serverCert.certFile
+	cfg.KeyFile = serverCert.keyFile
+	cfg.ClientCAFile = ca.file(t)
+	cfg.ClientAuth = mode
+
+	r, err := certs.NewReloader(logrus.New(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r, serverCert
+}
+
+// TestCertificateReload asserts that a renewed certificate is picked up, and an invalid one doesn't replace it.
+func TestCertificateReload(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	r, first := reloaderFixture(t, ca, "optional")
+
+	if leaf := r.Certificate().Certificate[0]; string(leaf) != string(first.cert.Raw) {
+		t.Fatal("Expected the certificate of the files")
+	}
+
+	if reloaded, err := r.Reload(); reloaded || err != nil {
+		t.Fatalf("Expected the unchanged files to be kept, received %t, %v", reloaded, err)
+	}
+
+	renewed := ca.issue(t, pkix.Name{CommonName: "stocks-api-renewed"}, "localhost")
+
+	copyFile(t, renewed.certFile, first.certFile)
+	copyFile(t, renewed.keyFile, first.keyFile)
+
+	if reloaded, err := r.Reload(); !reloaded || err != nil {
+		t.Fatalf("Expected the renewed files to be reloaded, received %t, %v", reloaded, err)
+	}
+
+	if leaf := r.Certificate().Certificate[0]; string(leaf) != string(renewed.cert.Raw) {
+		t.Fatal("Expected the renewed certificate")
+	}
+
+	if err := os.WriteFile(first.keyFile, []byte("half written"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Reload(); err == nil {
+		t.Fatal("Expected the invalid key to fail the reload")
+	}
+
+	if leaf := r.Certificate().Certificate[0]; string(leaf) != string(renewed.cert.Raw) {
+		t.Fatal("Expected the renewed certificate to be kept")
+	}
+}
+
+// copyFile replaces a file, moving its modification time on so that the change is told apart.
+func copyFile(t *testing.T, from string, to string) {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(to, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(to, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCertificatePrincipal asserts that the client certificates are mapped to their principal.
+func TestCertificatePrincipal(t *testing.T) {
+	a, _ := authFixture(t)
+	ca := newTestCA(t, "ca")
+
+	cert := ca.issue(t, pkix.Name{CommonName: "billing", Organization: []string{"acme"}, OrganizationalUnit: []string{"viewer"}}).cert
+
+	principal, err := a.AuthenticateCertificate(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal.Subject != "billing" || principal.Kind != entities.CertificatePrincipal || principal.Tenant != "acme" {
+		t.Fatalf("Unexpected principal %+v", principal)
+	}
+
+	if len(principal.Roles) != 1 || principal.Roles[0] != "viewer" {
+		t.Fatalf("Expected the roles of the organizational units, received %v", principal.Roles)
+	}
+
+	invalid := map[string]pkix.Name{
+		"no common name": {Organization: []string{"acme"}},
+		"invalid tenant": {CommonName: "billing", Organization: []string{"Acme Corp"}},
+	}
+
+	for name, subject := range invalid {
+		if _, err := a.AuthenticateCertificate(ca.issue(t, subject).cert); errs.KindOf(err) != errs.Unauthenticated {
+			t.Fatalf("Expected %s to be unauthenticated, received %v", name, err)
+		}
+	}
+}
+
+// tlsServerFixture an HTTP server requiring auth, served over TLS by the reloader.
+func tlsServerFixture(t *testing.T, r *certs.Reloader) string {
+	a, _ := authFixture(t)
+
+	cfg := config.Default()
+	cfg.Server.Address = "127.0.0.1"
+	cfg.Server.Port = 0
+
+	s := serverFixture(cfg, server.Options{Authenticator: a, Certs: r})
+
+	if err := s.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	go s.Serve()
+
+	t.Cleanup(func() {
+		s.Shutdown(context.Background())
+	})
+
+	return fmt.Sprintf("https://%s", s.Addr())
+}
+
+// tlsClient a client trusting the CA, presenting the client certificates.
+func tlsClient(ca *testCA, clientCerts ...tls.Certificate) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:      ca.pool(),
+				Certificates: clientCerts,
+			},
+		},
+	}
+}
+
+// TestHttpMutualTLS asserts that the callers are authenticated by their client certificate, issued by the client CAs.
+func TestHttpMutualTLS(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	r, _ := reloaderFixture(t, ca, "optional")
+	url := tlsServerFixture(t, r) + "/api/v1/stocks/not-an-id"
+
+	client := ca.issue(t, pkix.Name{CommonName: "billing", Organization: []string{"acme"}, OrganizationalUnit: []string{"viewer"}})
+
+	res, err := tlsClient(ca, client.pair(t)).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected the certificate to authenticate the call, received %d", res.StatusCode)
+	}
+
+	res, err = tlsClient(ca).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected the call without a certificate to be unauthenticated, received %d", res.StatusCode)
+	}
+
+	rogue := newTestCA(t, "rogue").issue(t, pkix.Name{CommonName: "billing", Organization: []string{"acme"}})
+
+	if _, err := tlsClient(ca, rogue.pair(t)).Get(url); err == nil {
+		t.Fatal("Expected the certificate of another CA to be rejected")
+	}
+}
+
+// TestHttpRequiredClientCertificate asserts that the callers must present a certificate once it's required.
+func TestHttpRequiredClientCertificate(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	r, _ := reloaderFixture(t, ca, "require")
+	url := tlsServerFixture(t, r) + "/healthz"
+
+	if _, err := tlsClient(ca).Get(url); err == nil {
+		t.Fatal("Expected the call without a certificate to be rejected")
+	}
+
+	client := ca.issue(t, pkix.Name{CommonName: "billing"})
+
+	res, err := tlsClient(ca, client.pair(t)).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
+
+// TestGatewayCertificateHeader asserts that the client certificate header sent by the callers isn't forwarded.
+func TestGatewayCertificateHeader(t *testing.T) {
+	var forwarded string
+	gw := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(certs.ForwardedHeader)
+	})
+
+	s := routerFixture(server.Options{Gateway: gw})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/stocks", nil)
+	req.Header.Set(certs.ForwardedHeader, "c3Bvb2ZlZA==")
+
+	s.Server.ServeHTTP(httptest.NewRecorder(), req)
+
+	if forwarded != "" {
+		t.Fatalf("Expected the header of the caller to be dropped, received '%s'", forwarded)
+	}
+}
+
+// peerContext the context of a gRPC call over TLS, the peer presenting the certificate.
+func peerContext(cert *x509.Certificate, md metadata.MD) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.PeerCertificates = []*x509.Certificate{cert}
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// TestGrpcClientCertificate asserts that the calls are authenticated by their client certificate, or by the one the
+// REST gateway forwarded, which no other caller may forward.
+func TestGrpcClientCertificate(t *testing.T) {
+	a, _ := authFixture(t)
+	ca := newTestCA(t, "ca")
+	r, serverCert := reloaderFixture(t, ca, "optional")
+	interceptor := rpc.UnaryAuthInterceptor(logrus.New(), a, r)
+
+	var principal *entities.Principal
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal = entities.PrincipalFrom(ctx)
+		return nil, nil
+	}
+
+	billing := ca.issue(t, pkix.Name{CommonName: "billing", Organization: []string{"acme"}}).cert
+	forwarded := metadata.Pairs(certs.ForwardedMetadataKey, certs.Forward(billing))
+
+	calls := map[string]context.Context{
+		"client certificate":  peerContext(billing, metadata.MD{}),
+		"forwarded by the gw": peerContext(serverCert.cert, forwarded),
+	}
+
+	for name, ctx := range calls {
+		principal = nil
+
+		if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		if principal == nil || principal.Subject != "billing" || principal.Tenant != "acme" {
+			t.Fatalf("%s: expected the principal of the certificate, received %+v", name, principal)
+		}
+	}
+
+	spoofed := map[string]context.Context{
+		"forwarded by a client": peerContext(nil, forwarded),
+		"gateway without one":   peerContext(serverCert.cert, metadata.MD{}),
+	}
+
+	for name, ctx := range spoofed {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		if code := status.Code(err); code != codes.Unauthenticated {
+			t.Fatalf("%s: expected Unauthenticated, received %s", name, code)
+		}
+	}
+}
+
+// TestDBSSLModes asserts that the ssl modes verify the certificate of the database as libpq does.
+func TestDBSSLModes(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dbCert := ca.issue(t, pkix.Name{CommonName: "postgres"}, "db.internal").pair(t)
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{dbCert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	tests := []struct {
+		name  string
+		cfg   config.DB
+		fails bool
+	}{
+		{name: "require", cfg: config.DB{SSLMode: "require", Host: "127.0.0.1"}},
+		{name: "verify-ca", cfg: config.DB{SSLMode: "verify-ca", Host: "127.0.0.1", SSLRootCert: ca.file(t)}},
+		{name: "verify-ca of another CA", cfg: config.DB{SSLMode: "verify-ca", Host: "127.0.0.1", SSLRootCert: newTestCA(t, "rogue").file(t)}, fails: true},
+		{name: "require with a root cert", cfg: config.DB{SSLMode: "require", Host: "127.0.0.1", SSLRootCert: newTestCA(t, "rogue").file(t)}, fails: true},
+		{name: "verify-full", cfg: config.DB{SSLMode: "verify-full", Host: "db.internal", SSLRootCert: ca.file(t)}},
+		{name: "verify-full of another host", cfg: config.DB{SSLMode: "verify-full", Host: "127.0.0.1", SSLRootCert: ca.file(t)}, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := db.SSLConfig(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			conn, err := tls.Dial("tcp", lis.Addr().String(), c)
+			if err == nil {
+				conn.Close()
+			}
+
+			if tt.fails != (err != nil) {
+				t.Fatalf("Expected failure %t, received %v", tt.fails, err)
+			}
+		})
+	}
+
+	if c, err := db.SSLConfig(config.DB{SSLMode: "disable"}); c != nil || err != nil {
+		t.Fatalf("Expected no TLS when disabled, received %v, %v", c, err)
+	}
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	rpc "stocks-api/support/grpc"
+	server "stocks-api/support/http"
+	"stocks-api/support/tracing"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpan  = "00f067aa0ba902b7"
+	testTraceparent = "00-" + testTraceID + "-" + testParentSpan + "-01"
+)
+
+// tracingFixture records the spans ended during the test.
+func tracingFixture(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func spanNamed(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	t.Fatalf("Expected a span named '%s'", name)
+	return nil
+}
+
+func attributeOf(span sdktrace.ReadOnlySpan, key string) string {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+// TestHttpTracing asserts that the requests are served within a span of their route, continuing the trace of the caller.
+func TestHttpTracing(t *testing.T) {
+	recorder := tracingFixture(t)
+	s := routerFixture(server.Options{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stocks/not-an-id", nil)
+	req.Header.Set("traceparent", testTraceparent)
+
+	rec := httptest.NewRecorder()
+	s.Server.ServeHTTP(rec, req)
+
+	span := spanNamed(t, recorder, "GET /api/v1/stocks/{id}")
+
+	if span.SpanContext().TraceID().String() != testTraceID {
+		t.Fatalf("Expected the trace of the caller, received %s", span.SpanContext().TraceID())
+	}
+
+	if span.Parent().SpanID().String() != testParentSpan || !span.Parent().IsRemote() {
+		t.Fatalf("Expected the span of the caller as the parent, received %s", span.Parent().SpanID())
+	}
+
+	if code := attributeOf(span, "http.status_code"); code != "400" {
+		t.Fatalf("Expected the status code 400, received '%s'", code)
+	}
+
+	if span.Status().Code == otelcodes.Error {
+		t.Fatal("Expected a client error not to fail the span")
+	}
+}
+
+// TestGrpcTracing asserts that the calls are served within a span of their method, continuing the trace of the caller.
+func TestGrpcTracing(t *testing.T) {
+	recorder := tracingFixture(t)
+	interceptor := rpc.UnaryTracingInterceptor()
+
+	var traceID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		traceID = trace.SpanContextFromContext(ctx).TraceID().String()
+		return nil, status.Error(codes.Internal, "boom")
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", testTraceparent))
+	info := &grpc.UnaryServerInfo{FullMethod: "/stocks.StockService/GetStock"}
+
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.Internal {
+		t.Fatalf("Expected the error of the handler, received %v", err)
+	}
+
+	if traceID != testTraceID {
+		t.Fatalf("Expected the handler within the trace of the caller, received %s", traceID)
+	}
+
+	span := spanNamed(t, recorder, "stocks.StockService/GetStock")
+
+	if method := attributeOf(span, "rpc.method"); method != "GetStock" {
+		t.Fatalf("Expected the method GetStock, received '%s'", method)
+	}
+
+	if span.Status().Code != otelcodes.Error {
+		t.Fatal("Expected an internal error to fail the span")
+	}
+}
+
+// TestQueryTracing asserts that the queries of a traced call are its child spans, the others aren't traced.
+func TestQueryTracing(t *testing.T) {
+	recorder := tracingFixture(t)
+	hook := tracing.QueryHook()
+
+	event := &bun.QueryEvent{Query: "SELECT 1"}
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+
+	if len(recorder.Ended()) != 0 {
+		t.Fatalf("Expected the queries outside a trace not to be traced, received %d spans", len(recorder.Ended()))
+	}
+
+	ctx, parent := tracing.Start(context.Background(), "StockService.GetOne")
+	hook.AfterQuery(hook.BeforeQuery(ctx, event), event)
+	parent.End()
+
+	span := spanNamed(t, recorder, "db.SELECT")
+
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("Expected the query as a child span of the call")
+	}
+
+	if statement := attributeOf(span, "db.statement"); statement != "SELECT 1" {
+		t.Fatalf("Expected the statement of the query, received '%s'", statement)
+	}
+}
+
+// TestLogTraceIds asserts that the entries logged with the context of a traced call carry its ids.
+func TestLogTraceIds(t *testing.T) {
+	tracingFixture(t)
+
+	var out bytes.Buffer
+
+	l := logrus.New()
+	l.SetOutput(&out)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	l.AddHook(tracing.LogHook{})
+
+	ctx, span := tracing.Start(context.Background(), "StockService.GetOne")
+	defer span.End()
+
+	l.WithContext(ctx).Error("failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry["trace_id"] != span.SpanContext().TraceID().String() || entry["span_id"] != span.SpanContext().SpanID().String() {
+		t.Fatalf("Expected the ids of the span, received %v", entry)
+	}
+}
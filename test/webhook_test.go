@@ -0,0 +1,256 @@
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"stocks-api/module/entities"
+	"stocks-api/module/repos"
+	"stocks-api/support/db"
+	"stocks-api/support/webhooks"
+)
+
+// TestWebhookSend asserts that deliveries are signed and that failing receivers surface an error.
+func TestWebhookSend(t *testing.T) {
+	const secret = "s3cr3t"
+
+	status := http.StatusOK
+	verified := false
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+
+		verified = webhooks.Verify(secret, timestamp, body, r.Header.Get(webhooks.HeaderSignature)) &&
+			r.Header.Get(webhooks.HeaderEventType) == string(entities.Created)
+
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	sub := &entities.WebhookSubscription{URL: receiver.URL, Secret: secret}
+	stock := &entities.Stock{ID: uuid.New(), Name: "widget", Quantity: 1}
+	event := entities.NewOutboxEvent(entities.Created, stock)
+	sender := webhooks.NewSender(receiver.Client())
+
+	if err := sender.Send(context.Background(), sub, event); err != nil {
+		t.Fatal(err)
+	}
+
+	if !verified {
+		t.Fatal("Expected the receiver to verify the signature")
+	}
+
+	status = http.StatusInternalServerError
+
+	if err := sender.Send(context.Background(), sub, event); err == nil {
+		t.Fatal("Expected an error for a failing receiver")
+	}
+}
+
+// TestWebhookBackoff asserts that the retry delay doubles and is capped.
+func TestWebhookBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  5 * time.Second,
+		2:  10 * time.Second,
+		4:  40 * time.Second,
+		30: time.Hour,
+	}
+
+	for attempts, expected := range cases {
+		if got := webhooks.Backoff(attempts); got != expected {
+			t.Fatalf("Expected backoff %s after %d attempts, received %s", expected, attempts, got)
+		}
+	}
+}
+
+// TestWebhookAccepts asserts the event type filtering of the subscriptions.
+func TestWebhookAccepts(t *testing.T) {
+	all := &entities.WebhookSubscription{}
+	deletes := &entities.WebhookSubscription{EventTypes: []string{string(entities.Deleted)}}
+
+	if !all.Accepts(entities.Updated) || !deletes.Accepts(entities.Deleted) || deletes.Accepts(entities.Created) {
+		t.Fatal("Unexpected event type filtering")
+	}
+}
+
+// fakeDeliveryStore hands out the due deliveries once, recording the delivered ones.
+type fakeDeliveryStore struct {
+	mu         sync.Mutex
+	due        []*entities.WebhookDelivery
+	delivered  int
+	claimLimit int
+}
+
+func (f *fakeDeliveryStore) FanOut(context.Context, int) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeDeliveryStore) ClaimDue(_ context.Context, limit int, _ time.Duration) ([]*entities.WebhookDelivery, error) {
+	f.claimLimit = limit
+
+	if len(f.due) > limit {
+		return f.due[:limit], nil
+	}
+
+	return f.due, nil
+}
+
+func (f *fakeDeliveryStore) Delivered(context.Context, *entities.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.delivered++
+
+	return nil
+}
+
+func (f *fakeDeliveryStore) Reschedule(context.Context, *entities.WebhookDelivery) error {
+	return nil
+}
+
+func (f *fakeDeliveryStore) DeadLetter(context.Context, *entities.WebhookDelivery) error {
+	return nil
+}
+
+func (f *fakeDeliveryStore) Purge(context.Context, time.Time, bool) (int64, error) {
+	return 0, nil
+}
+
+// TestWebhookConcurrentDelivery asserts that the claimed deliveries are sent at once, rather than one after the other.
+func TestWebhookConcurrentDelivery(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer receiver.Close()
+
+	sub := &entities.WebhookSubscription{URL: receiver.URL, Secret: "s3cr3t"}
+	store := &fakeDeliveryStore{}
+
+	for i := 0; i < 100; i++ {
+		event := entities.NewOutboxEvent(entities.Created, &entities.Stock{ID: uuid.New(), Name: "widget"})
+		store.due = append(store.due, &entities.WebhookDelivery{ID: uuid.New(), Subscription: sub, Event: event})
+	}
+
+	dispatcher := webhooks.NewDispatcher(logrus.New(), store, webhooks.NewSender(receiver.Client()), false)
+
+	started := time.Now()
+	dispatcher.Deliver(context.Background())
+
+	if store.delivered != store.claimLimit || store.claimLimit == 0 || store.claimLimit >= len(store.due) {
+		t.Fatalf("Expected the %d claimed deliveries out of %d to be delivered, received %d", store.claimLimit, len(store.due), store.delivered)
+	}
+
+	if elapsed := time.Since(started); elapsed > time.Duration(store.claimLimit)*100*time.Millisecond/2 {
+		t.Fatalf("Expected the deliveries to be sent concurrently, took %s", elapsed)
+	}
+}
+
+// TestWebhookPurge asserts that only the outbox events dispatched before the retention, and delivered since, are purged.
+func TestWebhookPurge(t *testing.T) {
+	instance := dbFixture(t)
+	ctx := tenantFixture()
+	repo := repos.NewWebhookRepo(logrus.New(), instance)
+
+	stock := &entities.Stock{ID: uuid.New(), Name: "widget", TenantID: entities.TenantFrom(ctx)}
+	week := 7 * 24 * time.Hour
+
+	delivered := entities.NewOutboxEvent(entities.Created, stock)
+	delivered.DispatchedAt = time.Now().Add(-2 * week)
+
+	pending := entities.NewOutboxEvent(entities.Updated, stock)
+	pending.DispatchedAt = time.Now().Add(-2 * week)
+
+	recent := entities.NewOutboxEvent(entities.Deleted, stock)
+	recent.DispatchedAt = time.Now()
+
+	sub := &entities.WebhookSubscription{URL: "https://example.com/hook", Secret: "s3cr3t"}
+	if err := repo.InsertOne(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery := &entities.WebhookDelivery{ID: uuid.New(), SubscriptionID: sub.ID, EventID: pending.ID}
+
+	err := settingTx(ctx, instance, "app.all_tenants", "on", func(tx bun.Tx) error {
+		for _, ev := range []*entities.OutboxEvent{delivered, pending, recent} {
+			if _, err := tx.NewInsert().Model(ev).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.NewInsert().Model(delivery).Exec(ctx)
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Purge(ctx, time.Now().Add(-week), false); err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutbox(t, ctx, instance, map[uuid.UUID]bool{delivered.ID: false, pending.ID: true, recent.ID: true})
+}
+
+// TestWebhookPurgeRelayBehind asserts that the outbox events delivered to every subscription are kept until the broker
+// relay published them too.
+func TestWebhookPurgeRelayBehind(t *testing.T) {
+	instance := dbFixture(t)
+	ctx := tenantFixture()
+	repo := repos.NewWebhookRepo(logrus.New(), instance)
+
+	stock := &entities.Stock{ID: uuid.New(), Name: "widget", TenantID: entities.TenantFrom(ctx)}
+	week := 7 * 24 * time.Hour
+
+	published := entities.NewOutboxEvent(entities.Created, stock)
+	published.DispatchedAt = time.Now().Add(-2 * week)
+	published.PublishedAt = time.Now().Add(-2 * week)
+
+	unpublished := entities.NewOutboxEvent(entities.Updated, stock)
+	unpublished.DispatchedAt = time.Now().Add(-2 * week)
+
+	err := settingTx(ctx, instance, "app.all_tenants", "on", func(tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(&[]*entities.OutboxEvent{published, unpublished}).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Purge(ctx, time.Now().Add(-week), true); err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutbox(t, ctx, instance, map[uuid.UUID]bool{published.ID: false, unpublished.ID: true})
+}
+
+// assertOutbox asserts which of the outbox events are kept.
+func assertOutbox(t *testing.T, ctx context.Context, instance *db.Instance, kept map[uuid.UUID]bool) {
+	t.Helper()
+
+	for id, expected := range kept {
+		var exists bool
+
+		err := settingTx(ctx, instance, "app.all_tenants", "on", func(tx bun.Tx) (err error) {
+			exists, err = tx.NewSelect().Model(new(entities.OutboxEvent)).Where("id = ?", id).Exists(ctx)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exists != expected {
+			t.Fatalf("Expected outbox event %s to be kept: %t, received %t", id, expected, exists)
+		}
+	}
+}